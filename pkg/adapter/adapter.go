@@ -208,6 +208,23 @@ func (l listener) processRes(processEvent bool, provider provider.Interface, log
 	return nil, logger, fmt.Errorf("skipping event")
 }
 
+// gitProviders returns the git providers an event can come from, in the order
+// they detect it: gitea sets the GitHub headers as well and is skipped by the
+// GitHub detection.
+func (l listener) gitProviders() []provider.Interface {
+	gitHub := github.New()
+	gitHub.Run = l.run
+	return []provider.Interface{
+		gitHub,
+		&gitea.Provider{},
+		&bitbucketserver.Provider{},
+		&gitlab.Provider{},
+		&bitbucketcloud.Provider{},
+	}
+}
+
+// detectProvider dispatches the event to the first git provider detecting it
+// from its headers and payload.
 func (l listener) detectProvider(req *http.Request, reqBody string) (provider.Interface, *zap.SugaredLogger, error) {
 	log := *l.logger
 
@@ -217,39 +234,13 @@ func (l listener) detectProvider(req *http.Request, reqBody string) (provider.In
 		return nil, &log, fmt.Errorf("invalid event body format: %w", err)
 	}
 
-	gitHub := github.New()
-	gitHub.Run = l.run
-	isGH, processReq, logger, reason, err := gitHub.Detect(req, reqBody, &log)
-	if isGH {
-		return l.processRes(processReq, gitHub, logger, reason, err)
-	}
-
-	zegitea := &gitea.Provider{}
-	isGitea, processReq, logger, reason, err := zegitea.Detect(req, reqBody, &log)
-	if isGitea {
-		return l.processRes(processReq, zegitea, logger, reason, err)
-	}
-
-	bitServer := &bitbucketserver.Provider{}
-	isBitServer, processReq, logger, reason, err := bitServer.Detect(req, reqBody, &log)
-	if isBitServer {
-		return l.processRes(processReq, bitServer, logger, reason, err)
-	}
-
-	gitLab := &gitlab.Provider{}
-	isGitlab, processReq, logger, reason, err := gitLab.Detect(req, reqBody, &log)
-	if isGitlab {
-		return l.processRes(processReq, gitLab, logger, reason, err)
-	}
-
-	bitCloud := &bitbucketcloud.Provider{}
-
-	isBitCloud, processReq, logger, reason, err := bitCloud.Detect(req, reqBody, &log)
-	if isBitCloud {
-		return l.processRes(processReq, bitCloud, logger, reason, err)
+	for _, gitProvider := range l.gitProviders() {
+		detected, processReq, logger, reason, err := gitProvider.Detect(req, reqBody, &log)
+		if detected {
+			return l.processRes(processReq, gitProvider, logger, reason, err)
+		}
 	}
-
-	return l.processRes(false, nil, logger, "", fmt.Errorf("no supported Git provider has been detected"))
+	return l.processRes(false, nil, &log, "", fmt.Errorf("no supported Git provider has been detected"))
 }
 
 func (l listener) writeResponse(response http.ResponseWriter, statusCode int, message string) {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -164,11 +165,13 @@ func TestWhichProvider(t *testing.T) {
 	logger, _ := logger.GetLogger()
 	l := listener{
 		logger: logger,
+		run:    params.New(),
 	}
 	tests := []struct {
 		name          string
 		event         interface{}
 		header        http.Header
+		wantProvider  string
 		wantErrString string
 	}{
 		{
@@ -180,6 +183,34 @@ func TestWhichProvider(t *testing.T) {
 			event: github.PushEvent{
 				Pusher: &github.CommitAuthor{Name: github.String("user")},
 			},
+			wantProvider: "*github.Provider",
+		},
+		{
+			name: "gitea event with the github headers",
+			header: map[string][]string{
+				"X-Github-Event":     {"push"},
+				"X-Gitea-Event-Type": {"push"},
+			},
+			event: map[string]any{
+				"pusher": map[string]string{"login": "user"},
+			},
+			wantProvider: "*gitea.Provider",
+		},
+		{
+			name: "gitlab event",
+			header: map[string][]string{
+				"X-Gitlab-Event": {"Push Hook"},
+			},
+			event:        map[string]string{"object_kind": "push"},
+			wantProvider: "*gitlab.Provider",
+		},
+		{
+			name: "azure devops service hook",
+			header: map[string][]string{
+				"X-Vss-Activityid": {"abcd"},
+			},
+			event:         map[string]string{"publisherId": "tfs", "eventType": "git.push"},
+			wantErrString: "no supported Git provider has been detected",
 		},
 		{
 			name: "some random event",
@@ -201,12 +232,13 @@ func TestWhichProvider(t *testing.T) {
 				Header: tt.header,
 			}
 
-			_, _, err = l.detectProvider(req, string(jeez))
+			gitProvider, _, err := l.detectProvider(req, string(jeez))
 			if tt.wantErrString != "" {
 				assert.ErrorContains(t, err, tt.wantErrString)
 				return
 			}
 			assert.NilError(t, err)
+			assert.Equal(t, fmt.Sprintf("%T", gitProvider), tt.wantProvider)
 		})
 	}
 }
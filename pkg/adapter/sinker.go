@@ -45,7 +45,7 @@ func (s *sinker) processEvent(ctx context.Context, request *http.Request) error
 	if s.event.EventType == "incoming" {
 		if request.Header.Get("X-GitHub-Enterprise-Host") != "" {
 			s.event.Provider.URL = request.Header.Get("X-GitHub-Enterprise-Host")
-			s.event.GHEURL = request.Header.Get("X-GitHub-Enterprise-Host")
+			s.event.GHEHost = request.Header.Get("X-GitHub-Enterprise-Host")
 		}
 	} else {
 		if err := s.processEventPayload(ctx, request); err != nil {
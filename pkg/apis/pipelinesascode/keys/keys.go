@@ -54,6 +54,33 @@ const (
 	MaxKeepRuns     = pipelinesascode.GroupName + "/max-keep-runs"
 	LogURL          = pipelinesascode.GroupName + "/log-url"
 	ExecutionOrder  = pipelinesascode.GroupName + "/execution-order"
+	// AggregatedCheckRunID is the id of the check run a PipelineRun is
+	// reported in with the other PipelineRuns of its commit, when the commit
+	// is close to the limit of check runs of GitHub.
+	AggregatedCheckRunID = pipelinesascode.GroupName + "/aggregated-check-run-id"
+	// StatusFailuresOnly only shows the failed TaskRuns in the status of a failed PipelineRun.
+	StatusFailuresOnly = pipelinesascode.GroupName + "/status-failures-only"
+	// StatusDurationBudget is the duration a PipelineRun is expected to run
+	// within, the status shows whether it did.
+	StatusDurationBudget = pipelinesascode.GroupName + "/status-duration-budget"
+	// StatusCounts shows the counts of succeeded, failed and skipped tasks at
+	// the top of the status.
+	StatusCounts = pipelinesascode.GroupName + "/status-counts"
+	// StatusParameters shows the parameters of the PipelineRun in the summary
+	// of its status.
+	StatusParameters = pipelinesascode.GroupName + "/status-parameters"
+	// StatusIdempotencyToken is the idempotency token of the last status
+	// posted for the PipelineRun and the time it has been posted at.
+	StatusIdempotencyToken = pipelinesascode.GroupName + "/status-idempotency-token"
+	// Environment is the GitHub environment a PipelineRun deploys to, its
+	// status is reported as a deployment of the environment too.
+	Environment = pipelinesascode.GroupName + "/environment"
+	// ApprovalGate is the manual approval gate the PipelineRun has been
+	// reported waiting for.
+	ApprovalGate = pipelinesascode.GroupName + "/approval-gate"
+	// StatusGistID is the id of the gist the summary of the status of the
+	// PipelineRun is published to, the gist is edited on the next statuses.
+	StatusGistID = pipelinesascode.GroupName + "/status-gist-id"
 	// PublicGithubAPIURL default is "https://api.github.com" but it can be overridden by X-GitHub-Enterprise-Host header.
 	PublicGithubAPIURL = "https://api.github.com"
 	// InstallationURL gives us the Installation ID for the GitHub Application.
@@ -83,6 +83,20 @@ type Settings struct {
 	GithubAppTokenScopeRepos []string `json:"github_app_token_scope_repos,omitempty"`
 	PipelineRunProvenance    string   `json:"pipelinerun_provenance,omitempty"`
 	Policy                   *Policy  `json:"policy,omitempty"`
+	// ApplicationName overrides the application name of the controller
+	// used as the name of the statuses of the repository.
+	ApplicationName string `json:"application_name,omitempty"`
+	// SlackWebhookSecret is the secret with the URL of a Slack incoming
+	// webhook notified of the completed PipelineRuns.
+	SlackWebhookSecret *Secret `json:"slack_webhook_secret,omitempty"`
+	// SkipDraft overrides the skip-draft setting of the controller for the
+	// pull requests of the repository when set.
+	SkipDraft *bool `json:"skip_draft,omitempty"`
+	// DeploymentEnvironments are the globs of the environments the
+	// PipelineRuns of the repository are allowed to report a deployment to,
+	// overriding the status-deployment-environments setting of the
+	// controller.
+	DeploymentEnvironments []string `json:"deployment_environments,omitempty"`
 }
 
 type Policy struct {
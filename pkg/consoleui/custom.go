@@ -111,6 +111,12 @@ func (o *CustomConsole) TaskLogURL(pr *tektonv1.PipelineRun, taskRunStatus *tekt
 	return o.generateURL(o.Info.Pac.CustomConsolePRTaskLog, nm)
 }
 
+// PodEventsURL links to the task logs, we don't have a setting for a view of
+// the pod events on custom consoles.
+func (o *CustomConsole) PodEventsURL(pr *tektonv1.PipelineRun, taskRunStatus *tektonv1.PipelineRunTaskRunStatus) string {
+	return o.TaskLogURL(pr, taskRunStatus)
+}
+
 func (o *CustomConsole) UI(_ context.Context, _ dynamic.Interface) error {
 	return nil
 }
@@ -13,6 +13,7 @@ const consoleIsnotConfiguredURL = "https://dashboard.is.not.configured"
 type Interface interface {
 	DetailURL(pr *tektonv1.PipelineRun) string
 	TaskLogURL(pr *tektonv1.PipelineRun, taskRunStatusstatus *tektonv1.PipelineRunTaskRunStatus) string
+	PodEventsURL(pr *tektonv1.PipelineRun, taskRunStatusstatus *tektonv1.PipelineRunTaskRunStatus) string
 	NamespaceURL(pr *tektonv1.PipelineRun) string
 	UI(ctx context.Context, kdyn dynamic.Interface) error
 	URL() string
@@ -34,6 +35,10 @@ func (f FallBackConsole) TaskLogURL(_ *tektonv1.PipelineRun, _ *tektonv1.Pipelin
 	return consoleIsnotConfiguredURL
 }
 
+func (f FallBackConsole) PodEventsURL(_ *tektonv1.PipelineRun, _ *tektonv1.PipelineRunTaskRunStatus) string {
+	return consoleIsnotConfiguredURL
+}
+
 func (f FallBackConsole) NamespaceURL(_ *tektonv1.PipelineRun) string {
 	return consoleIsnotConfiguredURL
 }
@@ -0,0 +1,73 @@
+package consoleui
+
+import (
+	"bytes"
+	"net/url"
+	"text/template"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// LogURLTemplate renders the log URLs of the PipelineRuns and the TaskRuns
+// from the log-url-template setting instead of the console, i.e: to point
+// them to Grafana/Loki. Everything else is handled by the wrapped console.
+type LogURLTemplate struct {
+	Interface
+	Info *info.Info
+}
+
+type logURLParams struct {
+	Namespace       string
+	PipelineRunName string
+	TaskRunName     string
+}
+
+// render returns the log URL from the template, or the fallback when the
+// template cannot be rendered to a proper URL.
+func (o *LogURLTemplate) render(params logURLParams, fallback string) string {
+	tmpl, err := template.New("log-url").Option("missingkey=error").Parse(o.Info.Pac.LogURLTemplate)
+	if err != nil {
+		return fallback
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, params); err != nil {
+		return fallback
+	}
+	if _, err := url.ParseRequestURI(out.String()); err != nil {
+		return fallback
+	}
+	return out.String()
+}
+
+func (o *LogURLTemplate) DetailURL(pr *tektonv1.PipelineRun) string {
+	if o.Info.Pac.LogURLTemplate == "" {
+		return o.Interface.DetailURL(pr)
+	}
+	return o.render(logURLParams{
+		Namespace:       pr.GetNamespace(),
+		PipelineRunName: pr.GetName(),
+	}, o.Interface.DetailURL(pr))
+}
+
+func (o *LogURLTemplate) TaskLogURL(pr *tektonv1.PipelineRun, taskRunStatus *tektonv1.PipelineRunTaskRunStatus) string {
+	if o.Info.Pac.LogURLTemplate == "" {
+		return o.Interface.TaskLogURL(pr, taskRunStatus)
+	}
+	return o.render(logURLParams{
+		Namespace:       pr.GetNamespace(),
+		PipelineRunName: pr.GetName(),
+		TaskRunName:     taskRunName(pr, taskRunStatus.PipelineTaskName),
+	}, o.Interface.TaskLogURL(pr, taskRunStatus))
+}
+
+// taskRunName returns the name of the TaskRun of a pipeline task from the
+// child references of the PipelineRun.
+func taskRunName(pr *tektonv1.PipelineRun, pipelineTaskName string) string {
+	for _, child := range pr.Status.ChildReferences {
+		if child.PipelineTaskName == pipelineTaskName {
+			return child.Name
+		}
+	}
+	return ""
+}
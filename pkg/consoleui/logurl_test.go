@@ -0,0 +1,69 @@
+package consoleui
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLogURLTemplate(t *testing.T) {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "pr",
+		},
+		Status: tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				ChildReferences: []tektonv1.ChildStatusReference{
+					{Name: "pr-build", PipelineTaskName: "build"},
+				},
+			},
+		},
+	}
+	trStatus := &tektonv1.PipelineRunTaskRunStatus{PipelineTaskName: "build"}
+	dashboard := &TektonDashboard{BaseURL: "https://dashboard"}
+
+	tests := []struct {
+		name          string
+		template      string
+		wantDetailURL string
+		wantTaskURL   string
+	}{
+		{
+			name:          "no template",
+			wantDetailURL: "https://dashboard/#/namespaces/ns/pipelineruns/pr",
+			wantTaskURL:   "https://dashboard/#/namespaces/ns/pipelineruns/pr?pipelineTask=build",
+		},
+		{
+			name:          "template",
+			template:      "https://grafana/explore?ns={{.Namespace}}&pr={{.PipelineRunName}}&tr={{.TaskRunName}}",
+			wantDetailURL: "https://grafana/explore?ns=ns&pr=pr&tr=",
+			wantTaskURL:   "https://grafana/explore?ns=ns&pr=pr&tr=pr-build",
+		},
+		{
+			name:          "invalid template falls back to the console",
+			template:      "https://grafana/{{.Unknown}}",
+			wantDetailURL: "https://dashboard/#/namespaces/ns/pipelineruns/pr",
+			wantTaskURL:   "https://dashboard/#/namespaces/ns/pipelineruns/pr?pipelineTask=build",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LogURLTemplate{
+				Interface: dashboard,
+				Info: &info.Info{
+					Pac: &info.PacOpts{
+						Settings: &settings.Settings{LogURLTemplate: tt.template},
+					},
+				},
+			}
+			assert.Equal(t, c.DetailURL(pr), tt.wantDetailURL)
+			assert.Equal(t, c.TaskLogURL(pr, trStatus), tt.wantTaskURL)
+			assert.Equal(t, c.URL(), dashboard.URL())
+		})
+	}
+}
@@ -16,6 +16,7 @@ const (
 	openShiftPipelineNamespaceViewURL = "https://%s/pipelines/ns/%s/pipeline-runs"
 	openShiftPipelineDetailViewURL    = "https://%s/k8s/ns/%s/tekton.dev~v1~PipelineRun/%s"
 	openShiftPipelineTaskLogURL       = "%s/logs/%s"
+	openShiftPodEventsURL             = "https://%s/k8s/ns/%s/pods/%s/events"
 	openShiftRouteGroup               = "route.openshift.io"
 	openShiftRouteVersion             = "v1"
 	openShiftRouteResource            = "routes"
@@ -45,6 +46,15 @@ func (o *OpenshiftConsole) TaskLogURL(pr *tektonv1.PipelineRun, taskRunStatus *t
 	return fmt.Sprintf(openShiftPipelineTaskLogURL, o.DetailURL(pr), taskRunStatus.PipelineTaskName)
 }
 
+// PodEventsURL links to the events of the TaskRun pod, or to the task logs if
+// the TaskRun has no pod.
+func (o *OpenshiftConsole) PodEventsURL(pr *tektonv1.PipelineRun, taskRunStatus *tektonv1.PipelineRunTaskRunStatus) string {
+	if taskRunStatus.Status == nil || taskRunStatus.Status.PodName == "" {
+		return o.TaskLogURL(pr, taskRunStatus)
+	}
+	return fmt.Sprintf(openShiftPodEventsURL, o.host, pr.GetNamespace(), taskRunStatus.Status.PodName)
+}
+
 func (o *OpenshiftConsole) NamespaceURL(pr *tektonv1.PipelineRun) string {
 	return fmt.Sprintf(openShiftPipelineNamespaceViewURL, o.host, pr.GetNamespace())
 }
@@ -30,6 +30,12 @@ func (t *TektonDashboard) TaskLogURL(pr *tektonv1.PipelineRun, taskRunStatus *te
 	return fmt.Sprintf("%s?pipelineTask=%s", t.DetailURL(pr), taskRunStatus.PipelineTaskName)
 }
 
+// PodEventsURL links to the TaskRun in the dashboard, which shows the pod
+// status, since there is no view of the pod events.
+func (t *TektonDashboard) PodEventsURL(pr *tektonv1.PipelineRun, taskRunStatus *tektonv1.PipelineRunTaskRunStatus) string {
+	return t.TaskLogURL(pr, taskRunStatus)
+}
+
 func (t *TektonDashboard) URL() string {
 	return t.BaseURL
 }
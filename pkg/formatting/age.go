@@ -1,6 +1,10 @@
 package formatting
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/hako/durafmt"
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
@@ -25,6 +29,52 @@ func Duration(t1, t2 *metav1.Time) string {
 	return durafmt.ParseShort(dur).String()
 }
 
+// TaskDuration returns a concise duration of a TaskRun for the status, i.e:
+// "1h 2m" or "45s", with the sub-second precision dropped over a minute. A
+// TaskRun not completed yet is "running", or "pending" when not started
+// either, and a TaskRun completed without having started is "—".
+func TaskDuration(start, completion *metav1.Time) string {
+	switch {
+	case start.IsZero() && completion.IsZero():
+		return "pending"
+	case start.IsZero():
+		return "—"
+	case completion.IsZero():
+		return "running"
+	}
+	return HumanDuration(completion.Sub(start.Time))
+}
+
+// HumanDuration formats a duration with its two most significant units, i.e:
+// "1h 2m", "2m 5s" or "1.5s". Negative durations, i.e: from a clock skew,
+// are clamped to 0.
+func HumanDuration(dur time.Duration) string {
+	if dur <= 0 {
+		return "0s"
+	}
+	if short := dur.Round(100 * time.Millisecond); short < time.Minute {
+		return short.String()
+	}
+
+	dur = dur.Round(time.Second)
+	hours := int(dur / time.Hour)
+	minutes := int(dur % time.Hour / time.Minute)
+	seconds := int(dur % time.Minute / time.Second)
+	parts := []string{}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+		if minutes > 0 {
+			parts = append(parts, fmt.Sprintf("%dm", minutes))
+		}
+	} else {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+		if seconds > 0 {
+			parts = append(parts, fmt.Sprintf("%ds", seconds))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 // PRDuration calculates the duration of a repository run, given its status.
 // It takes a RepositoryRunStatus object as input.
 // It returns a string with the duration of the run, or nonAttributedStr if the run has not started or completed.
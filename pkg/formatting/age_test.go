@@ -130,3 +130,79 @@ func TestPRDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskDuration(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	start := &metav1.Time{Time: clock.Now()}
+	after := func(dur time.Duration) *metav1.Time {
+		return &metav1.Time{Time: clock.Now().Add(dur)}
+	}
+	tests := []struct {
+		name       string
+		start      *metav1.Time
+		completion *metav1.Time
+		want       string
+	}{
+		{
+			name: "not started",
+			want: "pending",
+		},
+		{
+			name:       "completed without starting",
+			completion: after(time.Minute),
+			want:       "—",
+		},
+		{
+			name:  "running",
+			start: start,
+			want:  "running",
+		},
+		{
+			name:       "seconds",
+			start:      start,
+			completion: after(45 * time.Second),
+			want:       "45s",
+		},
+		{
+			name:       "sub-second precision under a minute",
+			start:      start,
+			completion: after(1500 * time.Millisecond),
+			want:       "1.5s",
+		},
+		{
+			name:       "rounded up to a minute",
+			start:      start,
+			completion: after(59960 * time.Millisecond),
+			want:       "1m",
+		},
+		{
+			name:       "minutes and seconds",
+			start:      start,
+			completion: after(2*time.Minute + 5*time.Second + 400*time.Millisecond),
+			want:       "2m 5s",
+		},
+		{
+			name:       "hours and minutes",
+			start:      start,
+			completion: after(time.Hour + 2*time.Minute + 3456*time.Millisecond),
+			want:       "1h 2m",
+		},
+		{
+			name:       "whole hours",
+			start:      start,
+			completion: after(2*time.Hour + 10*time.Second),
+			want:       "2h",
+		},
+		{
+			name:       "clock skew",
+			start:      start,
+			completion: after(-5 * time.Second),
+			want:       "0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, TaskDuration(tt.start, tt.completion), tt.want)
+		})
+	}
+}
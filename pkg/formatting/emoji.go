@@ -2,6 +2,7 @@ package formatting
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	knative1 "knative.dev/pkg/apis/duck/v1"
@@ -9,23 +10,45 @@ import (
 
 const nonAttributedStr = "---"
 
+// Classifications of the conditions of a TaskRun.
+const (
+	conditionFailed    = "Failed"
+	conditionSucceeded = "Succeeded"
+	conditionRunning   = "Running"
+)
+
+// classifyCondition returns the classification of the conditions, empty when
+// there is none.
+func classifyCondition(c knative1.Conditions) string {
+	if len(c) == 0 {
+		return ""
+	}
+	switch c[0].Status {
+	case corev1.ConditionFalse:
+		return conditionFailed
+	case corev1.ConditionTrue:
+		return conditionSucceeded
+	case corev1.ConditionUnknown:
+		return conditionRunning
+	}
+	return ""
+}
+
 // formatCondition knative formatcondition with emoji or not.
 func formatCondition(c knative1.Conditions, skipemoji bool) string {
-	var status, emoji string
+	var emoji string
 	if len(c) == 0 {
 		return nonAttributedStr
 	}
 
-	switch c[0].Status {
-	case corev1.ConditionFalse:
+	status := classifyCondition(c)
+	switch status {
+	case conditionFailed:
 		emoji = "❌"
-		status = "Failed"
-	case corev1.ConditionTrue:
+	case conditionSucceeded:
 		emoji = "✅"
-		status = "Succeeded"
-	case corev1.ConditionUnknown:
+	case conditionRunning:
 		emoji = "🏃"
-		status = "Running"
 	}
 	if !skipemoji {
 		status = fmt.Sprintf("%s %s", emoji, status)
@@ -41,3 +64,52 @@ func ConditionEmoji(c knative1.Conditions) string {
 func ConditionSad(c knative1.Conditions) string {
 	return formatCondition(c, true)
 }
+
+// ConditionCounts are how many TaskRuns have succeeded, failed or are still
+// running, classified as formatCondition does, and how many tasks have been
+// skipped.
+type ConditionCounts struct {
+	Succeeded, Failed, Running, Skipped int
+}
+
+// CountConditions counts the conditions of the TaskRuns, the skipped tasks
+// have no TaskRun and are counted by the caller.
+func CountConditions(conditions []knative1.Conditions) ConditionCounts {
+	counts := ConditionCounts{}
+	for _, c := range conditions {
+		switch classifyCondition(c) {
+		case conditionSucceeded:
+			counts.Succeeded++
+		case conditionFailed:
+			counts.Failed++
+		case conditionRunning:
+			counts.Running++
+		}
+	}
+	return counts
+}
+
+// Badge renders the counts on a single line, i.e: "✅ 12  ❌ 1  ⏭ 2", the
+// zero counts are left out.
+func (c ConditionCounts) Badge(skipemoji bool) string {
+	parts := []string{}
+	for _, count := range []struct {
+		number       int
+		emoji, label string
+	}{
+		{c.Succeeded, "✅", "succeeded"},
+		{c.Failed, "❌", "failed"},
+		{c.Running, "🏃", "running"},
+		{c.Skipped, "⏭", "skipped"},
+	} {
+		if count.number == 0 {
+			continue
+		}
+		if skipemoji {
+			parts = append(parts, fmt.Sprintf("%d %s", count.number, count.label))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s %d", count.emoji, count.number))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
@@ -61,3 +61,45 @@ func TestSkipEmoji(t *testing.T) {
 		kv1.Conditions{{Status: corev1.ConditionTrue}})
 	assert.Assert(t, !strings.Contains(got, "✅"))
 }
+
+func TestCountConditions(t *testing.T) {
+	succeeded := kv1.Conditions{{Status: corev1.ConditionTrue}}
+	failed := kv1.Conditions{{Status: corev1.ConditionFalse}}
+	running := kv1.Conditions{{Status: corev1.ConditionUnknown}}
+	tests := []struct {
+		name       string
+		conditions []kv1.Conditions
+		skipped    int
+		want       ConditionCounts
+		badge      string
+		badgeSad   string
+	}{
+		{
+			name:       "all succeeded",
+			conditions: []kv1.Conditions{succeeded, succeeded, succeeded},
+			want:       ConditionCounts{Succeeded: 3},
+			badge:      "✅ 3",
+			badgeSad:   "3 succeeded",
+		},
+		{
+			name:       "mixed",
+			conditions: []kv1.Conditions{succeeded, failed, succeeded, running, {}},
+			skipped:    2,
+			want:       ConditionCounts{Succeeded: 2, Failed: 1, Running: 1, Skipped: 2},
+			badge:      "✅ 2  ❌ 1  🏃 1  ⏭ 2",
+			badgeSad:   "2 succeeded  1 failed  1 running  2 skipped",
+		},
+		{
+			name: "none",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CountConditions(tt.conditions)
+			got.Skipped = tt.skipped
+			assert.DeepEqual(t, got, tt.want)
+			assert.Equal(t, got.Badge(false), tt.badge)
+			assert.Equal(t, got.Badge(true), tt.badgeSad)
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package formatting
 
 import (
+	"sort"
+
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
 )
 
 // PipelineRunStatus return status of PR  success failed or skipped.
@@ -15,3 +18,43 @@ func PipelineRunStatus(pr *tektonv1.PipelineRun) string {
 	}
 	return "success"
 }
+
+// ConclusionTitle returns the title of a status with the conclusion, as shown
+// on the check runs, the conclusion itself when it has no title.
+func ConclusionTitle(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return "Success"
+	case "failure":
+		return "Failed"
+	case "pending":
+		return "Pending"
+	case "neutral":
+		return "Unknown"
+	case "skipped":
+		return "Skipped"
+	case "cancelled":
+		return "Cancelled"
+	case "timed_out":
+		return "Timed out"
+	case "action_required":
+		return "Waiting for approval"
+	}
+	return conclusion
+}
+
+// FailedTaskNames returns the sorted names of the pipeline tasks of the
+// TaskRuns which have failed.
+func FailedTaskNames(taskRunStatuses map[string]*tektonv1.PipelineRunTaskRunStatus) []string {
+	names := []string{}
+	for _, taskrunStatus := range taskRunStatuses {
+		if taskrunStatus == nil || taskrunStatus.Status == nil {
+			continue
+		}
+		if cond := taskrunStatus.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.IsFalse() {
+			names = append(names, taskrunStatus.PipelineTaskName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
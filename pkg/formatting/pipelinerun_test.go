@@ -6,6 +6,7 @@ import (
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	knativeapi "knative.dev/pkg/apis"
 	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
@@ -64,3 +65,32 @@ func TestPipelineRunStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestConclusionTitle(t *testing.T) {
+	assert.Equal(t, ConclusionTitle("success"), "Success")
+	assert.Equal(t, ConclusionTitle("failure"), "Failed")
+	assert.Equal(t, ConclusionTitle("timed_out"), "Timed out")
+	assert.Equal(t, ConclusionTitle("action_required"), "Waiting for approval")
+	assert.Equal(t, ConclusionTitle("stale"), "stale")
+}
+
+func TestFailedTaskNames(t *testing.T) {
+	taskRun := func(name string, condition corev1.ConditionStatus) *tektonv1.PipelineRunTaskRunStatus {
+		return &tektonv1.PipelineRunTaskRunStatus{
+			PipelineTaskName: name,
+			Status: &tektonv1.TaskRunStatus{Status: knativeduckv1.Status{Conditions: knativeduckv1.Conditions{
+				{Type: knativeapi.ConditionSucceeded, Status: condition},
+			}}},
+		}
+	}
+	statuses := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"pr-unit":  taskRun("unit", corev1.ConditionFalse),
+		"pr-build": taskRun("build", corev1.ConditionTrue),
+		"pr-lint":  taskRun("lint", corev1.ConditionFalse),
+		"pr-e2e":   taskRun("e2e", corev1.ConditionUnknown),
+		"pr-nil":   nil,
+		"pr-none":  {PipelineTaskName: "none"},
+	}
+	assert.DeepEqual(t, FailedTaskNames(statuses), []string{"lint", "unit"})
+	assert.DeepEqual(t, FailedTaskNames(nil), []string{})
+}
@@ -0,0 +1,62 @@
+package formatting
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const tableEnd = "\n</table>"
+
+// TruncateText truncates text so it fits within maxSize bytes, a maxSize lower
+// or equal to 0 means no limit. The text is cut at a row of the task status
+// table, or else at a line, and a link to the full logs at logURL is appended.
+// When maxSize is too small to fit the link the text is only cut.
+func TruncateText(text string, maxSize int, logURL string) string {
+	if maxSize <= 0 || len(text) <= maxSize {
+		return text
+	}
+	marker := "\n\n…output truncated, see full logs"
+	if logURL != "" {
+		marker = fmt.Sprintf("\n\n…output truncated, see [full logs](%s)", logURL)
+	}
+	limit := maxSize - len(marker) - len(tableEnd)
+	if limit <= 0 {
+		return text[:runeStart(text, maxSize)]
+	}
+	head := text[:limit]
+
+	// cut at the last full row if we are in the middle of a table
+	if tableStart := strings.LastIndex(head, "<table>"); tableStart > strings.LastIndex(head, "</table>") {
+		if row := strings.LastIndex(head, "\n<tr>"); row > tableStart {
+			return head[:row] + tableEnd + marker
+		}
+		return strings.TrimRight(head[:tableStart], "\n") + marker
+	}
+	if line := strings.LastIndex(head, "\n"); line > 0 {
+		return head[:line] + marker
+	}
+	return text[:runeStart(text, limit)] + marker
+}
+
+// runeStart returns the last index at or before cut which doesn't split a
+// multibyte character of text.
+func runeStart(text string, cut int) int {
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// TruncateRunes truncates text to maxLength characters, ending with an
+// ellipsis when it has been truncated.
+func TruncateRunes(text string, maxLength int) (string, bool) {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text, false
+	}
+	if maxLength <= 0 {
+		return "", true
+	}
+	runes := []rune(text)
+	return strings.TrimRight(string(runes[:maxLength-1]), " ") + "…", true
+}
@@ -0,0 +1,111 @@
+package formatting
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTruncateText(t *testing.T) {
+	const maxSize = 65535
+	row := "\n<tr>\n<td>✅ Succeeded</td>\n<td>1 minute</td><td>\n\n[task](https://console/task)\n\n</td></tr>"
+	table := "\n<table>\n  <tr><th>Status</th><th>Duration</th><th>Name</th></tr>" + strings.Repeat(row, 100) + "\n</table>"
+	logURL := "https://console/pr"
+
+	tests := []struct {
+		name       string
+		text       string
+		maxSize    int
+		logURL     string
+		wantSuffix string
+		truncated  bool
+	}{
+		{
+			name:    "no limit",
+			text:    strings.Repeat("a", 100000),
+			maxSize: 0,
+		},
+		{
+			name:    "under the limit",
+			text:    "small" + table,
+			maxSize: maxSize,
+		},
+		{
+			name:       "cut at a row of the task table",
+			text:       "header\n<table>" + strings.Repeat(row, 2*maxSize/len(row)) + "\n</table>",
+			maxSize:    maxSize,
+			logURL:     logURL,
+			wantSuffix: "</td></tr>\n</table>\n\n…output truncated, see [full logs](https://console/pr)",
+			truncated:  true,
+		},
+		{
+			name:       "cut at a line outside of a table",
+			text:       strings.Repeat("a log line\n", maxSize/10),
+			maxSize:    maxSize,
+			logURL:     logURL,
+			wantSuffix: "a log line\n\n…output truncated, see [full logs](https://console/pr)",
+			truncated:  true,
+		},
+		{
+			name:       "no log url",
+			text:       strings.Repeat("✅", maxSize),
+			maxSize:    maxSize,
+			wantSuffix: "✅\n\n…output truncated, see full logs",
+			truncated:  true,
+		},
+		{
+			name:       "smaller gitlab style limit",
+			text:       strings.Repeat("a log line\n", 1000),
+			maxSize:    1000,
+			logURL:     logURL,
+			wantSuffix: "a log line\n\n…output truncated, see [full logs](https://console/pr)",
+			truncated:  true,
+		},
+		{
+			name:       "limit too small for the link",
+			text:       strings.Repeat("✅", 100),
+			maxSize:    20,
+			logURL:     logURL,
+			wantSuffix: "✅",
+			truncated:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.text, tt.maxSize, tt.logURL)
+			if !tt.truncated {
+				assert.Equal(t, got, tt.text)
+				return
+			}
+			assert.Assert(t, got != "")
+			assert.Assert(t, len(got) <= tt.maxSize, "got %d bytes, limit is %d", len(got), tt.maxSize)
+			assert.Assert(t, strings.HasSuffix(got, tt.wantSuffix), got)
+			assert.Assert(t, utf8.ValidString(got))
+			assert.Equal(t, strings.Count(got, "<table>"), strings.Count(got, "</table>"))
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		want          string
+		wantTruncated bool
+	}{
+		{name: "under the limit", text: "short", want: "short"},
+		{name: "at the limit", text: "0123456789", want: "0123456789"},
+		{name: "over the limit", text: "0123456789a", want: "012345678…", wantTruncated: true},
+		{name: "trailing space before the cut", text: "01234567 9a", want: "01234567…", wantTruncated: true},
+		{name: "multibyte characters", text: strings.Repeat("✅", 11), want: strings.Repeat("✅", 9) + "…", wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := TruncateRunes(tt.text, 10)
+			assert.Equal(t, got, tt.want)
+			assert.Equal(t, truncated, tt.wantTruncated)
+		})
+	}
+}
@@ -56,6 +56,29 @@ func GetRepoOwnerSplitted(u string) (string, string, error) {
 	return org, repo, nil
 }
 
+// NormalizeRepoURL normalizes the URL of a repository to compare it with
+// another one, with the scheme (https when missing) and the host lowercased
+// and without the .git suffix or the trailing slashes. The path is kept as is
+// for the sub-groups of GitLab.
+func NormalizeRepoURL(repoURL string) string {
+	repoURL = strings.TrimSpace(repoURL)
+	if !strings.Contains(repoURL, "://") {
+		repoURL = "https://" + repoURL
+	}
+	uparse, err := url.Parse(repoURL)
+	if err != nil || uparse.Host == "" {
+		return strings.TrimSuffix(strings.TrimRight(repoURL, "/"), ".git")
+	}
+	path := strings.TrimSuffix(strings.TrimRight(uparse.Path, "/"), ".git")
+	return fmt.Sprintf("%s://%s%s", strings.ToLower(uparse.Scheme), strings.ToLower(uparse.Host), strings.TrimRight(path, "/"))
+}
+
+// SameRepoURL returns true when both URLs are the URL of the same repository
+// once normalized with NormalizeRepoURL.
+func SameRepoURL(a, b string) bool {
+	return NormalizeRepoURL(a) == NormalizeRepoURL(b)
+}
+
 // CamelCasit pull_request > PullRequest.
 func CamelCasit(s string) string {
 	c := cases.Title(language.AmericanEnglish)
@@ -207,3 +207,46 @@ func TestShortSHA(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeRepoURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "normalized", url: "https://github.com/owner/repo", want: "https://github.com/owner/repo"},
+		{name: "git suffix", url: "https://github.com/owner/repo.git", want: "https://github.com/owner/repo"},
+		{name: "trailing slash", url: "https://github.com/owner/repo/", want: "https://github.com/owner/repo"},
+		{name: "git suffix and trailing slash", url: "https://github.com/owner/repo.git/", want: "https://github.com/owner/repo"},
+		{name: "host and scheme case", url: "HTTPS://GitHub.com/owner/Repo", want: "https://github.com/owner/Repo"},
+		{name: "no scheme", url: "gitlab.com/group/repo", want: "https://gitlab.com/group/repo"},
+		{name: "sub-groups", url: "https://GitLab.com/group/sub/group/repo.git", want: "https://gitlab.com/group/sub/group/repo"},
+		{name: "spaces", url: " https://github.com/owner/repo ", want: "https://github.com/owner/repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeRepoURL(tt.url); got != tt.want {
+				t.Errorf("NormalizeRepoURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameRepoURL(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "same", a: "https://gitlab.com/group/sub/repo", b: "https://GITLAB.com/group/sub/repo.git/", want: true},
+		{name: "other sub-group", a: "https://gitlab.com/group/sub/repo", b: "https://gitlab.com/group/other/repo", want: false},
+		{name: "parent group", a: "https://gitlab.com/group/sub", b: "https://gitlab.com/group/sub/repo", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameRepoURL(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameRepoURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -67,8 +67,8 @@ func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1.PipelineRu
 		if event.InstallationID != -1 {
 			annotations[keys.InstallationID] = strconv.FormatInt(event.InstallationID, 10)
 		}
-		if event.GHEURL != "" {
-			annotations[keys.GHEURL] = event.GHEURL
+		if event.GHEHost != "" {
+			annotations[keys.GHEURL] = event.GHEHost
 		}
 	}
 
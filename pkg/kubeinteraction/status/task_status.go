@@ -11,6 +11,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -68,6 +69,83 @@ func GetStatusFromTaskStatusOrFromAsking(ctx context.Context, pr *tektonv1.Pipel
 	return trStatus
 }
 
+// GetTaskRunsResourceRequests returns the resource requests of the pods of
+// the TaskRuns, summed over all their containers, by TaskRun name. TaskRuns
+// which don't have a pod yet (i.e: not started) are not part of the result.
+func GetTaskRunsResourceRequests(ctx context.Context, pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus, run *params.Run) map[string]corev1.ResourceList {
+	requests := map[string]corev1.ResourceList{}
+	for name, tr := range trStatus {
+		if tr.Status == nil || tr.Status.PodName == "" {
+			continue
+		}
+		pod, err := run.Clients.Kube.CoreV1().Pods(pr.GetNamespace()).Get(ctx, tr.Status.PodName, metav1.GetOptions{})
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				run.Clients.Log.Warnf("cannot get pod %s of taskrun %s in ns %s: %v", tr.Status.PodName, name, pr.GetNamespace(), err)
+			}
+			continue
+		}
+		total := corev1.ResourceList{}
+		for _, container := range pod.Spec.Containers {
+			for resourceName, quantity := range container.Resources.Requests {
+				sum := total[resourceName]
+				sum.Add(quantity)
+				total[resourceName] = sum
+			}
+		}
+		requests[name] = total
+	}
+	return requests
+}
+
+// GetTaskRunsPodEvents returns the message of the latest warning event of the
+// pod of the TaskRuns which have failed before any of their steps have run
+// (i.e: image pull failure or the pod couldn't be scheduled), by TaskRun name.
+func GetTaskRunsPodEvents(ctx context.Context, pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus, run *params.Run) map[string]string {
+	podEvents := map[string]string{}
+	for name, tr := range trStatus {
+		if !failedBeforeSteps(tr) {
+			continue
+		}
+		events, err := run.Clients.Kube.CoreV1().Events(pr.GetNamespace()).List(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.kind=Pod,involvedObject.name=%s", tr.Status.PodName),
+		})
+		if err != nil {
+			run.Clients.Log.Warnf("cannot get events of pod %s of taskrun %s in ns %s: %v", tr.Status.PodName, name, pr.GetNamespace(), err)
+			continue
+		}
+		var latest *corev1.Event
+		for i := range events.Items {
+			event := &events.Items[i]
+			if event.Type != corev1.EventTypeWarning || event.InvolvedObject.Name != tr.Status.PodName {
+				continue
+			}
+			if latest == nil || latest.LastTimestamp.Before(&event.LastTimestamp) {
+				latest = event
+			}
+		}
+		if latest != nil {
+			podEvents[name] = fmt.Sprintf("%s: %s", latest.Reason, latest.Message)
+		}
+	}
+	return podEvents
+}
+
+func failedBeforeSteps(tr *tektonv1.PipelineRunTaskRunStatus) bool {
+	if tr.Status == nil || tr.Status.PodName == "" || len(tr.Status.Conditions) == 0 ||
+		tr.Status.Conditions[0].Status != corev1.ConditionFalse {
+		return false
+	}
+	// steps which never started may still have been marked as terminated
+	// when the TaskRun failed
+	for _, step := range tr.Status.Steps {
+		if step.Running != nil || (step.Terminated != nil && !step.Terminated.StartedAt.IsZero()) {
+			return false
+		}
+	}
+	return true
+}
+
 // CollectFailedTasksLogSnippet collects all tasks information we are interested in.
 // should really be in a tektoninteractions package but i lack imagination at the moment.
 func CollectFailedTasksLogSnippet(ctx context.Context, cs *params.Run, kinteract kubeinteraction.Interface, pr *tektonv1.PipelineRun, numLines int64) map[string]pacv1alpha1.TaskInfos {
@@ -2,6 +2,7 @@ package status
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -15,6 +16,7 @@ import (
 	zapobserver "go.uber.org/zap/zaptest/observer"
 	assertv3 "gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	knativeapi "knative.dev/pkg/apis"
@@ -303,3 +305,142 @@ func TestGetStatusFromTaskStatusOrFromAsking(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTaskRunsResourceRequests(t *testing.T) {
+	testNS := "test"
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "started-pod", Namespace: testNS},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "step-one",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("250m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					}},
+				},
+				{
+					Name: "step-two",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("250m"),
+					}},
+				},
+			},
+		},
+	}
+	_, err := stdata.Kube.CoreV1().Pods(testNS).Create(ctx, pod, metav1.CreateOptions{})
+	assertv3.NilError(t, err)
+
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	cs := &params.Run{Clients: paramclients.Clients{
+		Kube: stdata.Kube,
+		Log:  zap.New(observer).Sugar(),
+	}}
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Namespace: testNS}}
+	trStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"started": {
+			PipelineTaskName: "started",
+			Status:           &tektonv1.TaskRunStatus{TaskRunStatusFields: tektonv1.TaskRunStatusFields{PodName: "started-pod"}},
+		},
+		"not-started": {
+			PipelineTaskName: "not-started",
+			Status:           &tektonv1.TaskRunStatus{},
+		},
+		"pod-gone": {
+			PipelineTaskName: "pod-gone",
+			Status:           &tektonv1.TaskRunStatus{TaskRunStatusFields: tektonv1.TaskRunStatusFields{PodName: "gone-pod"}},
+		},
+	}
+
+	got := GetTaskRunsResourceRequests(ctx, pr, trStatus, cs)
+	assertv3.Equal(t, len(got), 1)
+	cpu := got["started"][corev1.ResourceCPU]
+	memory := got["started"][corev1.ResourceMemory]
+	assertv3.Equal(t, cpu.String(), "500m")
+	assertv3.Equal(t, memory.String(), "128Mi")
+}
+
+func TestGetTaskRunsPodEvents(t *testing.T) {
+	testNS := "test"
+	now := time.Now()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Events: []*corev1.Event{
+			{
+				ObjectMeta:     metav1.ObjectMeta{Name: "old", Namespace: testNS},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "unschedulable-pod"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "FailedScheduling",
+				Message:        "old event",
+				LastTimestamp:  metav1.NewTime(now.Add(-time.Hour)),
+			},
+			{
+				ObjectMeta:     metav1.ObjectMeta{Name: "latest", Namespace: testNS},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "unschedulable-pod"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "FailedScheduling",
+				Message:        "0/3 nodes are available: 3 Insufficient cpu.",
+				LastTimestamp:  metav1.NewTime(now),
+			},
+			{
+				ObjectMeta:     metav1.ObjectMeta{Name: "normal", Namespace: testNS},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "unschedulable-pod"},
+				Type:           corev1.EventTypeNormal,
+				Reason:         "Scheduled",
+				Message:        "not a warning",
+				LastTimestamp:  metav1.NewTime(now.Add(time.Hour)),
+			},
+			{
+				ObjectMeta:     metav1.ObjectMeta{Name: "ran", Namespace: testNS},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "ran-pod"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				Message:        "should not be shown",
+				LastTimestamp:  metav1.NewTime(now),
+			},
+		},
+	})
+
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	cs := &params.Run{Clients: paramclients.Clients{
+		Kube: stdata.Kube,
+		Log:  zap.New(observer).Sugar(),
+	}}
+	failed := knativeduckv1.Conditions{{Type: knativeapi.ConditionSucceeded, Status: corev1.ConditionFalse}}
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Namespace: testNS}}
+	trStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"unschedulable": {
+			PipelineTaskName: "unschedulable",
+			Status: &tektonv1.TaskRunStatus{
+				Status:              knativeduckv1.Status{Conditions: failed},
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{PodName: "unschedulable-pod"},
+			},
+		},
+		"ran": {
+			PipelineTaskName: "ran",
+			Status: &tektonv1.TaskRunStatus{
+				Status: knativeduckv1.Status{Conditions: failed},
+				TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+					PodName: "ran-pod",
+					Steps: []tektonv1.StepState{
+						{ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+							ExitCode:  1,
+							StartedAt: metav1.NewTime(now),
+						}}},
+					},
+				},
+			},
+		},
+		"not-started": {
+			PipelineTaskName: "not-started",
+			Status:           &tektonv1.TaskRunStatus{},
+		},
+	}
+
+	got := GetTaskRunsPodEvents(ctx, pr, trStatus, cs)
+	assertv3.DeepEqual(t, got, map[string]string{
+		"unschedulable": "FailedScheduling: 0/3 nodes are available: 3 Insufficient cpu.",
+	})
+}
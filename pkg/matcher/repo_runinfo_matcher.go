@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,7 +20,7 @@ func MatchEventURLRepo(ctx context.Context, cs *params.Run, event *info.Event, n
 	for i := len(repositories.Items) - 1; i >= 0; i-- {
 		repo := repositories.Items[i]
 		repo.Spec.URL = strings.TrimSuffix(repo.Spec.URL, "/")
-		if repo.Spec.URL == event.URL {
+		if formatting.SameRepoURL(repo.Spec.URL, event.URL) {
 			return &repo, nil
 		}
 	}
@@ -11,15 +11,35 @@ import (
 	"knative.dev/pkg/metrics"
 )
 
-var prCount = stats.Float64("pipelines_as_code_pipelinerun_count",
-	"number of pipeline runs by pipelines as code",
-	stats.UnitDimensionless)
+var (
+	prCount = stats.Float64("pipelines_as_code_pipelinerun_count",
+		"number of pipeline runs by pipelines as code",
+		stats.UnitDimensionless)
+
+	statusUpdateCount = stats.Float64("pipelines_as_code_status_update_count",
+		"number of status updates sent to the git providers",
+		stats.UnitDimensionless)
+
+	statusUpdateFailureCount = stats.Float64("pipelines_as_code_status_update_failure_count",
+		"number of status updates to the git providers which have failed",
+		stats.UnitDimensionless)
+
+	apiLatency = stats.Float64("pipelines_as_code_git_provider_api_latency",
+		"latency of the calls to the git provider API reporting the statuses",
+		stats.UnitMilliseconds)
+
+	// apiLatencyDistribution has to be the same aggregation for the view to
+	// be registered again by another recorder.
+	apiLatencyDistribution = view.Distribution(10, 50, 100, 250, 500, 1000, 2500, 5000, 10000)
+)
 
 // Recorder holds keys for metrics.
 type Recorder struct {
 	initialized     bool
 	provider        tag.Key
 	eventType       tag.Key
+	conclusion      tag.Key
+	api             tag.Key
 	ReportingPeriod time.Duration
 }
 
@@ -45,6 +65,18 @@ func NewRecorder() (*Recorder, error) {
 	}
 	r.eventType = eventType
 
+	conclusion, err := tag.NewKey("conclusion")
+	if err != nil {
+		return nil, err
+	}
+	r.conclusion = conclusion
+
+	api, err := tag.NewKey("api")
+	if err != nil {
+		return nil, err
+	}
+	r.api = api
+
 	err = view.Register(
 		&view.View{
 			Description: prCount.Description(),
@@ -52,6 +84,24 @@ func NewRecorder() (*Recorder, error) {
 			Aggregation: view.Count(),
 			TagKeys:     []tag.Key{r.provider, r.eventType},
 		},
+		&view.View{
+			Description: statusUpdateCount.Description(),
+			Measure:     statusUpdateCount,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{r.provider, r.conclusion},
+		},
+		&view.View{
+			Description: statusUpdateFailureCount.Description(),
+			Measure:     statusUpdateFailureCount,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{r.provider, r.conclusion},
+		},
+		&view.View{
+			Description: apiLatency.Description(),
+			Measure:     apiLatency,
+			Aggregation: apiLatencyDistribution,
+			TagKeys:     []tag.Key{r.provider, r.api},
+		},
 	)
 	if err != nil {
 		r.initialized = false
@@ -80,3 +130,47 @@ func (r *Recorder) Count(provider, event string) error {
 	metrics.Record(ctx, prCount.M(1))
 	return nil
 }
+
+// StatusUpdate logs a status update sent to a provider with its conclusion,
+// counted as a failure as well when it has failed.
+func (r *Recorder) StatusUpdate(provider, conclusion string, failed bool) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for status updates, failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.provider, provider),
+		tag.Insert(r.conclusion, conclusion),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, statusUpdateCount.M(1))
+	if failed {
+		metrics.Record(ctx, statusUpdateFailureCount.M(1))
+	}
+	return nil
+}
+
+// APILatency logs how long a call to the API of a provider took.
+func (r *Recorder) APILatency(provider, api string, latency time.Duration) error {
+	if !r.initialized {
+		return fmt.Errorf(
+			"ignoring the metrics recording for api latency, failed to initialize the metrics recorder")
+	}
+
+	ctx, err := tag.New(
+		context.Background(),
+		tag.Insert(r.provider, provider),
+		tag.Insert(r.api, api),
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics.Record(ctx, apiLatency.M(float64(latency.Milliseconds())))
+	return nil
+}
@@ -3,6 +3,7 @@ package info
 import (
 	"net/http"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 )
 
@@ -41,14 +42,30 @@ type Event struct {
 
 	PullRequestNumber int    // Pull or Merge Request number
 	PullRequestTitle  string // Title of the pull Request
+	PullRequestDraft  bool   // Whether the pull Request is a draft
 	TriggerComment    string // The comment triggering the pipelinerun when using on-comment annotation
+	// PullRequestLabel are the labels of the pull request, nil until they
+	// have been fetched.
+	PullRequestLabel []string
+	// ApplicationName overrides the application name from the settings
+	// when set on the Repository.
+	ApplicationName string
+	// PreviousSHA is the head commit of the pull request before it got
+	// updated, only set when the pull request is synchronized.
+	PreviousSHA string
 
 	// TODO: move forge specifics to each driver
 	// Github
 	Organization   string
 	Repository     string
 	InstallationID int64
-	GHEURL         string
+	// GHEHost is the host of the GitHub Enterprise the event comes from,
+	// empty for github.com.
+	GHEHost string
+	// CheckRunExternalID is the external id of the check run re-run or
+	// cancelled, the name of its PipelineRun or TaskRun. It is resolved to
+	// the target PipelineRun once the Repository is known.
+	CheckRunExternalID string
 
 	// TODO: move out inside the provider
 	// Bitbucket Cloud
@@ -88,6 +105,15 @@ func (r *Event) DeepCopyInto(out *Event) {
 	*out = *r
 }
 
+// GitHubAPIURL returns the URL of the API of a GitHub Enterprise host, or the
+// one of github.com when the host is empty.
+func GitHubAPIURL(gheHost string) string {
+	if gheHost == "" {
+		return keys.PublicGithubAPIURL
+	}
+	return "https://" + gheHost + "/api/v3"
+}
+
 // NewEvent returns a new Event.
 func NewEvent() *Event {
 	return &Event{
@@ -15,3 +15,9 @@ func TestInfoEvent(t *testing.T) {
 	ev1.DeepCopyInto(ev2)
 	assert.Equal(t, eventType, ev2.EventType)
 }
+
+func TestGitHubAPIURL(t *testing.T) {
+	assert.Equal(t, GitHubAPIURL("ghe.company.com"), "https://ghe.company.com/api/v3")
+	// no enterprise header means github.com
+	assert.Equal(t, GitHubAPIURL(""), "https://api.github.com")
+}
@@ -5,4 +5,7 @@ type ProviderConfig struct {
 	APIURL         string
 	Name           string
 	SkipEmoji      bool
+	// MaxTextSize is the maximum size of the status text the provider
+	// accepts, 0 means there is no known limit.
+	MaxTextSize int
 }
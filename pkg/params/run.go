@@ -38,6 +38,11 @@ func (r *Run) UpdatePACInfo(ctx context.Context) error {
 		return err
 	}
 
+	// the console is wrapped again below when the log URLs are templated
+	if logURLConsole, ok := r.Clients.ConsoleUI.(*consoleui.LogURLTemplate); ok {
+		r.Clients.ConsoleUI = logURLConsole.Interface
+	}
+
 	if r.Info.Pac.Settings.TektonDashboardURL != "" && r.Info.Pac.Settings.TektonDashboardURL != r.Clients.ConsoleUI.URL() {
 		r.Clients.Log.Infof("updating console url to: %s", r.Info.Pac.Settings.TektonDashboardURL)
 		r.Clients.ConsoleUI = &consoleui.TektonDashboard{BaseURL: r.Info.Pac.Settings.TektonDashboardURL}
@@ -58,6 +63,10 @@ func (r *Run) UpdatePACInfo(ctx context.Context) error {
 		_ = r.Clients.ConsoleUI.UI(ctx, r.Clients.Dynamic)
 	}
 
+	if r.Info.Pac.Settings.LogURLTemplate != "" {
+		r.Clients.ConsoleUI = &consoleui.LogURLTemplate{Interface: r.Clients.ConsoleUI, Info: &r.Info}
+	}
+
 	return nil
 }
 
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/configutil"
 	"go.uber.org/zap"
@@ -26,6 +28,43 @@ const (
 	CustomConsoleNamespaceURLKey = "custom-console-url-namespace"
 
 	SecretGhAppTokenRepoScopedKey = "secret-github-app-token-scoped" //nolint: gosec
+
+	// FailureRemediationKeyPrefix prefixes the keys of the remediations to
+	// show on failure, followed by the failure reason or the failed task
+	// name, i.e: failure-remediation-lint
+	FailureRemediationKeyPrefix = "failure-remediation-"
+
+	// ClassicStatusSkippedStateSuccess reports a skipped or neutral run as a
+	// success on the classic commit statuses, ClassicStatusSkippedStatePending
+	// as pending and ClassicStatusSkippedStateLabeled as a success with
+	// "(skipped)" in the description.
+	ClassicStatusSkippedStateSuccess = "success"
+	ClassicStatusSkippedStatePending = "pending"
+	ClassicStatusSkippedStateLabeled = "labeled"
+
+	// CommentStrategyAll comments on the pull request with every status
+	// reported on the classic commit statuses, CommentStrategyFailureOnly
+	// only with the failures and CommentStrategyDisableAll never.
+	CommentStrategyAll         = "all"
+	CommentStrategyFailureOnly = "failure_only"
+	CommentStrategyDisableAll  = "disable_all"
+
+	// StatusSHAStrategyHead reports the commit statuses of a pull request on
+	// its head commit only, StatusSHAStrategyHeadAndMerge on its merge commit
+	// too.
+	StatusSHAStrategyHead         = "head"
+	StatusSHAStrategyHeadAndMerge = "head-and-merge"
+
+	// StatusModeCheckRun reports the statuses on GitHub as check runs,
+	// StatusModeCommitStatus as classic commit statuses and StatusModeBoth as
+	// both, the check runs need a GitHub App.
+	StatusModeCheckRun     = "check_run"
+	StatusModeCommitStatus = "commit_status"
+	StatusModeBoth         = "both"
+
+	// maxStatusContextPrefixLength leaves most of the 255 characters of a
+	// GitHub status context to the check name.
+	maxStatusContextPrefixLength = 100
 )
 
 var (
@@ -67,7 +106,93 @@ type Settings struct {
 	CustomConsolePRTaskLog    string `json:"custom-console-url-pr-tasklog"`
 	CustomConsoleNamespaceURL string `json:"custom-console-url-namespace"`
 
+	LogURLTemplate string `json:"log-url-template"`
+
 	RememberOKToTest bool `default:"true" json:"remember-ok-to-test"`
+	// SkipDraft doesn't start the PipelineRuns of the draft pull requests
+	// until they are marked as ready, unless the Repository CR overrides it.
+	SkipDraft bool `default:"false" json:"skip-draft"`
+
+	StatusKubernetesEvent  bool   `default:"false"         json:"status-kubernetes-event"`
+	CollapseTaskStatus     bool   `default:"false"         json:"collapse-task-status"`
+	GroupFailureMessages   bool   `default:"false"         json:"group-failure-messages"`
+	StatusResourceRequests bool   `default:"false"         json:"status-resource-requests"`
+	StatusPodEvents        bool   `default:"false"         json:"status-pod-events"`
+	StatusGraphURL         string `json:"status-graph-url"`
+	StatusPullRequestLabel bool   `default:"false"         json:"status-pull-request-labels"`
+	StatusLastSuccessLink  bool   `default:"false"         json:"status-last-success-link"`
+	StatusQueued           bool   `default:"false"         json:"status-queued"`
+	// StatusConcurrency is how many statuses of the PipelineRuns started for
+	// an event are posted at the same time.
+	StatusConcurrency int `default:"5" json:"status-concurrency"`
+
+	ClassicStatusSkippedState string `default:"success" json:"classic-status-skipped-state"`
+	CommentStrategy           string `default:"all"     json:"comment-strategy"`
+	StatusSHAStrategy         string `default:"head"    json:"status-sha-strategy"`
+	// StatusMode is how the statuses are reported on GitHub, when not set
+	// as check runs with a GitHub App and as commit statuses otherwise.
+	StatusMode string `json:"status-mode"`
+	// StatusContextPrefix is prepended to the names of the check runs and
+	// to the contexts of the commit statuses, i.e: "ci/pac/".
+	StatusContextPrefix string `json:"status-context-prefix"`
+	// PerTaskChecks reports every TaskRun as its own check run on top of the
+	// check run of the PipelineRun.
+	PerTaskChecks bool `default:"false" json:"per-task-checks"`
+	// AuditStatuses writes an audit record of every status posted on
+	// GitHub as a JSON line.
+	AuditStatuses bool `default:"false" json:"audit-statuses"`
+	// DryRun logs the statuses instead of posting them to the git provider.
+	DryRun bool `default:"false" json:"dry-run"`
+	// CommentLogSnippet adds the last CommentLogSnippetLines lines of the
+	// logs of the failed tasks to the comment of a failed PipelineRun on the
+	// pull request.
+	CommentLogSnippet      bool `default:"false" json:"comment-log-snippet"`
+	CommentLogSnippetLines int  `default:"20"    json:"comment-log-snippet-lines"`
+	// StatusGist publishes the summary of a completed run as a secret gist
+	// linked from its commit status, only for the public repositories and the
+	// ones matching the comma separated globs of StatusGistRepositories.
+	StatusGist             bool   `default:"false" json:"status-gist"`
+	StatusGistRepositories string `json:"status-gist-repositories"`
+	// StatusDeploymentEnvironments are the comma separated globs of the
+	// environments the PipelineRuns are allowed to report a deployment to,
+	// unless the Repository CR has its own. No deployment is reported when
+	// empty.
+	StatusDeploymentEnvironments string `json:"status-deployment-environments"`
+	// SlackWebhookAllowedHosts are the comma separated hosts the Slack
+	// webhooks of the Repository CRs may point to besides hooks.slack.com.
+	SlackWebhookAllowedHosts string `json:"slack-webhook-allowed-hosts"`
+
+	// FailureRemediations maps a failure reason or a failed task name to the
+	// remediation to show in the status on failure.
+	FailureRemediations map[string]string
+
+	ProtectedTags string `json:"protected-tags"`
+
+	// CheckNameTemplate is the template joining the application name and the
+	// PipelineRun name in the check names, "{{.AppName}} / {{.PipelineRun}}"
+	// when not set.
+	CheckNameTemplate string `json:"check-name-template"`
+
+	GitHubHTTPSProxy   string `json:"github-https-proxy"`
+	GitHubNoProxy      string `json:"github-no-proxy"`
+	GitHubCABundlePath string `json:"github-ca-bundle-path"`
+	// GitHubRequestTimeout is the timeout in seconds of the requests made to
+	// GitHub for the GitHub App authentication.
+	GitHubRequestTimeout int `default:"30" json:"github-request-timeout"`
+	// GitHubJWTExpiration is how long in seconds the JWT of the GitHub App
+	// is valid, GitHub refuses the ones valid for more than 10 minutes.
+	GitHubJWTExpiration int `default:"300" json:"github-jwt-expiration"`
+	// GitHubCircuitBreakerFailures is the number of consecutive failures of
+	// the installation lookups on a GitHub host after which they are not
+	// attempted for GitHubCircuitBreakerCooldown seconds.
+	GitHubCircuitBreakerFailures int `default:"5"  json:"github-circuit-breaker-failures"`
+	GitHubCircuitBreakerCooldown int `default:"60" json:"github-circuit-breaker-cooldown"`
+	// GitHubRepoListCacheTTL is how long in seconds the repositories of an
+	// installation of the GitHub App are cached.
+	GitHubRepoListCacheTTL int `default:"300" json:"github-repo-list-cache-ttl"`
+	// UseGraphQL looks up the existing check runs of a commit with a single
+	// GraphQL query instead of the paginated REST API.
+	UseGraphQL bool `default:"false" json:"github-use-graphql"`
 }
 
 func (s *Settings) DeepCopy(out *Settings) {
@@ -81,13 +206,29 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 	defer mutex.Unlock()
 
 	setting.HubCatalogs = getHubCatalogs(logger, setting.HubCatalogs, config)
+	setting.FailureRemediations = getFailureRemediations(config)
 
 	err := configutil.ValidateAndAssignValues(logger, config, setting, map[string]func(string) error{
-		"ErrorDetectionSimpleRegexp": isValidRegex,
-		"TektonDashboardURL":         isValidURL,
-		"CustomConsoleURL":           isValidURL,
-		"CustomConsolePRTaskLog":     startWithHTTPorHTTPS,
-		"CustomConsolePRDetail":      startWithHTTPorHTTPS,
+		"ErrorDetectionSimpleRegexp":   isValidRegex,
+		"TektonDashboardURL":           isValidURL,
+		"CustomConsoleURL":             isValidURL,
+		"CustomConsolePRTaskLog":       startWithHTTPorHTTPS,
+		"CustomConsolePRDetail":        startWithHTTPorHTTPS,
+		"LogURLTemplate":               startWithHTTPorHTTPS,
+		"StatusGraphURL":               startWithHTTPorHTTPS,
+		"GitHubHTTPSProxy":             startWithHTTPorHTTPS,
+		"ClassicStatusSkippedState":    isValidClassicStatusSkippedState,
+		"CommentStrategy":              isValidCommentStrategy,
+		"StatusSHAStrategy":            isValidStatusSHAStrategy,
+		"StatusMode":                   isValidStatusMode,
+		"StatusContextPrefix":          isValidStatusContextPrefix,
+		"CommentLogSnippetLines":       isPositiveInt,
+		"GitHubRequestTimeout":         isPositiveInt,
+		"GitHubJWTExpiration":          isValidJWTExpiration,
+		"GitHubCircuitBreakerFailures": isPositiveInt,
+		"GitHubCircuitBreakerCooldown": isPositiveInt,
+		"GitHubRepoListCacheTTL":       isPositiveInt,
+		"StatusConcurrency":            isPositiveInt,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to validate and assign values: %w", err)
@@ -125,6 +266,69 @@ func isValidRegex(regex string) error {
 	return nil
 }
 
+func isValidClassicStatusSkippedState(state string) error {
+	switch state {
+	case ClassicStatusSkippedStateSuccess, ClassicStatusSkippedStatePending, ClassicStatusSkippedStateLabeled:
+		return nil
+	}
+	return fmt.Errorf("invalid value, must be one of %s, %s or %s",
+		ClassicStatusSkippedStateSuccess, ClassicStatusSkippedStatePending, ClassicStatusSkippedStateLabeled)
+}
+
+func isValidCommentStrategy(strategy string) error {
+	switch strategy {
+	case CommentStrategyAll, CommentStrategyFailureOnly, CommentStrategyDisableAll:
+		return nil
+	}
+	return fmt.Errorf("invalid value, must be one of %s, %s or %s",
+		CommentStrategyAll, CommentStrategyFailureOnly, CommentStrategyDisableAll)
+}
+
+func isValidStatusSHAStrategy(strategy string) error {
+	switch strategy {
+	case StatusSHAStrategyHead, StatusSHAStrategyHeadAndMerge:
+		return nil
+	}
+	return fmt.Errorf("invalid value, must be one of %s or %s", StatusSHAStrategyHead, StatusSHAStrategyHeadAndMerge)
+}
+
+func isValidStatusMode(mode string) error {
+	switch mode {
+	case StatusModeCheckRun, StatusModeCommitStatus, StatusModeBoth:
+		return nil
+	}
+	return fmt.Errorf("invalid value, must be one of %s, %s or %s", StatusModeCheckRun, StatusModeCommitStatus, StatusModeBoth)
+}
+
+// statusContextPrefixRegexp are the characters allowed in the prefix of the
+// status contexts, usable in the patterns of the branch protection rules.
+var statusContextPrefixRegexp = regexp.MustCompile(`^[A-Za-z0-9 ._/:()-]+$`)
+
+func isValidStatusContextPrefix(prefix string) error {
+	if utf8.RuneCountInString(prefix) > maxStatusContextPrefixLength || !statusContextPrefixRegexp.MatchString(prefix) {
+		return fmt.Errorf("invalid value, must be at most %d letters, digits, spaces or ._/:()- characters", maxStatusContextPrefixLength)
+	}
+	return nil
+}
+
+func isPositiveInt(value string) error {
+	if i, err := strconv.Atoi(value); err != nil || i <= 0 {
+		return fmt.Errorf("invalid value, must be a number greater than 0")
+	}
+	return nil
+}
+
+// MaxGitHubJWTExpiration is the longest validity in seconds GitHub accepts
+// for the JWT of a GitHub App.
+const MaxGitHubJWTExpiration = 600
+
+func isValidJWTExpiration(value string) error {
+	if i, err := strconv.Atoi(value); err != nil || i <= 0 || i > MaxGitHubJWTExpiration {
+		return fmt.Errorf("invalid value, must be a number of seconds between 1 and %d", MaxGitHubJWTExpiration)
+	}
+	return nil
+}
+
 func startWithHTTPorHTTPS(url string) error {
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
 		return fmt.Errorf("invalid value, must start with http:// or https://")
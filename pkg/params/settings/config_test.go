@@ -2,6 +2,7 @@ package settings
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
@@ -44,6 +45,17 @@ func TestConfigToSettings(t *testing.T) {
 				CustomConsolePRTaskLog:             "",
 				CustomConsoleNamespaceURL:          "",
 				RememberOKToTest:                   true,
+				ClassicStatusSkippedState:          "success",
+				CommentStrategy:                    "all",
+				StatusSHAStrategy:                  "head",
+				FailureRemediations:                map[string]string{},
+				GitHubRequestTimeout:               30,
+				GitHubJWTExpiration:                300,
+				GitHubCircuitBreakerFailures:       5,
+				CommentLogSnippetLines:             20,
+				GitHubCircuitBreakerCooldown:       60,
+				GitHubRepoListCacheTTL:             300,
+				StatusConcurrency:                  5,
 			},
 		},
 		{
@@ -70,7 +82,46 @@ func TestConfigToSettings(t *testing.T) {
 				"custom-console-url-pr-details":          "https://custom-console-pr-details",
 				"custom-console-url-pr-tasklog":          "https://custom-console-pr-tasklog",
 				"custom-console-url-namespace":           "https://custom-console-namespace",
+				"log-url-template":                       "https://grafana/{{.Namespace}}/{{.PipelineRunName}}",
 				"remember-ok-to-test":                    "false",
+				"skip-draft":                             "true",
+				"status-kubernetes-event":                "true",
+				"collapse-task-status":                   "true",
+				"group-failure-messages":                 "true",
+				"status-resource-requests":               "true",
+				"status-pod-events":                      "true",
+				"status-graph-url":                       "https://graph/{{ namespace }}/{{ pipelinerun }}",
+				"status-pull-request-labels":             "true",
+				"status-last-success-link":               "true",
+				"status-queued":                          "true",
+				"classic-status-skipped-state":           "labeled",
+				"comment-strategy":                       "failure_only",
+				"status-sha-strategy":                    "head-and-merge",
+				"status-mode":                            "both",
+				"per-task-checks":                        "true",
+				"audit-statuses":                         "true",
+				"dry-run":                                "true",
+				"status-context-prefix":                  "ci/pac/",
+				"comment-log-snippet":                    "true",
+				"comment-log-snippet-lines":              "50",
+				"status-gist":                            "true",
+				"status-gist-repositories":               "owner/*",
+				"status-deployment-environments":         "staging,prod-*",
+				"slack-webhook-allowed-hosts":            "slack.proxy.corp",
+				"failure-remediation-lint":               "run `make fmt`",
+				"failure-remediation-":                   "ignored",
+				"protected-tags":                         "v*,release-*",
+				"check-name-template":                    "{{.PipelineRun}}",
+				"github-https-proxy":                     "http://proxy.corp:3128",
+				"github-no-proxy":                        "localhost,.internal",
+				"github-ca-bundle-path":                  "/etc/ssl/certs/corp-ca.pem",
+				"github-request-timeout":                 "10",
+				"github-jwt-expiration":                  "540",
+				"github-circuit-breaker-failures":        "3",
+				"github-circuit-breaker-cooldown":        "120",
+				"github-repo-list-cache-ttl":             "60",
+				"status-concurrency":                     "10",
+				"github-use-graphql":                     "true",
 			},
 			expectedStruct: Settings{
 				ApplicationName:                    "pac-pac",
@@ -95,7 +146,45 @@ func TestConfigToSettings(t *testing.T) {
 				CustomConsolePRdetail:              "https://custom-console-pr-details",
 				CustomConsolePRTaskLog:             "https://custom-console-pr-tasklog",
 				CustomConsoleNamespaceURL:          "https://custom-console-namespace",
+				LogURLTemplate:                     "https://grafana/{{.Namespace}}/{{.PipelineRunName}}",
 				RememberOKToTest:                   false,
+				SkipDraft:                          true,
+				StatusKubernetesEvent:              true,
+				CollapseTaskStatus:                 true,
+				GroupFailureMessages:               true,
+				StatusResourceRequests:             true,
+				StatusPodEvents:                    true,
+				StatusGraphURL:                     "https://graph/{{ namespace }}/{{ pipelinerun }}",
+				StatusPullRequestLabel:             true,
+				StatusLastSuccessLink:              true,
+				StatusQueued:                       true,
+				ClassicStatusSkippedState:          "labeled",
+				CommentStrategy:                    "failure_only",
+				StatusSHAStrategy:                  "head-and-merge",
+				StatusMode:                         "both",
+				PerTaskChecks:                      true,
+				AuditStatuses:                      true,
+				DryRun:                             true,
+				StatusContextPrefix:                "ci/pac/",
+				CommentLogSnippet:                  true,
+				CommentLogSnippetLines:             50,
+				StatusGist:                         true,
+				StatusGistRepositories:             "owner/*",
+				StatusDeploymentEnvironments:       "staging,prod-*",
+				SlackWebhookAllowedHosts:           "slack.proxy.corp",
+				FailureRemediations:                map[string]string{"lint": "run `make fmt`"},
+				ProtectedTags:                      "v*,release-*",
+				CheckNameTemplate:                  "{{.PipelineRun}}",
+				GitHubHTTPSProxy:                   "http://proxy.corp:3128",
+				GitHubNoProxy:                      "localhost,.internal",
+				GitHubCABundlePath:                 "/etc/ssl/certs/corp-ca.pem",
+				GitHubRequestTimeout:               10,
+				GitHubJWTExpiration:                540,
+				GitHubCircuitBreakerFailures:       3,
+				GitHubCircuitBreakerCooldown:       120,
+				GitHubRepoListCacheTTL:             60,
+				StatusConcurrency:                  10,
+				UseGraphQL:                         true,
 			},
 		},
 		{
@@ -133,6 +222,90 @@ func TestConfigToSettings(t *testing.T) {
 			},
 			expectedError: "custom validation failed for field CustomConsolePRTaskLog: invalid value, must start with http:// or https://",
 		},
+		{
+			name: "invalid classic status skipped state",
+			configMap: map[string]string{
+				"classic-status-skipped-state": "failure",
+			},
+			expectedError: "custom validation failed for field ClassicStatusSkippedState: invalid value, must be one of success, pending or labeled",
+		},
+		{
+			name: "invalid comment strategy",
+			configMap: map[string]string{
+				"comment-strategy": "none",
+			},
+			expectedError: "custom validation failed for field CommentStrategy: invalid value, must be one of all, failure_only or disable_all",
+		},
+		{
+			name: "invalid status sha strategy",
+			configMap: map[string]string{
+				"status-sha-strategy": "merge",
+			},
+			expectedError: "custom validation failed for field StatusSHAStrategy: invalid value, must be one of head or head-and-merge",
+		},
+		{
+			name: "invalid status mode",
+			configMap: map[string]string{
+				"status-mode": "check_runs",
+			},
+			expectedError: "custom validation failed for field StatusMode: invalid value, must be one of check_run, commit_status or both",
+		},
+		{
+			name: "invalid status context prefix",
+			configMap: map[string]string{
+				"status-context-prefix": "ci/*/",
+			},
+			expectedError: "custom validation failed for field StatusContextPrefix: invalid value, must be at most 100 letters, digits, spaces or ._/:()- characters",
+		},
+		{
+			name: "status context prefix too long",
+			configMap: map[string]string{
+				"status-context-prefix": strings.Repeat("a", 101),
+			},
+			expectedError: "custom validation failed for field StatusContextPrefix: invalid value, must be at most 100 letters, digits, spaces or ._/:()- characters",
+		},
+		{
+			name: "invalid value for github https proxy",
+			configMap: map[string]string{
+				"github-https-proxy": "proxy.corp:3128",
+			},
+			expectedError: "custom validation failed for field GitHubHTTPSProxy: invalid value, must start with http:// or https://",
+		},
+		{
+			name: "invalid github request timeout",
+			configMap: map[string]string{
+				"github-request-timeout": "0",
+			},
+			expectedError: "custom validation failed for field GitHubRequestTimeout: invalid value, must be a number greater than 0",
+		},
+		{
+			name: "invalid github jwt expiration",
+			configMap: map[string]string{
+				"github-jwt-expiration": "3600",
+			},
+			expectedError: "custom validation failed for field GitHubJWTExpiration: invalid value, must be a number of seconds between 1 and 600",
+		},
+		{
+			name: "invalid github circuit breaker failures",
+			configMap: map[string]string{
+				"github-circuit-breaker-failures": "-1",
+			},
+			expectedError: "custom validation failed for field GitHubCircuitBreakerFailures: invalid value, must be a number greater than 0",
+		},
+		{
+			name: "invalid github repo list cache ttl",
+			configMap: map[string]string{
+				"github-repo-list-cache-ttl": "0",
+			},
+			expectedError: "custom validation failed for field GitHubRepoListCacheTTL: invalid value, must be a number greater than 0",
+		},
+		{
+			name: "invalid status concurrency",
+			configMap: map[string]string{
+				"status-concurrency": "0",
+			},
+			expectedError: "custom validation failed for field StatusConcurrency: invalid value, must be a number greater than 0",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3,11 +3,26 @@ package settings
 import (
 	"fmt"
 	"net/url"
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
 )
 
+// getFailureRemediations gets the remediations from the keys prefixed by
+// FailureRemediationKeyPrefix.
+func getFailureRemediations(config map[string]string) map[string]string {
+	remediations := map[string]string{}
+	for k, v := range config {
+		reason := strings.TrimPrefix(k, FailureRemediationKeyPrefix)
+		if reason == k || reason == "" || v == "" {
+			continue
+		}
+		remediations[reason] = v
+	}
+	return remediations
+}
+
 func getHubCatalogs(logger *zap.SugaredLogger, catalogs *sync.Map, config map[string]string) *sync.Map {
 	if catalogs == nil {
 		catalogs = &sync.Map{}
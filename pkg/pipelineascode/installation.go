@@ -0,0 +1,22 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+)
+
+// invalidateInstallationRepos invalidates the cached repositories of the
+// installation of an installation_repositories event, only once its payload
+// has been validated with the webhook secret so nobody else can flush the
+// cache.
+func (p *PacRun) invalidateInstallationRepos(ctx context.Context) error {
+	p.event.Provider.WebhookSecret, _ = GetCurrentNSWebhookSecret(ctx, p.k8int, p.run)
+	if err := p.vcx.Validate(ctx, p.run, p.event); err != nil {
+		return fmt.Errorf("could not validate payload, check your webhook secret?: %w", err)
+	}
+	github.InstallationRepos().Invalidate(p.event.GHEHost, p.event.InstallationID)
+	p.logger.Infof("installation_repositories: invalidated the cached repositories of installation %d", p.event.InstallationID)
+	return nil
+}
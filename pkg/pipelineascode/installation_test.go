@@ -0,0 +1,89 @@
+package pipelineascode
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	ghprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestInvalidateInstallationRepos(t *testing.T) {
+	const webhookSecret = "secret"
+	payload := []byte(`{"action": "added", "installation": {"id": 4242}}`)
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	mac.Write(payload)
+	validSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name            string
+		signature       string
+		wantErr         string
+		wantInvalidated bool
+	}{
+		{
+			name:            "valid signature",
+			signature:       validSignature,
+			wantInvalidated: true,
+		},
+		{
+			name:      "invalid signature",
+			signature: "sha256=" + hex.EncodeToString([]byte("forged")),
+			wantErr:   "could not validate payload",
+		},
+		{
+			name:    "no signature",
+			wantErr: "no signature has been detected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ctx = info.StoreNS(ctx, "pac")
+			cache := ghprovider.InstallationRepos()
+			cache.Set("", 4242, []string{"https://github.com/owner/repo"}, time.Minute)
+			defer cache.Invalidate("", 4242)
+
+			fakelogger, _ := logger.GetLogger()
+			run := &params.Run{
+				Clients: clients.Clients{Log: fakelogger},
+				Info: info.Info{
+					Pac:        &info.PacOpts{Settings: &settings.Settings{}},
+					Controller: &info.ControllerInfo{Secret: info.DefaultPipelinesAscodeSecretName},
+				},
+			}
+			event := info.NewEvent()
+			event.EventType = ghprovider.InstallationRepositoriesEventType
+			event.InstallationID = 4242
+			event.Request = &info.Request{
+				Header:  map[string][]string{github.SHA256SignatureHeader: {tt.signature}},
+				Payload: payload,
+			}
+			k8int := &kitesthelper.KinterfaceTest{
+				GetSecretResult: map[string]string{info.DefaultPipelinesAscodeSecretName: webhookSecret},
+			}
+			vcx := &ghprovider.Provider{Run: run, Logger: fakelogger}
+
+			p := NewPacs(event, vcx, run, k8int, fakelogger)
+			err := p.Run(ctx)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			_, cached := cache.Get("", 4242)
+			assert.Equal(t, cached, !tt.wantInvalidated)
+		})
+	}
+}
@@ -29,10 +29,20 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 		return nil, nil, nil
 	}
 
+	// draft pull requests are tested once they are marked as ready or
+	// explicitly with a /test or /retest comment
+	if p.event.PullRequestDraft && p.event.TriggerTarget == triggertype.PullRequest && p.skipDraft(repo) {
+		msg := fmt.Sprintf("skipping pull request #%d since it is a draft", p.event.PullRequestNumber)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositorySkipDraftPullRequest", msg)
+		return nil, repo, nil
+	}
+
 	if p.event.CancelPipelineRuns {
 		return nil, repo, p.cancelPipelineRuns(ctx, repo)
 	}
 
+	p.reportQueued(ctx)
+
 	matchedPRs, err := p.getPipelineRunsFromRepo(ctx, repo)
 	if err != nil {
 		return nil, repo, err
@@ -126,6 +136,15 @@ is that what you want? make sure you use -n when generating the secret, eg: echo
 	return repo, nil
 }
 
+// skipDraft returns true when the PipelineRuns of the draft pull requests are
+// not started, from the Repository CR or else the skip-draft setting.
+func (p *PacRun) skipDraft(repo *v1alpha1.Repository) bool {
+	if repo.Spec.Settings != nil && repo.Spec.Settings.SkipDraft != nil {
+		return *repo.Spec.Settings.SkipDraft
+	}
+	return p.run.Info.Pac != nil && p.run.Info.Pac.Settings != nil && p.run.Info.Pac.Settings.SkipDraft
+}
+
 // getPipelineRunsFromRepo fetches pipelineruns from git repository and prepare them for creation.
 func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Repository) ([]matcher.Match, error) {
 	provenance := "source"
@@ -63,6 +63,47 @@ func TestChangeSecret(t *testing.T) {
 	assert.Assert(t, prs[0].GetAnnotations()[apipac.GitAuthSecret] != "")
 }
 
+func TestSkipDraft(t *testing.T) {
+	tests := []struct {
+		name          string
+		setting       bool
+		repoSkipDraft *bool
+		want          bool
+	}{
+		{
+			name: "not enabled",
+		},
+		{
+			name:    "enabled in the settings",
+			setting: true,
+			want:    true,
+		},
+		{
+			name:          "enabled on the repository",
+			repoSkipDraft: github.Bool(true),
+			want:          true,
+		},
+		{
+			name:          "disabled on the repository",
+			setting:       true,
+			repoSkipDraft: github.Bool(false),
+			want:          false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &v1alpha1.Repository{}
+			if tt.repoSkipDraft != nil {
+				repo.Spec.Settings = &v1alpha1.Settings{SkipDraft: tt.repoSkipDraft}
+			}
+			p := &PacRun{run: &params.Run{Info: info.Info{Pac: &info.PacOpts{
+				Settings: &settings.Settings{SkipDraft: tt.setting},
+			}}}}
+			assert.Equal(t, p.skipDraft(repo), tt.want)
+		})
+	}
+}
+
 func TestFilterRunningPipelineRunOnTargetTest(t *testing.T) {
 	testPipeline := "test"
 	prs := []*tektonv1.PipelineRun{
@@ -17,6 +17,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
@@ -35,6 +36,11 @@ type PacRun struct {
 	logger       *zap.SugaredLogger
 	eventEmitter *events.EventEmitter
 	manager      *ConcurrencyManager
+	// queued is set once the queued status of the event has been reported.
+	queued bool
+	// statusPool bounds how many statuses of the started PipelineRuns are
+	// posted at the same time.
+	statusPool *provider.StatusPool
 }
 
 func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, k8int kubeinteraction.Interface, logger *zap.SugaredLogger) PacRun {
@@ -42,10 +48,32 @@ func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, k8int k
 		event: event, run: run, vcx: vcx, k8int: k8int, logger: logger,
 		eventEmitter: events.NewEventEmitter(run.Clients.Kube, logger),
 		manager:      NewConcurrencyManager(),
+		statusPool:   provider.NewStatusPool(vcx, statusConcurrency(run)),
 	}
 }
 
+// statusConcurrency returns how many statuses are posted at the same time
+// from the settings, provider.DefaultStatusConcurrency when not set.
+func statusConcurrency(run *params.Run) int {
+	if run.Info.Pac == nil || run.Info.Pac.Settings == nil {
+		return provider.DefaultStatusConcurrency
+	}
+	return run.Info.Pac.StatusConcurrency
+}
+
+// statusCreator returns the pool the statuses of the started PipelineRuns are
+// posted through, the provider itself when there is none.
+func (p *PacRun) statusCreator() provider.StatusCreator {
+	if p.statusPool != nil {
+		return p.statusPool
+	}
+	return p.vcx
+}
+
 func (p *PacRun) Run(ctx context.Context) error {
+	if p.event.EventType == github.InstallationRepositoriesEventType {
+		return p.invalidateInstallationRepos(ctx)
+	}
 	matchedPRs, repo, err := p.matchRepoPR(ctx)
 	if err != nil {
 		createStatusErr := p.vcx.CreateStatus(ctx, p.event, provider.StatusOpts{
@@ -59,7 +87,18 @@ func (p *PacRun) Run(ctx context.Context) error {
 			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus", fmt.Sprintf("cannot create status: %s: %s", err, createStatusErr))
 		}
 	}
+	// the check runs left in progress on the previous head of the pull
+	// request would otherwise stay pending forever
+	if err == nil && repo != nil && p.event.PreviousSHA != "" {
+		if err := p.vcx.CancelInProgressCheckRuns(ctx, p.event); err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryCancelCheckRuns",
+				fmt.Sprintf("cannot cancel the check runs of the previous commit %s: %s", p.event.PreviousSHA, err))
+		}
+	}
 	if len(matchedPRs) == 0 {
+		if err == nil {
+			p.completeQueued(ctx)
+		}
 		return nil
 	}
 	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
@@ -204,14 +243,14 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 		}
 	}
 
-	if err := p.vcx.CreateStatus(ctx, p.event, status); err != nil {
+	if err := p.statusCreator().CreateStatus(ctx, p.event, status); err != nil {
 		// we still return the created PR with error, and allow caller to decide what to do with the PR, and avoid
 		// unneeded SIGSEGV's
 		return pr, fmt.Errorf("cannot use the API on the provider platform to create a in_progress status: %w", err)
 	}
 
-	// Patch pipelineRun with logURL annotation, skips for GitHub App as we patch logURL while patching CheckrunID
-	if _, ok := pr.Annotations[keys.InstallationID]; !ok {
+	// Patch pipelineRun with logURL annotation, skips when reporting a check run as we patch logURL while patching CheckrunID
+	if !p.vcx.ReportsCheckRuns(p.event) {
 		pr, err = action.PatchPipelineRun(ctx, p.logger, "logURL", p.run.Clients.Tekton, pr, getLogURLMergePatch(p.run.Clients, pr))
 		if err != nil {
 			// we still return the created PR with error, and allow caller to decide what to do with the PR, and avoid
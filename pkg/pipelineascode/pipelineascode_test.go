@@ -130,6 +130,7 @@ func TestRun(t *testing.T) {
 		WebHookSecretValue           string
 		PayloadEncodedSecret         string
 		concurrencyLimit             int
+		skipDraft                    bool
 		expectedLogSnippet           string
 	}{
 		{
@@ -214,6 +215,32 @@ func TestRun(t *testing.T) {
 			finalStatus:     "neutral",
 			finalStatusText: "<th>Status</th><th>Duration</th><th>Name</th>",
 		},
+		{
+			name: "pull request/draft",
+			runevent: info.Event{
+				Event: &github.PullRequestEvent{
+					PullRequest: &github.PullRequest{
+						Number: github.Int(666),
+					},
+				},
+				SHA:               "fromwebhook",
+				Organization:      "owner",
+				Sender:            "owner",
+				Repository:        "repo",
+				URL:               "https://service/documentation",
+				HeadBranch:        "press",
+				BaseBranch:        "main",
+				EventType:         "pull_request",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 666,
+				PullRequestDraft:  true,
+				InstallationID:    1234,
+			},
+			tektondir:          "testdata/pull_request",
+			finalStatus:        "skipped",
+			skipDraft:          true,
+			expectedLogSnippet: "skipping pull request #666 since it is a draft",
+		},
 		{
 			name: "pull request/concurrency limit",
 			runevent: info.Event{
@@ -545,6 +572,7 @@ func TestRun(t *testing.T) {
 							SecretAutoCreation: true,
 							RemoteTasks:        true,
 							HubCatalogs:        &hubCatalogs,
+							SkipDraft:          tt.skipDraft,
 						},
 					},
 					Controller: &info.ControllerInfo{
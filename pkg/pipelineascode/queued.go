@@ -0,0 +1,49 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// reportQueued reports a queued status as soon as the event has been
+// accepted, fetching the templates, resolving and creating the PipelineRuns
+// can take a while during which nothing would be shown on the commit. It's
+// only done when reporting check runs, where the first PipelineRun started
+// takes over the queued check run.
+func (p *PacRun) reportQueued(ctx context.Context) {
+	if !p.run.Info.Pac.StatusQueued {
+		return
+	}
+	if !p.vcx.ReportsCheckRuns(p.event) {
+		return
+	}
+	if err := p.vcx.CreateStatus(ctx, p.event, provider.StatusOpts{
+		Status:     "queued",
+		Conclusion: "queued",
+		DetailsURL: p.run.Clients.ConsoleUI.URL(),
+	}); err != nil {
+		p.logger.Warnf("cannot report the queued status: %v", err)
+		return
+	}
+	p.queued = true
+}
+
+// completeQueued completes as skipped the queued status when no PipelineRun
+// has matched the event, otherwise it would stay queued forever.
+func (p *PacRun) completeQueued(ctx context.Context) {
+	if !p.queued {
+		return
+	}
+	if err := p.vcx.CreateStatus(ctx, p.event, provider.StatusOpts{
+		Status:     "completed",
+		Conclusion: "skipped",
+		Title:      "Skipped",
+		Summary:    "has no PipelineRun matching this event.",
+		DetailsURL: p.run.Clients.ConsoleUI.URL(),
+	}); err != nil {
+		p.eventEmitter.EmitMessage(nil, zap.ErrorLevel, "RepositoryCreateStatus", fmt.Sprintf("cannot complete the queued status: %s", err))
+	}
+}
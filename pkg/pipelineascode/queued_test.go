@@ -0,0 +1,80 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestReportQueued(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusQueued bool
+		checkRuns    bool
+		erroring     bool
+		wantStatuses []provider.StatusOpts
+	}{
+		{
+			name:         "reported and completed",
+			statusQueued: true,
+			checkRuns:    true,
+			wantStatuses: []provider.StatusOpts{
+				{Status: "queued", Conclusion: "queued", DetailsURL: "https://dashboard.is.not.configured"},
+				{
+					Status:     "completed",
+					Conclusion: "skipped",
+					Title:      "Skipped",
+					Summary:    "has no PipelineRun matching this event.",
+					DetailsURL: "https://dashboard.is.not.configured",
+				},
+			},
+		},
+		{
+			name:      "disabled",
+			checkRuns: true,
+		},
+		{
+			name:         "not reported as check runs",
+			statusQueued: true,
+		},
+		{
+			name:         "not completed when not reported",
+			statusQueued: true,
+			checkRuns:    true,
+			erroring:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			vcx := &testprovider.TestProviderImp{CreateStatusErorring: tt.erroring, CheckRuns: tt.checkRuns}
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:       logger,
+					ConsoleUI: consoleui.FallBackConsole{},
+				},
+				Info: info.Info{
+					Pac: &info.PacOpts{Settings: &settings.Settings{StatusQueued: tt.statusQueued}},
+				},
+			}
+			event := &info.Event{}
+			pac := NewPacs(event, vcx, cs, nil, logger)
+
+			pac.reportQueued(ctx)
+			pac.completeQueued(ctx)
+			assert.DeepEqual(t, vcx.CreatedStatuses, tt.wantStatuses)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+const (
+	// AggregatedPipelineRunName is the name the aggregated status of the
+	// pipelines spanning several repositories is reported under.
+	AggregatedPipelineRunName = "cross-repository"
+	aggregatedExternalID      = "pipelines-as-code-cross-repository"
+)
+
+// ComponentResult is the result of the pipeline of one of the repositories
+// of a pipeline spanning several repositories.
+type ComponentResult struct {
+	// Conclusion is one of success, failure, cancelled, neutral, skipped or
+	// pending when the pipeline is still running.
+	Conclusion string
+	// DetailsURL links to the run of the component.
+	DetailsURL string
+}
+
+// CreateAggregatedStatus posts a single status on the primary repository
+// combining the results of the pipelines of the repositories they are keyed
+// by, linking to each of them. Any failure fails the status, then any
+// cancellation cancels it, and it's pending as long as a component is.
+func CreateAggregatedStatus(ctx context.Context, vcx StatusCreator, primary *info.Event, results map[string]ComponentResult) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no component results to aggregate")
+	}
+
+	repos := make([]string, 0, len(results))
+	for repo := range results {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	counts := map[string]int{}
+	lines := []string{}
+	for _, repo := range repos {
+		result := results[repo]
+		counts[result.Conclusion]++
+		name := repo
+		if result.DetailsURL != "" {
+			name = fmt.Sprintf("[%s](%s)", repo, result.DetailsURL)
+		}
+		lines = append(lines, fmt.Sprintf("* %s: %s", name, result.Conclusion))
+	}
+
+	status := StatusOpts{
+		Status:                  "completed",
+		PipelineRunName:         aggregatedExternalID,
+		OriginalPipelineRunName: AggregatedPipelineRunName,
+		Title:                   "Cross-repository pipeline",
+		Text:                    strings.Join(lines, "\n"),
+	}
+	switch {
+	case counts["failure"] > 0:
+		status.Conclusion = "failure"
+		status.Summary = fmt.Sprintf("has <b>failed</b> on %d of %d repositories.", counts["failure"], len(results))
+	case counts["cancelled"] > 0:
+		status.Conclusion = "cancelled"
+		status.Summary = fmt.Sprintf("has been <b>cancelled</b> on %d of %d repositories.", counts["cancelled"], len(results))
+	case counts["pending"] > 0:
+		status.Status = "in_progress"
+		status.Conclusion = "pending"
+		status.Summary = fmt.Sprintf("is still <b>running</b> on %d of %d repositories.", counts["pending"], len(results))
+	case counts["success"] > 0:
+		status.Conclusion = "success"
+		status.Summary = fmt.Sprintf("has <b>successfully</b> completed on %d repositories.", len(results))
+	default:
+		status.Conclusion = "neutral"
+		status.Summary = fmt.Sprintf("has been <b>skipped</b> on %d repositories.", len(results))
+	}
+	return vcx.CreateStatus(ctx, primary, status)
+}
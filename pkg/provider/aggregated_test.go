@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"gotest.tools/v3/assert"
+)
+
+type fakeStatusCreator struct {
+	err      error
+	statuses []StatusOpts
+	events   []*info.Event
+}
+
+func (f *fakeStatusCreator) CreateStatus(_ context.Context, event *info.Event, status StatusOpts) error {
+	f.events = append(f.events, event)
+	f.statuses = append(f.statuses, status)
+	return f.err
+}
+
+func TestCreateAggregatedStatus(t *testing.T) {
+	primary := &info.Event{Organization: "owner", Repository: "service", SHA: "sha"}
+	tests := []struct {
+		name           string
+		results        map[string]ComponentResult
+		wantStatus     string
+		wantConclusion string
+		wantSummary    string
+		wantText       string
+		wantErr        string
+	}{
+		{
+			name: "two repositories succeeded",
+			results: map[string]ComponentResult{
+				"owner/service": {Conclusion: "success", DetailsURL: "https://console/service"},
+				"owner/deploy":  {Conclusion: "success", DetailsURL: "https://console/deploy"},
+			},
+			wantStatus:     "completed",
+			wantConclusion: "success",
+			wantSummary:    "has <b>successfully</b> completed on 2 repositories.",
+			wantText:       "* [owner/deploy](https://console/deploy): success\n* [owner/service](https://console/service): success",
+		},
+		{
+			name: "one repository failed",
+			results: map[string]ComponentResult{
+				"owner/service": {Conclusion: "success", DetailsURL: "https://console/service"},
+				"owner/deploy":  {Conclusion: "failure", DetailsURL: "https://console/deploy"},
+			},
+			wantStatus:     "completed",
+			wantConclusion: "failure",
+			wantSummary:    "has <b>failed</b> on 1 of 2 repositories.",
+			wantText:       "* [owner/deploy](https://console/deploy): failure\n* [owner/service](https://console/service): success",
+		},
+		{
+			name: "one repository still running",
+			results: map[string]ComponentResult{
+				"owner/service": {Conclusion: "success"},
+				"owner/deploy":  {Conclusion: "pending"},
+			},
+			wantStatus:     "in_progress",
+			wantConclusion: "pending",
+			wantSummary:    "is still <b>running</b> on 1 of 2 repositories.",
+			wantText:       "* owner/deploy: pending\n* owner/service: success",
+		},
+		{
+			name:    "no results",
+			wantErr: "no component results to aggregate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcx := &fakeStatusCreator{}
+			err := CreateAggregatedStatus(context.Background(), vcx, primary, tt.results)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, len(vcx.statuses), 0)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(vcx.statuses), 1)
+			assert.Equal(t, vcx.events[0], primary)
+			status := vcx.statuses[0]
+			assert.Equal(t, status.OriginalPipelineRunName, AggregatedPipelineRunName)
+			assert.Equal(t, status.Status, tt.wantStatus)
+			assert.Equal(t, status.Conclusion, tt.wantConclusion)
+			assert.Equal(t, status.Summary, tt.wantSummary)
+			assert.Equal(t, status.Text, tt.wantText)
+		})
+	}
+}
@@ -36,14 +36,24 @@ func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []str
 	return false, ""
 }
 
+// CancelInProgressCheckRuns TODO: Implement ME.
+func (v *Provider) CancelInProgressCheckRuns(_ context.Context, _ *info.Event) error {
+	return nil
+}
+
+// ReportsCheckRuns returns false, there are only commit statuses.
+func (v *Provider) ReportsCheckRuns(_ *info.Event) bool {
+	return false
+}
+
 // GetTaskURI TODO: Implement ME.
 func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
 	return false, "", nil
 }
 
-const taskStatusTemplate = `| **Status** | **Duration** | **Name** |
-| --- | --- | --- |
-{{range $taskrun := .TaskRunList }}|{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}|{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}|{{ $taskrun.ConsoleLogURL }}|
+const taskStatusTemplate = `| **Status** | **Duration** | **Name** |{{ if .ShowResourceRequests }} **Resource Requests** |{{ end }}
+| --- | --- | --- |{{ if .ShowResourceRequests }} --- |{{ end }}
+{{range $taskrun := .TaskRunList }}|{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}|{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}|{{ $taskrun.ConsoleLogURL }}{{ with $taskrun.PodEvent }}<br>{{ . }}{{ end }}|{{ if $.ShowResourceRequests }}{{ $taskrun.ResourceRequests }}|{{ end }}
 {{ end }}`
 
 func (v *Provider) Validate(_ context.Context, _ *params.Run, _ *info.Event) error {
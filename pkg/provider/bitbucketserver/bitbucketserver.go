@@ -20,7 +20,7 @@ import (
 )
 
 const taskStatusTemplate = `
-{{range $taskrun := .TaskRunList }}* **{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}**  {{ $taskrun.ConsoleLogURL }} *{{ formatDuration $taskrun.Status.StartTime $taskrun.Status.CompletionTime }}*
+{{range $taskrun := .TaskRunList }}* **{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}**  {{ $taskrun.ConsoleLogURL }} *{{ formatDuration $taskrun.Status.StartTime $taskrun.Status.CompletionTime }}*{{ if $.ShowResourceRequests }} ({{ $taskrun.ResourceRequests }}){{ end }}{{ with $taskrun.PodEvent }} - {{ . }}{{ end }}
 {{ end }}`
 
 var _ provider.Interface = (*Provider)(nil)
@@ -41,6 +41,16 @@ func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []str
 	return false, ""
 }
 
+// CancelInProgressCheckRuns TODO: Implement ME.
+func (v *Provider) CancelInProgressCheckRuns(_ context.Context, _ *info.Event) error {
+	return nil
+}
+
+// ReportsCheckRuns returns false, there are only commit statuses.
+func (v *Provider) ReportsCheckRuns(_ *info.Event) bool {
+	return false
+}
+
 // GetTaskURI TODO: Implement ME.
 func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
 	return false, "", nil
@@ -63,6 +73,24 @@ func sanitizeTitle(s string) string {
 	return strings.Split(s, "\n")[0]
 }
 
+// getStatusKey returns the key of the build status of a PipelineRun, the
+// application name followed by the name of the PipelineRun like the check
+// runs on GitHub, so the PipelineRuns of a commit don't overwrite each other
+// statuses and a new run of a PipelineRun updates its status.
+func getStatusKey(statusOpts provider.StatusOpts, pacopts *info.PacOpts, event *info.Event) string {
+	applicationName := pacopts.ApplicationName
+	if event.ApplicationName != "" {
+		applicationName = event.ApplicationName
+	}
+	if statusOpts.OriginalPipelineRunName == "" {
+		return applicationName
+	}
+	if applicationName == "" {
+		return statusOpts.OriginalPipelineRunName
+	}
+	return fmt.Sprintf("%s / %s", applicationName, statusOpts.OriginalPipelineRunName)
+}
+
 func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts provider.StatusOpts) error {
 	detailsURL := event.Provider.URL
 	switch statusOpts.Conclusion {
@@ -75,6 +103,9 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	case "failure":
 		statusOpts.Conclusion = "FAILED"
 		statusOpts.Title = "❌ Failed"
+	case "cancelled":
+		statusOpts.Conclusion = "FAILED"
+		statusOpts.Title = "⏹️ Cancelled"
 	case "pending":
 		statusOpts.Conclusion = "INPROGRESS"
 		statusOpts.Title = "⚡ CI has started"
@@ -92,16 +123,12 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 		return fmt.Errorf("no token has been set, cannot set status")
 	}
 
-	key := statusOpts.PipelineRunName
-	if key == "" {
-		key = statusOpts.Conclusion
-	}
-
+	key := getStatusKey(statusOpts, v.run.Info.Pac, event)
 	_, err := v.Client.DefaultApi.SetCommitStatus(
 		event.SHA,
 		bbv1.BuildStatus{
 			State:       statusOpts.Conclusion,
-			Name:        v.run.Info.Pac.ApplicationName,
+			Name:        key,
 			Key:         key,
 			Description: statusOpts.Title,
 			Url:         detailsURL,
@@ -140,6 +140,14 @@ func TestCreateStatus(t *testing.T) {
 			expectedDescSubstr: "Failed",
 			pacOpts:            pacopts,
 		},
+		{
+			name: "good/cancelled",
+			status: provider.StatusOpts{
+				Conclusion: "cancelled",
+			},
+			expectedDescSubstr: "Cancelled",
+			pacOpts:            pacopts,
+		},
 		{
 			name: "good/pending",
 			status: provider.StatusOpts{
@@ -198,6 +206,52 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestGetStatusKey(t *testing.T) {
+	tests := []struct {
+		name                string
+		applicationName     string
+		repoApplicationName string
+		pipelineRunName     string
+		want                string
+	}{
+		{
+			name:            "application and pipelinerun",
+			applicationName: "Pipelines as Code CI",
+			pipelineRunName: "pr",
+			want:            "Pipelines as Code CI / pr",
+		},
+		{
+			name:            "application only",
+			applicationName: "Pipelines as Code CI",
+			want:            "Pipelines as Code CI",
+		},
+		{
+			name:            "pipelinerun only",
+			pipelineRunName: "pr",
+			want:            "pr",
+		},
+		{
+			name:                "application of the repository",
+			applicationName:     "Pipelines as Code CI",
+			repoApplicationName: "Team CI",
+			pipelineRunName:     "pr",
+			want:                "Team CI / pr",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: tt.applicationName}}
+			event := info.NewEvent()
+			event.ApplicationName = tt.repoApplicationName
+			got := getStatusKey(provider.StatusOpts{
+				PipelineRunName:         tt.pipelineRunName + "-abcde",
+				OriginalPipelineRunName: tt.pipelineRunName,
+			}, pacopts, event)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
 func TestGetFileInsideRepo(t *testing.T) {
 	tests := []struct {
 		name          string
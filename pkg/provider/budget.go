@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// FormatDurationBudget renders the duration of a completed PipelineRun against
+// its budget, i.e: "3m12s / 5m0s budget ✅", nothing is rendered without a
+// budget or when the PipelineRun has not completed.
+func FormatDurationBudget(pr *v1.PipelineRun, budget time.Duration) string {
+	if budget <= 0 || pr == nil || pr.Status.StartTime == nil || pr.Status.CompletionTime == nil {
+		return ""
+	}
+	duration := pr.Status.CompletionTime.Sub(pr.Status.StartTime.Time).Round(time.Second)
+	indicator := "✅"
+	if duration > budget {
+		indicator = "❌"
+	}
+	return fmt.Sprintf("\n\n**Duration:** %s / %s budget %s", duration, budget, indicator)
+}
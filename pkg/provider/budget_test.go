@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFormatDurationBudget(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	makePR := func(duration time.Duration) *v1.PipelineRun {
+		return &v1.PipelineRun{
+			Status: v1.PipelineRunStatus{
+				PipelineRunStatusFields: v1.PipelineRunStatusFields{
+					StartTime:      &metav1.Time{Time: start},
+					CompletionTime: &metav1.Time{Time: start.Add(duration)},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		pr     *v1.PipelineRun
+		budget time.Duration
+		want   string
+	}{
+		{
+			name:   "within budget",
+			pr:     makePR(3*time.Minute + 12*time.Second),
+			budget: 5 * time.Minute,
+			want:   "\n\n**Duration:** 3m12s / 5m0s budget ✅",
+		},
+		{
+			name:   "exactly the budget",
+			pr:     makePR(5 * time.Minute),
+			budget: 5 * time.Minute,
+			want:   "\n\n**Duration:** 5m0s / 5m0s budget ✅",
+		},
+		{
+			name:   "over budget",
+			pr:     makePR(7*time.Minute + 300*time.Millisecond),
+			budget: 5 * time.Minute,
+			want:   "\n\n**Duration:** 7m0s / 5m0s budget ❌",
+		},
+		{
+			name: "no budget",
+			pr:   makePR(time.Minute),
+		},
+		{
+			name:   "not completed",
+			pr:     &v1.PipelineRun{},
+			budget: 5 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, FormatDurationBudget(tt.pr, tt.budget), tt.want)
+		})
+	}
+}
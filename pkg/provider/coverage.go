@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CoverageResultName is the name of the task result with the test coverage
+// percentage of the commit, i.e: "87.5".
+const CoverageResultName = "coverage"
+
+// ParseCoverage parses a coverage percentage, with or without a trailing %,
+// it has to be between 0 and 100.
+func ParseCoverage(value string) (float64, error) {
+	coverage, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coverage %q: %w", value, err)
+	}
+	// NaN is not between 0 and 100 either
+	if !(coverage >= 0 && coverage <= 100) {
+		return 0, fmt.Errorf("invalid coverage %q: must be between 0 and 100", value)
+	}
+	return coverage, nil
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseCoverage(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr string
+	}{
+		{value: "87.5", want: 87.5},
+		{value: " 100% ", want: 100},
+		{value: "0", want: 0},
+		{value: "100.1", wantErr: "must be between 0 and 100"},
+		{value: "-1", wantErr: "must be between 0 and 100"},
+		{value: "NaN", wantErr: "must be between 0 and 100"},
+		{value: "high", wantErr: "invalid coverage \"high\""},
+		{value: "", wantErr: "invalid coverage \"\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseCoverage(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The kinds of the errors reporting a status, for the callers to decide
+// whether to try again later (i.e: ErrRateLimited) or not.
+var (
+	// ErrNoToken is when there is no valid token to report the status with.
+	ErrNoToken = errors.New("no valid token")
+	// ErrRateLimited is when the git provider rate limits our requests.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrPermission is when the token is not allowed to report the status.
+	ErrPermission = errors.New("permission denied")
+)
+
+// StatusError is an error reporting a status of one of the ErrNoToken,
+// ErrRateLimited or ErrPermission kinds, errors.Is matches its kind and the
+// error it wraps.
+type StatusError struct {
+	Kind error
+	Err  error
+}
+
+func NewStatusError(kind, err error) *StatusError {
+	return &StatusError{Kind: kind, Err: err}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// IsRetryableStatusError returns true when reporting the status again later
+// may succeed, i.e: once the rate limit has been reset.
+func IsRetryableStatusError(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	cause := errors.New("API rate limit exceeded")
+	err := fmt.Errorf("cannot create status: %w", NewStatusError(ErrRateLimited, cause))
+
+	assert.Error(t, err, "cannot create status: rate limited: API rate limit exceeded")
+	assert.Assert(t, errors.Is(err, ErrRateLimited))
+	assert.Assert(t, errors.Is(err, cause))
+	assert.Assert(t, !errors.Is(err, ErrPermission))
+	var statusErr *StatusError
+	assert.Assert(t, errors.As(err, &statusErr))
+	assert.Equal(t, statusErr.Kind, ErrRateLimited)
+	assert.Assert(t, IsRetryableStatusError(err))
+
+	assert.Assert(t, !IsRetryableStatusError(NewStatusError(ErrPermission, cause)))
+	assert.Assert(t, !IsRetryableStatusError(NewStatusError(ErrNoToken, cause)))
+	assert.Assert(t, !IsRetryableStatusError(cause))
+}
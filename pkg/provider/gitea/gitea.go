@@ -17,6 +17,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"go.uber.org/zap"
@@ -26,7 +27,7 @@ import (
 const (
 	taskStatusTemplate = `
 <table>
-  <tr><th>Status</th><th>Duration</th><th>Name</th></tr>
+  <tr><th>Status</th><th>Duration</th><th>Name</th>{{- if .ShowResourceRequests }}<th>Resource Requests</th>{{- end }}</tr>
 
 {{- range $taskrun := .TaskRunList }}
 <tr>
@@ -34,8 +35,9 @@ const (
 <td>{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.Status.CompletionTime }}</td><td>
 
 {{ $taskrun.ConsoleLogURL }}
+{{- with $taskrun.PodEvent }}<br>{{ . }}{{- end }}
 
-</td></tr>
+</td>{{- if $.ShowResourceRequests }}<td>{{ $taskrun.ResourceRequests }}</td>{{- end }}</tr>
 {{- end }}
 </table>`
 )
@@ -55,6 +57,16 @@ type Provider struct {
 	run          *params.Run
 }
 
+// CancelInProgressCheckRuns TODO: Implement ME.
+func (v *Provider) CancelInProgressCheckRuns(_ context.Context, _ *info.Event) error {
+	return nil
+}
+
+// ReportsCheckRuns returns false, there are only commit statuses.
+func (v *Provider) ReportsCheckRuns(_ *info.Event) bool {
+	return false
+}
+
 // GetTaskURI TODO: Implement ME.
 func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
 	return false, "", nil
@@ -130,6 +142,12 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	case "neutral":
 		statusOpts.Title = "Unknown"
 		statusOpts.Summary = "doesn't know what happened with this commit."
+	case "skipped":
+		statusOpts.Title = "Skipped"
+		statusOpts.Summary = "has <b>skipped</b> this commit."
+	case "cancelled":
+		statusOpts.Title = "Cancelled"
+		statusOpts.Summary = "has been <b>cancelled</b>."
 	}
 
 	if statusOpts.Status == "in_progress" {
@@ -143,6 +161,7 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	}
 	// gitea show weirdly the <br>
 	statusOpts.Summary = fmt.Sprintf("%s%s %s", v.run.Info.Pac.ApplicationName, onPr, statusOpts.Summary)
+	statusOpts.Summary += provider.FormatParameters(statusOpts.Parameters)
 
 	return v.createStatusCommit(event, v.run.Info.Pac, statusOpts)
 }
@@ -150,12 +169,26 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts, status provider.StatusOpts) error {
 	state := gitea.StatusState(status.Conclusion)
 	switch status.Conclusion {
-	case "neutral":
-		state = gitea.StatusSuccess // We don't have a choice than setting as success, no pending here.c
+	case "neutral", "skipped":
+		// there is no neutral state on the statuses API, same as the GitHub
+		// commit statuses
+		switch pacopts.ClassicStatusSkippedState {
+		case settings.ClassicStatusSkippedStatePending:
+			state = gitea.StatusPending
+		case settings.ClassicStatusSkippedStateLabeled:
+			state = gitea.StatusSuccess
+			status.Title = strings.TrimSpace(status.Title + " (skipped)")
+		default:
+			state = gitea.StatusSuccess
+		}
 	case "pending":
 		if status.Title != "" {
 			state = gitea.StatusPending
 		}
+	case "", "success", "failure":
+	default:
+		// cancelled or anything Gitea doesn't know about
+		state = gitea.StatusError
 	}
 	if status.Status == "in_progress" {
 		state = gitea.StatusPending
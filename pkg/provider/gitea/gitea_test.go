@@ -420,6 +420,68 @@ func TestProvider_CreateStatusCommit(t *testing.T) {
 			wantStatusJSON:  `{"state":"pending","target_url":"","description":"Pipeline run for myapp has been triggered","context":"myapp"}`,
 			wantCommentJSON: `{"body":"\ntime to get started"}`,
 		},
+		{
+			name: "cancelled",
+			args: args{
+				status: provider.StatusOpts{
+					Conclusion: "cancelled",
+					Title:      "Cancelled",
+				},
+				pacopts: &info.PacOpts{Settings: &settings.Settings{
+					ApplicationName: "myapp",
+				}},
+				event: &info.Event{
+					Organization:      "myorg",
+					Repository:        "myrepo",
+					PullRequestNumber: 1,
+					TriggerTarget:     "pull_request",
+					SHA:               "123456",
+				},
+			},
+			wantStatusJSON: `{"state":"error","target_url":"","description":"Cancelled","context":"myapp"}`,
+		},
+		{
+			name: "skipped labeled",
+			args: args{
+				status: provider.StatusOpts{
+					Conclusion: "skipped",
+					Title:      "Skipped",
+				},
+				pacopts: &info.PacOpts{Settings: &settings.Settings{
+					ApplicationName:           "myapp",
+					ClassicStatusSkippedState: settings.ClassicStatusSkippedStateLabeled,
+				}},
+				event: &info.Event{
+					Organization:      "myorg",
+					Repository:        "myrepo",
+					PullRequestNumber: 1,
+					TriggerTarget:     "pull_request",
+					SHA:               "123456",
+				},
+			},
+			wantStatusJSON: `{"state":"success","target_url":"","description":"Skipped (skipped)","context":"myapp"}`,
+		},
+		{
+			name: "neutral as pending",
+			args: args{
+				status: provider.StatusOpts{
+					Conclusion: "neutral",
+					Title:      "Unknown",
+				},
+				pacopts: &info.PacOpts{Settings: &settings.Settings{
+					ApplicationName:           "myapp",
+					ClassicStatusSkippedState: settings.ClassicStatusSkippedStatePending,
+				}},
+				event: &info.Event{
+					Organization:      "myorg",
+					Repository:        "myrepo",
+					PullRequestNumber: 1,
+					TriggerTarget:     "pull_request",
+					SHA:               "123456",
+				},
+			},
+			wantStatusJSON: `{"state":"pending","target_url":"","description":"Unknown","context":"myapp"}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
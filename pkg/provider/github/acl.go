@@ -306,7 +306,7 @@ func (v *Provider) GetStringPullRequestComment(ctx context.Context, runevent *in
 		ListOptions: github.ListOptions{PerPage: v.paginedNumber},
 	}
 	for {
-		comments, resp, err := v.Client.Issues.ListComments(ctx, runevent.Organization, runevent.Repository,
+		comments, resp, err := v.issues().ListComments(ctx, runevent.Organization, runevent.Repository,
 			prNumber, opt)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,188 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// aggregatedCheckRunMutex makes sure the PipelineRuns of a commit reported at
+// the same time don't each create their own aggregated check run.
+var aggregatedCheckRunMutex sync.Mutex
+
+// conclusionSeverity orders the conclusions of the check runs, the aggregated
+// check run gets the most severe conclusion of its PipelineRuns.
+var conclusionSeverity = map[string]int{
+	"success":         0,
+	"skipped":         1,
+	"neutral":         2,
+	"action_required": 3,
+	"cancelled":       4,
+	"timed_out":       5,
+	"failure":         6,
+}
+
+// aggregatedRun is the name and the status of a PipelineRun reported in the
+// aggregated check run.
+type aggregatedRun struct {
+	name       string
+	status     string
+	conclusion string
+}
+
+// isNearCheckRunsLimit checks if the commit has almost as many check runs as
+// GitHub is able to show, from all the GitHub apps.
+func (v *Provider) isNearCheckRunsLimit(ctx context.Context, runevent *info.Event) bool {
+	res, _, err := v.checks().ListCheckRunsForRef(ctx, runevent.Organization, runevent.Repository,
+		runevent.SHA, &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 1}})
+	if err != nil {
+		return false
+	}
+	return res.GetTotal() >= checkRunsAggregationThreshold
+}
+
+// aggregateStatusOpts returns the status opts of the check run aggregating
+// the PipelineRuns reported when being close to the check runs limit.
+func aggregateStatusOpts(statusOpts provider.StatusOpts) provider.StatusOpts {
+	statusOpts.PipelineRunName = aggregatedCheckRunExternalID
+	statusOpts.OriginalPipelineRunName = aggregatedCheckRunName
+	statusOpts.CheckNameSuffix = ""
+	return statusOpts
+}
+
+// getOrCreateAggregatedCheckRun returns the id of the aggregated check run of
+// the commit, the one of the other PipelineRuns of the commit already
+// reported in it first, and records it on the PipelineRun.
+func (v *Provider) getOrCreateAggregatedCheckRun(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts) (*int64, error) {
+	aggregatedCheckRunMutex.Lock()
+	defer aggregatedCheckRunMutex.Unlock()
+
+	checkRunID := v.findAggregatedCheckRunID(ctx, runevent, statusOpts.PipelineRun)
+	aggregatedOpts := aggregateStatusOpts(statusOpts)
+	if checkRunID == nil {
+		checkRunID, _ = v.getExistingCheckRunID(ctx, runevent, aggregatedOpts)
+	}
+	if checkRunID == nil {
+		var err error
+		if checkRunID, err = v.createCheckRunStatus(ctx, runevent, aggregatedOpts); err != nil {
+			return nil, err
+		}
+	}
+	if statusOpts.PipelineRun != nil {
+		id := strconv.FormatInt(*checkRunID, 10)
+		patch := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      map[string]string{keys.AggregatedCheckRunID: id},
+				"annotations": map[string]string{keys.AggregatedCheckRunID: id},
+			},
+		}
+		if _, err := action.PatchPipelineRun(ctx, v.Logger, "aggregated checkRunID", v.Run.Clients.Tekton, statusOpts.PipelineRun, patch); err != nil {
+			return nil, err
+		}
+	}
+	return checkRunID, nil
+}
+
+// findAggregatedCheckRunID returns the id of the aggregated check run recorded
+// on the other PipelineRuns of the commit, nil when there is none.
+func (v *Provider) findAggregatedCheckRunID(ctx context.Context, runevent *info.Event, pr *tektonv1.PipelineRun) *int64 {
+	if pr == nil {
+		return nil
+	}
+	prs, err := v.Run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", keys.SHA, formatting.CleanValueKubernetes(runevent.SHA), keys.AggregatedCheckRunID),
+	})
+	if err != nil {
+		v.Logger.Warnf("cannot list the pipelineruns of commit %s reported in the aggregated check run: %v", runevent.SHA, err)
+		return nil
+	}
+	for _, other := range prs.Items {
+		if id, err := strconv.ParseInt(other.GetLabels()[keys.AggregatedCheckRunID], 10, 64); err == nil {
+			return github.Int64(id)
+		}
+	}
+	return nil
+}
+
+// aggregatedRuns returns the PipelineRuns reported in the aggregated check run
+// with the run being reported taking the place of its PipelineRun.
+func (v *Provider) aggregatedRuns(ctx context.Context, statusOpts provider.StatusOpts, checkRunID int64, current aggregatedRun) []aggregatedRun {
+	runs := []aggregatedRun{current}
+	if statusOpts.PipelineRun == nil {
+		return runs
+	}
+	prs, err := v.Run.Clients.Tekton.TektonV1().PipelineRuns(statusOpts.PipelineRun.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%d", keys.AggregatedCheckRunID, checkRunID),
+	})
+	if err != nil {
+		v.Logger.Warnf("cannot list the pipelineruns reported in the aggregated check run %d: %v", checkRunID, err)
+		return runs
+	}
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		if pr.GetName() == statusOpts.PipelineRun.GetName() {
+			continue
+		}
+		run := aggregatedRun{name: pr.GetName(), status: "in_progress"}
+		if pr.IsDone() {
+			run.status = "completed"
+			run.conclusion = formatting.PipelineRunStatus(pr)
+			if isPipelineRunCancelledOrStopped(pr) {
+				run.conclusion = "cancelled"
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// makeAggregatedCheckRunOptions makes the update of the aggregated check run
+// from the ones of all the PipelineRuns it reports, it is completed when all
+// of them are with the most severe of their conclusions.
+func (v *Provider) makeAggregatedCheckRunOptions(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts, checkRunID int64, opts github.UpdateCheckRunOptions) github.UpdateCheckRunOptions {
+	current := aggregatedRun{name: statusOpts.PipelineRunName, status: opts.GetStatus(), conclusion: opts.GetConclusion()}
+	runs := v.aggregatedRuns(ctx, statusOpts, checkRunID, current)
+
+	completed := true
+	conclusion := "success"
+	lines := make([]string, 0, len(runs))
+	for _, run := range runs {
+		state := run.conclusion
+		if run.status != "completed" {
+			completed = false
+			state = run.status
+		} else if conclusionSeverity[run.conclusion] > conclusionSeverity[conclusion] {
+			conclusion = run.conclusion
+		}
+		lines = append(lines, fmt.Sprintf("* %s: %s", run.name, state))
+	}
+
+	aggregatedOpts := aggregateStatusOpts(statusOpts)
+	opts.Name = getCheckName(v.Logger, aggregatedOpts, v.Run.Info.Pac, runevent)
+	opts.ExternalID = github.String(aggregatedCheckRunExternalID)
+	opts.Actions = nil
+	opts.Output = &github.CheckRunOutput{
+		Title:   github.String(fmt.Sprintf("%d PipelineRuns", len(runs))),
+		Summary: github.String(formatting.TruncateText(strings.Join(lines, "\n"), maxTextSize, "")),
+	}
+	if !completed {
+		opts.Status = github.String("in_progress")
+		opts.Conclusion = nil
+		opts.CompletedAt = nil
+		return opts
+	}
+	opts.Status = github.String("completed")
+	opts.Conclusion = github.String(conclusion)
+	return opts
+}
@@ -0,0 +1,164 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGithubProviderAggregatedCheckRun(t *testing.T) {
+	aggregatedPR := func(name string, condition corev1.ConditionStatus) *tektonv1.PipelineRun {
+		pr := &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns",
+				Labels: map[string]string{
+					keys.SHA:                  "sha",
+					keys.AggregatedCheckRunID: "555",
+				},
+				Annotations: map[string]string{
+					keys.AggregatedCheckRunID: "555",
+				},
+			},
+		}
+		if condition != "" {
+			pr.Status.Status = knativeduckv1.Status{Conditions: knativeduckv1.Conditions{
+				{Type: knativeapi.ConditionSucceeded, Status: condition},
+			}}
+		}
+		return pr
+	}
+
+	tests := []struct {
+		name           string
+		others         []*tektonv1.PipelineRun
+		conclusion     string
+		wantStatus     string
+		wantConclusion string
+		wantTitle      string
+	}{
+		{
+			name:           "worst conclusion of the aggregated runs",
+			others:         []*tektonv1.PipelineRun{aggregatedPR("failed", corev1.ConditionFalse), aggregatedPR("succeeded", corev1.ConditionTrue)},
+			conclusion:     "success",
+			wantStatus:     "completed",
+			wantConclusion: "failure",
+			wantTitle:      "3 PipelineRuns",
+		},
+		{
+			name:           "another aggregated run still running",
+			others:         []*tektonv1.PipelineRun{aggregatedPR("running", "")},
+			conclusion:     "success",
+			wantStatus:     "in_progress",
+			wantConclusion: "",
+			wantTitle:      "2 PipelineRuns",
+		},
+		{
+			name:           "only run aggregated",
+			conclusion:     "failure",
+			wantStatus:     "completed",
+			wantConclusion: "failure",
+			wantTitle:      "1 PipelineRuns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			mux.HandleFunc("/repos/owner/repository/commits/sha/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprint(w, `{"total_count": 950, "check_runs": []}`)
+			})
+			created := 0
+			mux.HandleFunc("/repos/owner/repository/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+				created++
+				_, _ = fmt.Fprint(w, `{"id": 555}`)
+			})
+			updates := []*github.UpdateCheckRunOptions{}
+			mux.HandleFunc("/repos/owner/repository/check-runs/555", func(w http.ResponseWriter, r *http.Request) {
+				checkRun := &github.UpdateCheckRunOptions{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(checkRun))
+				updates = append(updates, checkRun)
+				_, _ = fmt.Fprint(w, `{"id": 555}`)
+			})
+
+			current := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "current",
+					Namespace: "ns",
+					Labels:    map[string]string{keys.SHA: "sha"},
+				},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: append(tt.others, current)})
+			fakelogger, _ := logger.GetLogger()
+			cnx := New()
+			cnx.Client = fakeclient
+			cnx.Logger = fakelogger
+			cnx.Run = params.New()
+			cnx.Run.Clients = clients.Clients{Tekton: stdata.Pipeline}
+			event := &info.Event{
+				Organization: "owner",
+				Repository:   "repository",
+				SHA:          "sha",
+			}
+			err := cnx.getOrUpdateCheckRunStatus(ctx, event, provider.StatusOpts{
+				PipelineRunName:         "current",
+				OriginalPipelineRunName: "current",
+				PipelineRun:             current,
+				Status:                  "completed",
+				Conclusion:              tt.conclusion,
+			})
+			assert.NilError(t, err)
+
+			// the aggregated check run of the other runs is reused
+			if len(tt.others) > 0 {
+				assert.Equal(t, created, 0)
+			} else {
+				assert.Equal(t, created, 1)
+			}
+			patched, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, "current", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, patched.GetAnnotations()[keys.AggregatedCheckRunID], "555")
+			_, hasCheckRunID := patched.GetAnnotations()[keys.CheckRunID]
+			assert.Assert(t, !hasCheckRunID)
+
+			assert.Equal(t, len(updates), 1)
+			assert.Equal(t, updates[0].GetStatus(), tt.wantStatus)
+			assert.Equal(t, updates[0].GetConclusion(), tt.wantConclusion)
+			assert.Equal(t, updates[0].GetExternalID(), aggregatedCheckRunExternalID)
+			assert.Equal(t, updates[0].Output.GetTitle(), tt.wantTitle)
+
+			// the next update of the PipelineRun goes to the aggregated check
+			// run without looking for it again
+			err = cnx.getOrUpdateCheckRunStatus(ctx, event, provider.StatusOpts{
+				PipelineRunName:         "current",
+				OriginalPipelineRunName: "current",
+				PipelineRun:             patched,
+				Status:                  "completed",
+				Conclusion:              tt.conclusion,
+			})
+			assert.NilError(t, err)
+			assert.Equal(t, len(updates), 2)
+			assert.Equal(t, updates[1].GetConclusion(), tt.wantConclusion)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultAcceptHeader is the Accept header of the requests authenticated
+	// with the JWT of the app when none is configured.
+	DefaultAcceptHeader = "application/vnd.github+json"
+	// KnownGoodAPIVersion is the version of the GitHub REST API
+	// Pipelines-as-Code is known to work with, to pin with GitHubAPIVersionEnv.
+	KnownGoodAPIVersion = "2022-11-28"
+	// APIVersionHeader is the header pinning the version of the REST API.
+	APIVersionHeader = "X-GitHub-Api-Version"
+
+	// GitHubAcceptHeaderEnv and GitHubAPIVersionEnv are the environment
+	// variables of the controller configuring the headers, they are not
+	// settings of the ConfigMap.
+	GitHubAcceptHeaderEnv = "PAC_GITHUB_ACCEPT_HEADER"
+	GitHubAPIVersionEnv   = "PAC_GITHUB_API_VERSION"
+)
+
+// AcceptHeader returns the Accept header of the requests authenticated with
+// the JWT of the app, DefaultAcceptHeader when not configured.
+func AcceptHeader() string {
+	if accept := os.Getenv(GitHubAcceptHeaderEnv); accept != "" {
+		return accept
+	}
+	return DefaultAcceptHeader
+}
+
+// APIVersion returns the version of the REST API to pin, empty when the
+// version header should not be sent.
+func APIVersion() string {
+	return os.Getenv(GitHubAPIVersionEnv)
+}
+
+// appHeadersTransport sets the version header on the requests which don't
+// have one already and the Accept header on the requests of the installation
+// tokens.
+type appHeadersTransport struct {
+	base    http.RoundTripper
+	accept  string
+	version string
+}
+
+// isInstallationTokenRequest checks if the request generates a token of an
+// installation, authenticated with the JWT of the app.
+func isInstallationTokenRequest(req *http.Request) bool {
+	return strings.Contains(req.URL.Path, "/app/installations/") && strings.HasSuffix(req.URL.Path, "/access_tokens")
+}
+
+func (t *appHeadersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	setVersion := t.version != "" && req.Header.Get(APIVersionHeader) == ""
+	setAccept := isInstallationTokenRequest(req)
+	if !setVersion && !setAccept {
+		return t.base.RoundTrip(req)
+	}
+	// a RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	if setVersion {
+		req.Header.Set(APIVersionHeader, t.version)
+	}
+	if setAccept {
+		req.Header.Set("Accept", t.accept)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// AppHeadersTransport wraps a transport to pin the version of the REST API on
+// all its requests and to send the configured Accept header with the
+// requests of the installation tokens, which ghinstallation makes with its
+// own Accept header.
+func AppHeadersTransport(base http.RoundTripper) http.RoundTripper {
+	return &appHeadersTransport{base: base, accept: AcceptHeader(), version: APIVersion()}
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAcceptHeader(t *testing.T) {
+	t.Setenv(GitHubAcceptHeaderEnv, "")
+	assert.Equal(t, AcceptHeader(), DefaultAcceptHeader)
+
+	t.Setenv(GitHubAcceptHeaderEnv, "application/vnd.github.v3+json")
+	assert.Equal(t, AcceptHeader(), "application/vnd.github.v3+json")
+}
+
+func TestAPIVersion(t *testing.T) {
+	t.Setenv(GitHubAPIVersionEnv, "")
+	assert.Equal(t, APIVersion(), "")
+
+	t.Setenv(GitHubAPIVersionEnv, KnownGoodAPIVersion)
+	assert.Equal(t, APIVersion(), KnownGoodAPIVersion)
+}
+
+func TestAppHeadersTransport(t *testing.T) {
+	type headers struct {
+		accept  string
+		version string
+	}
+	tests := []struct {
+		name    string
+		accept  string
+		version string
+		path    string
+		header  http.Header
+		want    headers
+	}{
+		{
+			name:   "nothing configured",
+			path:   "/repos/owner/repo",
+			header: http.Header{"Accept": {"application/vnd.github.v3+json"}},
+			want:   headers{accept: "application/vnd.github.v3+json"},
+		},
+		{
+			name:    "version pinned",
+			version: KnownGoodAPIVersion,
+			path:    "/repos/owner/repo",
+			header:  http.Header{"Accept": {"application/vnd.github.v3+json"}},
+			want:    headers{accept: "application/vnd.github.v3+json", version: KnownGoodAPIVersion},
+		},
+		{
+			name:    "version of the request kept",
+			version: KnownGoodAPIVersion,
+			path:    "/repos/owner/repo",
+			header:  http.Header{APIVersionHeader: {"2026-03-10"}},
+			want:    headers{version: "2026-03-10"},
+		},
+		{
+			name:   "default accept header of the installation token",
+			path:   "/app/installations/120/access_tokens",
+			header: http.Header{"Accept": {"application/vnd.github.v3+json"}},
+			want:   headers{accept: DefaultAcceptHeader},
+		},
+		{
+			name:    "configured headers of the installation token",
+			accept:  "application/json",
+			version: KnownGoodAPIVersion,
+			path:    "/api/v3/app/installations/120/access_tokens",
+			header:  http.Header{"Accept": {"application/vnd.github.v3+json"}},
+			want:    headers{accept: "application/json", version: KnownGoodAPIVersion},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(GitHubAcceptHeaderEnv, tt.accept)
+			t.Setenv(GitHubAPIVersionEnv, tt.version)
+			var got headers
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = headers{accept: r.Header.Get("Accept"), version: r.Header.Get(APIVersionHeader)}
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: AppHeadersTransport(http.DefaultTransport)}
+			req, err := http.NewRequest(http.MethodPost, server.URL+tt.path, nil)
+			assert.NilError(t, err)
+			req.Header = tt.header.Clone()
+			res, err := client.Do(req)
+			assert.NilError(t, err)
+			assert.NilError(t, res.Body.Close())
+			assert.Equal(t, got, tt.want)
+			assert.DeepEqual(t, req.Header, tt.header)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+)
+
+const (
+	// DefaultCircuitBreakerFailures is the number of consecutive failures of
+	// the installation lookups on a host opening the circuit when none is set
+	// in the settings.
+	DefaultCircuitBreakerFailures = 5
+	// DefaultCircuitBreakerCooldown is how long the circuit stays open when
+	// none is set in the settings.
+	DefaultCircuitBreakerCooldown = time.Minute
+	// maxCooldownFactor caps the cooldown doubled on every failed probe.
+	maxCooldownFactor = 8
+)
+
+// ErrCircuitOpen is returned when the installation lookups on a host are not
+// attempted because the last ones have failed.
+var ErrCircuitOpen = errors.New("installation endpoint circuit open")
+
+// installationEndpoints is the circuit breaker shared by all installations of
+// the controller, a sick GitHub Enterprise is sick for all the events.
+var installationEndpoints = NewCircuitBreaker(clockwork.NewRealClock())
+
+type circuit struct {
+	failures int
+	// openedAt is when the circuit has been opened, zero when closed.
+	openedAt time.Time
+	// cooldownFactor doubles the cooldown on every failed probe.
+	cooldownFactor int
+	// probingSince is when the single probe of a half-open circuit has been
+	// allowed, zero when there is none in flight.
+	probingSince time.Time
+}
+
+// CircuitBreaker stops the installation lookups on a host after a number of
+// consecutive failures for a cooldown, then allows a single probe (half-open)
+// closing the circuit when it succeeds or opening it again for twice as long
+// when it fails. It's safe for concurrent use.
+type CircuitBreaker struct {
+	mutex    sync.Mutex
+	clock    clockwork.Clock
+	circuits map[string]*circuit
+}
+
+func NewCircuitBreaker(clock clockwork.Clock) *CircuitBreaker {
+	return &CircuitBreaker{
+		clock:    clock,
+		circuits: map[string]*circuit{},
+	}
+}
+
+// Allow returns an error wrapping ErrCircuitOpen when a lookup on the host
+// must not be attempted, in the half-open state only the first caller is
+// allowed to probe the host.
+func (b *CircuitBreaker) Allow(host string, cooldown time.Duration) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok || c.openedAt.IsZero() {
+		return nil
+	}
+	now := b.clock.Now()
+	cooldown *= time.Duration(c.cooldownFactor)
+	if reopenAt := c.openedAt.Add(cooldown); now.Before(reopenAt) {
+		return fmt.Errorf("%w on %s after %d consecutive failures, retrying in %s",
+			ErrCircuitOpen, host, c.failures, reopenAt.Sub(now).Round(time.Second))
+	}
+	// a probe which never reported back doesn't keep the circuit half-open
+	// forever
+	if !c.probingSince.IsZero() && now.Before(c.probingSince.Add(cooldown)) {
+		return fmt.Errorf("%w on %s, waiting for the probe request", ErrCircuitOpen, host)
+	}
+	c.probingSince = now
+	return nil
+}
+
+// Success closes the circuit of the host.
+func (b *CircuitBreaker) Success(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.circuits, host)
+}
+
+// Failure records a failure on the host, the circuit is opened after
+// threshold consecutive failures or when the probe of a half-open circuit
+// fails.
+func (b *CircuitBreaker) Failure(host string, threshold int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+	c.failures++
+	now := b.clock.Now()
+	switch {
+	case !c.openedAt.IsZero():
+		// the probe has failed
+		if c.cooldownFactor < maxCooldownFactor {
+			c.cooldownFactor *= 2
+		}
+		c.openedAt, c.probingSince = now, time.Time{}
+	case c.failures >= threshold:
+		c.openedAt, c.cooldownFactor = now, 1
+	}
+}
+
+// circuitBreakerSettings returns the number of consecutive failures opening
+// the circuit and its cooldown from the settings, the defaults when not set.
+func circuitBreakerSettings(run *params.Run) (int, time.Duration) {
+	failures, cooldown := DefaultCircuitBreakerFailures, DefaultCircuitBreakerCooldown
+	if run.Info.Pac == nil {
+		return failures, cooldown
+	}
+	if run.Info.Pac.GitHubCircuitBreakerFailures > 0 {
+		failures = run.Info.Pac.GitHubCircuitBreakerFailures
+	}
+	if run.Info.Pac.GitHubCircuitBreakerCooldown > 0 {
+		cooldown = time.Duration(run.Info.Pac.GitHubCircuitBreakerCooldown) * time.Second
+	}
+	return failures, cooldown
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := NewCircuitBreaker(clock)
+	cooldown := time.Minute
+
+	// closed until the threshold
+	for i := 0; i < 2; i++ {
+		assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+		b.Failure("ghe.company.com", 3)
+	}
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+	b.Failure("ghe.company.com", 3)
+
+	// open
+	err := b.Allow("ghe.company.com", cooldown)
+	assert.Assert(t, errors.Is(err, ErrCircuitOpen))
+	assert.ErrorContains(t, err, "installation endpoint circuit open on ghe.company.com after 3 consecutive failures, retrying in 1m0s")
+	assert.NilError(t, b.Allow("ghe.other.com", cooldown))
+
+	// half-open, a single probe is allowed
+	clock.Advance(cooldown)
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+	assert.ErrorContains(t, b.Allow("ghe.company.com", cooldown), "waiting for the probe request")
+
+	// the probe has failed, open for twice as long
+	b.Failure("ghe.company.com", 3)
+	clock.Advance(cooldown)
+	assert.Assert(t, errors.Is(b.Allow("ghe.company.com", cooldown), ErrCircuitOpen))
+	clock.Advance(cooldown)
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+
+	// the probe has succeeded, closed
+	b.Success("ghe.company.com")
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+	b.Failure("ghe.company.com", 3)
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+}
+
+func TestCircuitBreakerCooldownCap(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := NewCircuitBreaker(clock)
+	cooldown := time.Minute
+
+	b.Failure("ghe.company.com", 1)
+	for i := 0; i < 10; i++ {
+		clock.Advance(maxCooldownFactor * cooldown)
+		assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+		b.Failure("ghe.company.com", 1)
+	}
+	clock.Advance(maxCooldownFactor*cooldown - time.Second)
+	assert.Assert(t, errors.Is(b.Allow("ghe.company.com", cooldown), ErrCircuitOpen))
+	clock.Advance(time.Second)
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+}
+
+func TestCircuitBreakerStaleProbe(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := NewCircuitBreaker(clock)
+	cooldown := time.Minute
+
+	b.Failure("ghe.company.com", 1)
+	clock.Advance(cooldown)
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+	// the probe never reported back
+	clock.Advance(cooldown)
+	assert.NilError(t, b.Allow("ghe.company.com", cooldown))
+}
+
+func TestGetInstallationsCircuitBreaker(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	calls := 0
+	code := http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+	run := &params.Run{
+		Clients: clients.Clients{HTTP: http.Client{}},
+		Info: info.Info{
+			Pac: &info.PacOpts{Settings: &settings.Settings{
+				GitHubCircuitBreakerFailures: 2,
+				GitHubCircuitBreakerCooldown: 30,
+			}},
+		},
+	}
+	clock := clockwork.NewFakeClock()
+	ip := &Install{run: run, circuitBreaker: NewCircuitBreaker(clock)}
+	installationURL := server.URL + "/app/installations"
+
+	for i := 0; i < 2; i++ {
+		res, err := ip.getInstallations(ctx, installationURL, "jwt")
+		assert.NilError(t, err)
+		res.Body.Close()
+	}
+	_, err := ip.getInstallations(ctx, installationURL, "jwt")
+	assert.Assert(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, calls, 2)
+
+	// the probe succeeds and closes the circuit
+	clock.Advance(30 * time.Second)
+	code = http.StatusOK
+	for i := 0; i < 2; i++ {
+		res, err := ip.getInstallations(ctx, installationURL, "jwt")
+		assert.NilError(t, err)
+		res.Body.Close()
+	}
+	assert.Equal(t, calls, 4)
+}
+
+func TestCircuitBreakerSettings(t *testing.T) {
+	failures, cooldown := circuitBreakerSettings(&params.Run{})
+	assert.Equal(t, failures, DefaultCircuitBreakerFailures)
+	assert.Equal(t, cooldown, DefaultCircuitBreakerCooldown)
+
+	failures, cooldown = circuitBreakerSettings(&params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+		GitHubCircuitBreakerFailures: 3,
+		GitHubCircuitBreakerCooldown: 10,
+	}}}})
+	assert.Equal(t, failures, 3)
+	assert.Equal(t, cooldown, 10*time.Second)
+}
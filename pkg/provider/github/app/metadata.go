@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+)
+
+// GitHubAPIURLOverrideEnv is the environment variable of the controller
+// overriding the API URL of GitHub the app talks to, i.e: to talk to a mock of
+// the GitHub API in the tests. It is not a setting of the ConfigMap.
+const GitHubAPIURLOverrideEnv = "PAC_GITHUB_API_URL_OVERRIDE"
+
+// AppMetadataTTL is how long the metadata of the GitHub App is cached before
+// being fetched again.
+const AppMetadataTTL = time.Hour
+
+// appMetadata is the cache shared by all installations of the controller, the
+// metadata of the app barely ever changes.
+var appMetadata = NewAppMetadataCache(clockwork.NewRealClock(), AppMetadataTTL)
+
+// AppMetadata is the metadata of the GitHub App as returned by the /app
+// endpoint.
+type AppMetadata struct {
+	ID          int64             `json:"id"`
+	Slug        string            `json:"slug"`
+	Name        string            `json:"name"`
+	Permissions map[string]string `json:"permissions"`
+}
+
+type cachedAppMetadata struct {
+	metadata  *AppMetadata
+	fetchedAt time.Time
+}
+
+// AppMetadataCache is an in-memory cache of the metadata of the GitHub App
+// keyed by API URL, safe for concurrent use.
+type AppMetadataCache struct {
+	mutex    sync.Mutex
+	clock    clockwork.Clock
+	ttl      time.Duration
+	metadata map[string]cachedAppMetadata
+}
+
+func NewAppMetadataCache(clock clockwork.Clock, ttl time.Duration) *AppMetadataCache {
+	return &AppMetadataCache{
+		clock:    clock,
+		ttl:      ttl,
+		metadata: map[string]cachedAppMetadata{},
+	}
+}
+
+// Get returns the cached metadata of the app for an API URL if they have been
+// fetched less than the TTL ago.
+func (c *AppMetadataCache) Get(apiURL string) (*AppMetadata, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, ok := c.metadata[apiURL]
+	if !ok {
+		return nil, false
+	}
+	if c.clock.Since(cached.fetchedAt) >= c.ttl {
+		delete(c.metadata, apiURL)
+		return nil, false
+	}
+	return cached.metadata, true
+}
+
+// Set stores the metadata of the app for an API URL.
+func (c *AppMetadataCache) Set(apiURL string, metadata *AppMetadata) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.metadata[apiURL] = cachedAppMetadata{
+		metadata:  metadata,
+		fetchedAt: c.clock.Now(),
+	}
+}
+
+// AppMetadata returns the id, slug, name and permissions of the GitHub App,
+// they are fetched with the JWT of the app and cached for AppMetadataTTL.
+func (ip *Install) AppMetadata(ctx context.Context) (*AppMetadata, error) {
+	_, apiURL := ip.apiURL()
+	if metadata, ok := ip.metadataCache.Get(apiURL); ok {
+		return metadata, nil
+	}
+
+	jwtToken, err := ip.GenerateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := ip.getReponseReauth(ctx, http.MethodGet, apiURL+"/app", jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("Non-OK HTTP status while getting app metadata: %s/app : %d", apiURL, res.StatusCode)
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &AppMetadata{}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, err
+	}
+	ip.metadataCache.Set(apiURL, metadata)
+	return metadata, nil
+}
+
+// explainMissingPermissions tells from the metadata of the GitHub App whether
+// the permissions missing on the installation are not requested by the app or
+// are still to be accepted on the installation, other errors are returned as
+// is.
+func (ip *Install) explainMissingPermissions(ctx context.Context, err error) error {
+	var missingErr *github.MissingPermissionsError
+	if !errors.As(err, &missingErr) {
+		return err
+	}
+	metadata, metadataErr := ip.AppMetadata(ctx)
+	if metadataErr != nil {
+		if ip.run.Clients.Log != nil {
+			ip.run.Clients.Log.Debugf("cannot get the metadata of the github app to explain the missing permissions: %v", metadataErr)
+		}
+		return err
+	}
+	if notRequested := missingErr.NotRequested(metadata.Permissions); len(notRequested) > 0 {
+		return fmt.Errorf("%w, the github app %s doesn't request %s, it has to be added to the permissions of the app",
+			err, metadata.Slug, strings.Join(notRequested, ", "))
+	}
+	return fmt.Errorf("%w, the github app %s requests it, the new permissions of the app have to be accepted on the installation",
+		err, metadata.Slug)
+}
+
+// apiURL returns the enterprise host of the request if any and the API URL of
+// GitHub we need to talk to as the app. The API URL override, when set, takes
+// precedence over the enterprise host of the request.
+func (ip *Install) apiURL() (string, string) {
+	apiURL := keys.PublicGithubAPIURL
+	if ip.ghClient != nil && ip.ghClient.APIURL != nil {
+		apiURL = *ip.ghClient.APIURL
+	}
+	// NOTE: Hopefully this works even when the ghe URL is on another host than the api URL
+	enterpriseHost := ip.request.Header.Get("X-GitHub-Enterprise-Host")
+	if enterpriseHost != "" {
+		apiURL = info.GitHubAPIURL(enterpriseHost)
+	}
+	if override := os.Getenv(GitHubAPIURLOverrideEnv); override != "" {
+		apiURL = strings.TrimSuffix(override, "/")
+	}
+	return enterpriseHost, apiURL
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestAppMetadata(t *testing.T) {
+	tdata := testclient.Data{
+		Namespaces: []*corev1.Namespace{testNamespace},
+		Secret:     []*corev1.Secret{validSecret},
+	}
+	fakeghclient, mux, serverURL, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	apiURL := serverURL + "/api/v3"
+
+	appRequests := 0
+	mux.HandleFunc("/app", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		assert.Assert(t, r.Header.Get("Authorization") != "")
+		appRequests++
+		_, _ = fmt.Fprint(w, `{"id": 274799, "slug": "pipelines-as-code", "name": "Pipelines as Code", "permissions": {"checks": "write", "contents": "read"}}`)
+	})
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+	logger, _ := logger.GetLogger()
+	run := &params.Run{
+		Clients: clients.Clients{
+			Log:            logger,
+			PipelineAsCode: stdata.PipelineAsCode,
+			Kube:           stdata.Kube,
+		},
+		Info: info.Info{
+			Pac: &info.PacOpts{
+				Settings: &settings.Settings{},
+			},
+			Controller: &info.ControllerInfo{Secret: validSecret.GetName()},
+		},
+	}
+	ctx = info.StoreNS(ctx, testNamespace.GetName())
+
+	clock := clockwork.NewFakeClock()
+	cache := NewAppMetadataCache(clock, time.Hour)
+	for i := 0; i < 2; i++ {
+		// a new installation for each event, the cache is shared between them
+		gprovider := &github.Provider{Client: fakeghclient, APIURL: &apiURL, Run: run}
+		ip := NewInstallation(nil, run, nil, gprovider, testNamespace.GetName())
+		ip.metadataCache = cache
+		metadata, err := ip.AppMetadata(ctx)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, metadata, &AppMetadata{
+			ID:          274799,
+			Slug:        "pipelines-as-code",
+			Name:        "Pipelines as Code",
+			Permissions: map[string]string{"checks": "write", "contents": "read"},
+		})
+	}
+	assert.Equal(t, appRequests, 1, "app metadata should have been served from the cache")
+
+	clock.Advance(time.Hour)
+	gprovider := &github.Provider{Client: fakeghclient, APIURL: &apiURL, Run: run}
+	ip := NewInstallation(nil, run, nil, gprovider, testNamespace.GetName())
+	ip.metadataCache = cache
+	_, err := ip.AppMetadata(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, appRequests, 2, "app metadata should have been fetched again after the TTL")
+}
+
+func TestAPIURL(t *testing.T) {
+	clientURL := "https://client.example.com/api/v3"
+	tests := []struct {
+		name               string
+		clientURL          *string
+		enterpriseHost     string
+		envOverride        string
+		wantEnterpriseHost string
+		wantAPIURL         string
+	}{
+		{
+			name:       "public github",
+			wantAPIURL: keys.PublicGithubAPIURL,
+		},
+		{
+			name:       "api url of the client",
+			clientURL:  &clientURL,
+			wantAPIURL: clientURL,
+		},
+		{
+			name:               "enterprise host",
+			clientURL:          &clientURL,
+			enterpriseHost:     "ghe.example.com",
+			wantEnterpriseHost: "ghe.example.com",
+			wantAPIURL:         "https://ghe.example.com/api/v3",
+		},
+		{
+			name:               "override over the enterprise host",
+			enterpriseHost:     "ghe.example.com",
+			envOverride:        "http://localhost:8080/api/v3/",
+			wantEnterpriseHost: "ghe.example.com",
+			wantAPIURL:         "http://localhost:8080/api/v3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(GitHubAPIURLOverrideEnv, tt.envOverride)
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", nil)
+			if tt.enterpriseHost != "" {
+				req.Header.Set("X-GitHub-Enterprise-Host", tt.enterpriseHost)
+			}
+			run := &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}}}
+			ip := NewInstallation(req, run, nil, &github.Provider{APIURL: tt.clientURL}, testNamespace.GetName())
+			enterpriseHost, apiURL := ip.apiURL()
+			assert.Equal(t, enterpriseHost, tt.wantEnterpriseHost)
+			assert.Equal(t, apiURL, tt.wantAPIURL)
+		})
+	}
+}
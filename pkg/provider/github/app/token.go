@@ -3,20 +3,41 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	gt "github.com/google/go-github/v59/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
 )
 
+const (
+	// DefaultJWTExpiration is the expiration of the JWT when none is set.
+	DefaultJWTExpiration = 5 * time.Minute
+	// MaxJWTExpiration is the longest expiration GitHub accepts for a JWT.
+	MaxJWTExpiration = settings.MaxGitHubJWTExpiration * time.Second
+	// jwtIssuedAtDrift backdates the JWT to tolerate the clock drift with
+	// GitHub, as recommended by the GitHub documentation.
+	jwtIssuedAtDrift = 60 * time.Second
+	// DefaultRequestTimeout is the timeout of the requests made by GetReponse
+	// when none is set in the settings.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultRepoListCacheTTL is how long the repositories of an installation
+	// are cached when it is not set in the settings.
+	DefaultRepoListCacheTTL = 5 * time.Minute
+)
+
 type Install struct {
 	request   *http.Request
 	run       *params.Run
@@ -24,7 +45,11 @@ type Install struct {
 	ghClient  *github.Provider
 	namespace string
 
-	repoList []string
+	repoList       []string
+	tokenCache     *TokenCache
+	metadataCache  *AppMetadataCache
+	circuitBreaker *CircuitBreaker
+	repoCache      *github.RepoListCache
 }
 
 func NewInstallation(req *http.Request, run *params.Run, repo *v1alpha1.Repository, gh *github.Provider, namespace string) *Install {
@@ -32,11 +57,15 @@ func NewInstallation(req *http.Request, run *params.Run, repo *v1alpha1.Reposito
 		req = &http.Request{}
 	}
 	return &Install{
-		request:   req,
-		run:       run,
-		repo:      repo,
-		ghClient:  gh,
-		namespace: namespace,
+		request:        req,
+		run:            run,
+		repo:           repo,
+		ghClient:       gh,
+		namespace:      namespace,
+		tokenCache:     installationTokens,
+		metadataCache:  appMetadata,
+		circuitBreaker: installationEndpoints,
+		repoCache:      github.InstallationRepos(),
 	}
 }
 
@@ -50,23 +79,19 @@ func (ip *Install) GetAndUpdateInstallationID(ctx context.Context) (string, stri
 		return "", "", 0, err
 	}
 
-	installationURL := *ip.ghClient.APIURL + keys.InstallationURL
-	enterpriseHost = ip.request.Header.Get("X-GitHub-Enterprise-Host")
-	if enterpriseHost != "" {
-		// NOTE: Hopefully this works even when the ghe URL is on another host than the api URL
-		installationURL = "https://" + enterpriseHost + "/api/v3" + keys.InstallationURL
-	}
+	enterpriseHost, apiURL := ip.apiURL()
+	installationURL := apiURL + keys.InstallationURL
 
-	res, err := GetReponse(ctx, http.MethodGet, installationURL, jwtToken, ip.run)
+	res, err := ip.getInstallations(ctx, installationURL, jwtToken)
 	if err != nil {
 		return "", "", 0, err
 	}
 
+	defer res.Body.Close()
 	if res.StatusCode >= 300 {
 		return "", "", 0, fmt.Errorf("Non-OK HTTP status while getting installation URL: %s : %d", installationURL, res.StatusCode)
 	}
 
-	defer res.Body.Close()
 	data, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", "", 0, err
@@ -77,21 +102,58 @@ func (ip *Install) GetAndUpdateInstallationID(ctx context.Context) (string, stri
 		return "", "", 0, err
 	}
 
+	// sort the installations so we always pick the same one when the
+	// repository is reachable through several of them
+	sort.Slice(installationData, func(i, j int) bool {
+		return installationData[i].GetID() < installationData[j].GetID()
+	})
+
 	/* each installationID can have list of repository
 	ref: https://docs.github.com/en/developers/apps/building-github-apps/authenticating-with-github-apps#authenticating-as-an-installation ,
 	     https://docs.github.com/en/rest/apps/installations?apiVersion=2022-11-28#list-repositories-accessible-to-the-app-installation */
+	var permissionErr, suspendedErr error
 	for i := range installationData {
 		if installationData[i].ID == nil {
 			return "", "", 0, fmt.Errorf("installation ID is nil")
 		}
+		// a suspended installation cannot do anything on the repositories,
+		// i.e: its statuses would silently fail
+		if installationData[i].SuspendedAt != nil {
+			err := fmt.Errorf("installation %d on %s is suspended since %s", *installationData[i].ID,
+				installationData[i].GetAccount().GetLogin(), installationData[i].GetSuspendedAt().Format(time.RFC3339))
+			if ip.run.Clients.Log != nil {
+				ip.run.Clients.Log.Warnf("skipping installation %d: %v", *installationData[i].ID, err)
+			}
+			if ip.isRepositoryOwner(installationData[i].GetAccount().GetLogin()) {
+				suspendedErr = err
+			}
+			continue
+		}
 		if *installationData[i].ID != 0 {
-			token, err = ip.ghClient.GetAppToken(ctx, ip.run.Clients.Kube, enterpriseHost, *installationData[i].ID, ip.namespace)
+			token, err = ip.getInstallationToken(ctx, enterpriseHost, *installationData[i].ID)
 			if err != nil {
-				return "", "", 0, err
+				// the repository may be reachable through another
+				// installation allowed to write the check runs
+				var missingErr *github.MissingPermissionsError
+				if errors.As(err, &missingErr) && i < len(installationData)-1 {
+					if ip.run.Clients.Log != nil {
+						ip.run.Clients.Log.Warnf("skipping installation %d: %v", *installationData[i].ID, err)
+					}
+					permissionErr = err
+					continue
+				}
+				return "", "", 0, ip.explainMissingPermissions(ctx, err)
 			}
 		}
-		exist, err := ip.listRepos(ctx)
+		// the repositories are the ones accessible to the installation token
+		ip.repoList = nil
+		exist, err := ip.listRepos(ctx, enterpriseHost, *installationData[i].ID)
 		if err != nil {
+			if isUnauthorized(err) {
+				// the token may have been revoked, make sure we don't reuse it
+				ip.tokenCache.Invalidate(enterpriseHost, *installationData[i].ID)
+				ip.repoCache.Invalidate(enterpriseHost, *installationData[i].ID)
+			}
 			return "", "", 0, err
 		}
 		if exist {
@@ -99,24 +161,140 @@ func (ip *Install) GetAndUpdateInstallationID(ctx context.Context) (string, stri
 			break
 		}
 	}
+	if installationID == 0 && suspendedErr != nil {
+		return "", "", 0, suspendedErr
+	}
+	if installationID == 0 && permissionErr != nil {
+		return "", "", 0, ip.explainMissingPermissions(ctx, permissionErr)
+	}
 	return enterpriseHost, token, installationID, nil
 }
 
-func (ip *Install) listRepos(ctx context.Context) (bool, error) {
+// getInstallations requests the installations of the app through the circuit
+// breaker of the host, the errors and the server errors are failures of the
+// host.
+func (ip *Install) getInstallations(ctx context.Context, installationURL, jwtToken string) (*http.Response, error) {
+	host := installationURL
+	if u, err := url.Parse(installationURL); err == nil {
+		host = u.Host
+	}
+	failures, cooldown := circuitBreakerSettings(ip.run)
+	if err := ip.circuitBreaker.Allow(host, cooldown); err != nil {
+		return nil, err
+	}
+	res, err := ip.getReponseReauth(ctx, http.MethodGet, installationURL, jwtToken)
+	if err != nil || res.StatusCode >= http.StatusInternalServerError {
+		ip.circuitBreaker.Failure(host, failures)
+	} else {
+		ip.circuitBreaker.Success(host)
+	}
+	return res, err
+}
+
+// getReponseReauth sends a request authenticated with the JWT of the app, when
+// GitHub answers 401 (i.e: the JWT has expired, the clocks are skewed or the
+// private key has been rotated) the private key is loaded again from the
+// secret, a new JWT is generated and the request is retried once.
+func (ip *Install) getReponseReauth(ctx context.Context, method, urlData, jwtToken string) (*http.Response, error) {
+	res, err := GetReponse(ctx, method, urlData, jwtToken, ip.run)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	res.Body.Close()
+	if ip.run.Clients.Log != nil {
+		ip.run.Clients.Log.Infof("request to %s is unauthorized, retrying once with a new jwt", urlData)
+	}
+	ip.appProvider().InvalidateAppKey(ip.namespace)
+	if jwtToken, err = ip.GenerateJWT(ctx); err != nil {
+		return nil, err
+	}
+	return GetReponse(ctx, method, urlData, jwtToken, ip.run)
+}
+
+// isRepositoryOwner returns true when the account is the owner of the
+// repository, i.e: the installation is the one of the repository.
+func (ip *Install) isRepositoryOwner(account string) bool {
+	if ip.repo == nil || account == "" {
+		return false
+	}
+	owner, _, err := formatting.GetRepoOwnerSplitted(ip.repo.Spec.URL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(owner, account)
+}
+
+// getInstallationToken returns the token of an installation from the cache if
+// we have a valid one or generate a new one.
+func (ip *Install) getInstallationToken(ctx context.Context, enterpriseHost string, installationID int64) (string, error) {
+	if token, applicationID, ok := ip.tokenCache.Get(enterpriseHost, installationID, ip.ghClient.RepositoryIDs); ok {
+		ip.ghClient.InitClientFromToken(ctx, enterpriseHost, installationID, token)
+		ip.ghClient.ApplicationID = &applicationID
+		return token, nil
+	}
+	token, err := ip.ghClient.GetAppToken(ctx, ip.run.Clients.Kube, enterpriseHost, installationID, ip.namespace)
+	if err != nil {
+		return "", err
+	}
+	var applicationID int64
+	if ip.ghClient.ApplicationID != nil {
+		applicationID = *ip.ghClient.ApplicationID
+	}
+	ip.tokenCache.Set(enterpriseHost, installationID, ip.ghClient.RepositoryIDs, applicationID, token, ip.ghClient.TokenExpiresAt())
+	return token, nil
+}
+
+func isUnauthorized(err error) bool {
+	var ghErr *gt.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusUnauthorized
+}
+
+// listRepos returns true when the repository is accessible to the
+// installation, its repositories are cached for the TTL of the settings. When
+// the repository is not in the cached ones they are listed again once, it may
+// have been added to the installation since they have been cached.
+func (ip *Install) listRepos(ctx context.Context, enterpriseHost string, installationID int64) (bool, error) {
+	cached := false
 	if ip.repoList == nil {
-		var err error
-		ip.repoList, err = github.ListRepos(ctx, ip.ghClient)
-		if err != nil {
+		if repos, ok := ip.repoCache.Get(enterpriseHost, installationID); ok {
+			ip.repoList = repos
+			cached = true
+		}
+	}
+	if ip.repoList == nil {
+		if err := ip.refreshRepoList(ctx, enterpriseHost, installationID); err != nil {
 			return false, err
 		}
 	}
+	if ip.hasRepo() || !cached {
+		return ip.hasRepo(), nil
+	}
+	if err := ip.refreshRepoList(ctx, enterpriseHost, installationID); err != nil {
+		return false, err
+	}
+	return ip.hasRepo(), nil
+}
+
+// refreshRepoList lists the repositories of the installation and caches them.
+func (ip *Install) refreshRepoList(ctx context.Context, enterpriseHost string, installationID int64) error {
+	repos, err := github.ListRepos(ctx, ip.ghClient)
+	if err != nil {
+		return err
+	}
+	ip.repoList = repos
+	ip.repoCache.Set(enterpriseHost, installationID, repos, repoListCacheTTL(ip.run))
+	return nil
+}
+
+// hasRepo returns true when the repository is in the listed repositories of
+// the installation.
+func (ip *Install) hasRepo() bool {
 	for i := range ip.repoList {
-		// If URL matches with repo spec url then we can break for loop
-		if ip.repoList[i] == ip.repo.Spec.URL {
-			return true, nil
+		if formatting.SameRepoURL(ip.repoList[i], ip.repo.Spec.URL) {
+			return true
 		}
 	}
-	return false, nil
+	return false
 }
 
 type JWTClaim struct {
@@ -124,54 +302,154 @@ type JWTClaim struct {
 	jwt.RegisteredClaims
 }
 
+// GenerateJWT generates the JWT of the app signed with its private key, when
+// signing fails the private key is read again from the secret once before
+// failing, in case it has just been rotated.
 func (ip *Install) GenerateJWT(ctx context.Context) (string, error) {
+	gh := ip.appProvider()
+	tokenString, err := ip.signJWT(ctx, gh)
+	var signErr *jwtSignError
+	if errors.As(err, &signErr) {
+		gh.InvalidateAppKey(ip.namespace)
+		return ip.signJWT(ctx, gh)
+	}
+	return tokenString, err
+}
+
+// appProvider returns a provider loading the private key of the GitHub App.
+func (ip *Install) appProvider() *github.Provider {
 	// TODO: move this out of here
 	gh := github.New()
 	gh.Run = ip.run
-	applicationID, privateKey, err := gh.GetAppIDAndPrivateKey(ctx, ip.namespace, ip.run.Clients.Kube)
+	gh.Logger = ip.run.Clients.Log
+	return gh
+}
+
+// jwtSignError is returned when the JWT cannot be signed with the private key.
+type jwtSignError struct {
+	err error
+}
+
+func (e *jwtSignError) Error() string {
+	return fmt.Sprintf("failed to sign private key: %v", e.err)
+}
+
+func (e *jwtSignError) Unwrap() error {
+	return e.err
+}
+
+func (ip *Install) signJWT(ctx context.Context, gh *github.Provider) (string, error) {
+	applicationID, privateKey, err := gh.GetAppIDAndRSAPrivateKey(ctx, ip.namespace, ip.run.Clients.Kube)
 	if err != nil {
 		return "", err
 	}
 
+	expiration := jwtExpiration(ip.run)
+
 	// The expirationTime claim identifies the expiration time on or after which the JWT MUST NOT be accepted for processing.
 	// Value cannot be longer duration.
 	// See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.4
-	expirationTime := time.Now().Add(5 * time.Minute)
+	now := time.Now()
 	claims := &JWTClaim{
 		Issuer: applicationID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
+			IssuedAt:  jwt.NewNumericDate(now.Add(-jwtIssuedAtDrift)),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
 
-	parsedPK, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	tokenString, err := token.SignedString(privateKey)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse private key: %w", err)
+		return "", &jwtSignError{err: err}
 	}
+	return tokenString, nil
+}
 
-	tokenString, err := token.SignedString(parsedPK)
-	if err != nil {
-		return "", fmt.Errorf("failed to sign private key: %w", err)
+// jwtExpiration returns the expiration of the JWT from the settings,
+// DefaultJWTExpiration when not set and GitHub's maximum when it's longer.
+func jwtExpiration(run *params.Run) time.Duration {
+	if run.Info.Pac == nil || run.Info.Pac.GitHubJWTExpiration <= 0 {
+		return DefaultJWTExpiration
 	}
-	return tokenString, nil
+	expiration := time.Duration(run.Info.Pac.GitHubJWTExpiration) * time.Second
+	if expiration > MaxJWTExpiration {
+		return MaxJWTExpiration
+	}
+	return expiration
+}
+
+// requestTimeout returns the timeout of the requests to GitHub from the
+// settings, DefaultRequestTimeout when not set.
+func requestTimeout(run *params.Run) time.Duration {
+	if run.Info.Pac == nil || run.Info.Pac.GitHubRequestTimeout <= 0 {
+		return DefaultRequestTimeout
+	}
+	return time.Duration(run.Info.Pac.GitHubRequestTimeout) * time.Second
 }
 
+// repoListCacheTTL returns how long the repositories of an installation are
+// cached from the settings, DefaultRepoListCacheTTL when not set.
+func repoListCacheTTL(run *params.Run) time.Duration {
+	if run.Info.Pac == nil || run.Info.Pac.GitHubRepoListCacheTTL <= 0 {
+		return DefaultRepoListCacheTTL
+	}
+	return time.Duration(run.Info.Pac.GitHubRepoListCacheTTL) * time.Second
+}
+
+// cancelOnCloseBody cancels the context of the request once the body of the
+// response has been closed, the body cannot be read anymore after that.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// GetReponse sends a request to GitHub authenticated with the JWT of the app,
+// the request fails when GitHub has not answered within the request timeout.
+// The caller has to close the body of the response.
 func GetReponse(ctx context.Context, method, urlData, jwtToken string, run *params.Run) (*http.Response, error) {
 	rawurl, err := url.Parse(urlData)
 	if err != nil {
 		return nil, err
 	}
 
+	timeout := requestTimeout(run)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	newreq, err := http.NewRequestWithContext(ctx, method, rawurl.String(), nil)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 	newreq.Header = map[string][]string{
-		"Accept":        {"application/vnd.github+json"},
+		"Accept":        {github.AcceptHeader()},
 		"Authorization": {fmt.Sprintf("Bearer %s", jwtToken)},
 	}
-	res, err := run.Clients.HTTP.Do(newreq)
-	return res, err
+	if version := github.APIVersion(); version != "" {
+		newreq.Header.Set(github.APIVersionHeader, version)
+	}
+	client := run.Clients.HTTP
+	tr, err := github.NewTransport(run.Info.Pac)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if tr != http.DefaultTransport {
+		client.Transport = tr
+	}
+	client.Transport = github.RateLimitedTransport(rawurl.Host, 0, client.Transport)
+	res, err := client.Do(newreq)
+	if err != nil {
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("request to %s timed out after %s: %w", rawurl.Redacted(), timeout, err)
+		}
+		return nil, fmt.Errorf("request to %s failed: %w", rawurl.Redacted(), err)
+	}
+	res.Body = &cancelOnCloseBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
 }
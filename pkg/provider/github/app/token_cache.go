@@ -0,0 +1,112 @@
+package app
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// tokenMinValidity is how long a cached installation token needs to be still
+// valid for before we reuse it instead of generating a new one.
+const tokenMinValidity = 5 * time.Minute
+
+// installationTokens is the cache shared by all installations of the
+// controller, tokens are valid for an hour so there is no need to generate a
+// new one on every event.
+var installationTokens = NewTokenCache(clockwork.NewRealClock())
+
+type tokenCacheKey struct {
+	enterpriseHost string
+	installationID int64
+	// repositoryIDs are the sorted repository IDs the token is scoped to, a
+	// token scoped to some repositories cannot be used for others.
+	repositoryIDs string
+}
+
+func makeTokenCacheKey(enterpriseHost string, installationID int64, repositoryIDs []int64) tokenCacheKey {
+	ids := make([]string, 0, len(repositoryIDs))
+	for _, id := range repositoryIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	sort.Strings(ids)
+	return tokenCacheKey{
+		enterpriseHost: enterpriseHost,
+		installationID: installationID,
+		repositoryIDs:  strings.Join(ids, ","),
+	}
+}
+
+type cachedToken struct {
+	token         string
+	applicationID int64
+	expiresAt     time.Time
+}
+
+// TokenCache is an in-memory cache of GitHub installation tokens keyed by
+// installation ID, enterprise host and the repositories they are scoped to,
+// safe for concurrent use.
+type TokenCache struct {
+	mutex  sync.Mutex
+	clock  clockwork.Clock
+	tokens map[tokenCacheKey]cachedToken
+}
+
+func NewTokenCache(clock clockwork.Clock) *TokenCache {
+	return &TokenCache{
+		clock:  clock,
+		tokens: map[tokenCacheKey]cachedToken{},
+	}
+}
+
+// Get returns the cached token for an installation scoped to the repository
+// IDs, and the ID of the application which generated it, if it is still valid
+// for more than tokenMinValidity.
+func (c *TokenCache) Get(enterpriseHost string, installationID int64, repositoryIDs []int64) (string, int64, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := makeTokenCacheKey(enterpriseHost, installationID, repositoryIDs)
+	cached, ok := c.tokens[key]
+	if !ok {
+		return "", 0, false
+	}
+	if c.clock.Now().Add(tokenMinValidity).After(cached.expiresAt) {
+		delete(c.tokens, key)
+		return "", 0, false
+	}
+	return cached.token, cached.applicationID, true
+}
+
+// Set stores a token for an installation scoped to the repository IDs until
+// expiresAt, a zero expiresAt is not cached since we would not know when to
+// regenerate it.
+func (c *TokenCache) Set(enterpriseHost string, installationID int64, repositoryIDs []int64, applicationID int64, token string, expiresAt time.Time) {
+	if token == "" || expiresAt.IsZero() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.tokens[makeTokenCacheKey(enterpriseHost, installationID, repositoryIDs)] = cachedToken{
+		token:         token,
+		applicationID: applicationID,
+		expiresAt:     expiresAt,
+	}
+}
+
+// Invalidate removes the cached tokens of an installation whatever their
+// scope, i.e: when GitHub replied with a 401 while using one.
+func (c *TokenCache) Invalidate(enterpriseHost string, installationID int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.tokens {
+		if key.enterpriseHost == enterpriseHost && key.installationID == installationID {
+			delete(c.tokens, key)
+		}
+	}
+}
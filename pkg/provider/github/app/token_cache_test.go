@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"gotest.tools/v3/assert"
+)
+
+func TestTokenCache(t *testing.T) {
+	tests := []struct {
+		name           string
+		expiresIn      time.Duration
+		enterpriseHost string
+		lookupHost     string
+		lookupID       int64
+		scope          []int64
+		lookupScope    []int64
+		invalidate     bool
+		wantToken      string
+		wantFound      bool
+	}{
+		{
+			name:      "token still valid",
+			expiresIn: time.Hour,
+			lookupID:  1,
+			wantToken: "TOKEN",
+			wantFound: true,
+		},
+		{
+			name:      "token expiring in less than five minutes",
+			expiresIn: 4 * time.Minute,
+			lookupID:  1,
+		},
+		{
+			name:      "token has expired",
+			expiresIn: -time.Minute,
+			lookupID:  1,
+		},
+		{
+			name:      "another installation",
+			expiresIn: time.Hour,
+			lookupID:  2,
+		},
+		{
+			name:           "same installation on another enterprise host",
+			expiresIn:      time.Hour,
+			enterpriseHost: "ghe.company.com",
+			lookupHost:     "ghe.other.com",
+			lookupID:       1,
+		},
+		{
+			name:        "same scope in another order",
+			expiresIn:   time.Hour,
+			lookupID:    1,
+			scope:       []int64{1, 2},
+			lookupScope: []int64{2, 1},
+			wantToken:   "TOKEN",
+			wantFound:   true,
+		},
+		{
+			name:        "token scoped to other repositories",
+			expiresIn:   time.Hour,
+			lookupID:    1,
+			scope:       []int64{1},
+			lookupScope: []int64{1, 2},
+		},
+		{
+			name:      "scoped token for an unscoped request",
+			expiresIn: time.Hour,
+			lookupID:  1,
+			scope:     []int64{1},
+		},
+		{
+			name:        "invalidated scoped token",
+			expiresIn:   time.Hour,
+			lookupID:    1,
+			scope:       []int64{1},
+			lookupScope: []int64{1},
+			invalidate:  true,
+		},
+		{
+			name:       "invalidated token",
+			expiresIn:  time.Hour,
+			lookupID:   1,
+			invalidate: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := clockwork.NewFakeClock()
+			cache := NewTokenCache(clock)
+			cache.Set(tt.enterpriseHost, 1, tt.scope, 12345, "TOKEN", clock.Now().Add(tt.expiresIn))
+			if tt.invalidate {
+				cache.Invalidate(tt.enterpriseHost, 1)
+			}
+			lookupHost := tt.enterpriseHost
+			if tt.lookupHost != "" {
+				lookupHost = tt.lookupHost
+			}
+			token, applicationID, found := cache.Get(lookupHost, tt.lookupID, tt.lookupScope)
+			assert.Equal(t, found, tt.wantFound)
+			assert.Equal(t, token, tt.wantToken)
+			if tt.wantFound {
+				assert.Equal(t, applicationID, int64(12345))
+			}
+		})
+	}
+}
+
+func TestTokenCacheConcurrentAccess(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewTokenCache(clock)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			cache.Set("", id, nil, 1, fmt.Sprintf("TOKEN-%d", id), clock.Now().Add(time.Hour))
+			token, _, found := cache.Get("", id, nil)
+			assert.Assert(t, found)
+			assert.Equal(t, token, fmt.Sprintf("TOKEN-%d", id))
+			cache.Invalidate("", id)
+		}(int64(i))
+	}
+	wg.Wait()
+}
@@ -1,12 +1,18 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
@@ -160,6 +166,64 @@ func Test_GenerateJWT(t *testing.T) {
 	}
 }
 
+func Test_GenerateJWTExpiration(t *testing.T) {
+	tests := []struct {
+		name           string
+		expiration     int
+		wantExpiration time.Duration
+	}{
+		{
+			name:           "default",
+			wantExpiration: DefaultJWTExpiration,
+		},
+		{
+			name:           "custom",
+			expiration:     480,
+			wantExpiration: 8 * time.Minute,
+		},
+		{
+			name:           "capped to github maximum",
+			expiration:     3600,
+			wantExpiration: MaxJWTExpiration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := logger.GetLogger()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ctx = info.StoreCurrentControllerName(ctx, "default")
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: []*corev1.Namespace{testNamespace},
+				Secret:     []*corev1.Secret{validSecret},
+			})
+			run := &params.Run{
+				Clients: clients.Clients{
+					Log:  logger,
+					Kube: stdata.Kube,
+				},
+				Info: info.Info{
+					Controller: &info.ControllerInfo{
+						Secret: validSecret.GetName(),
+					},
+					Pac: &info.PacOpts{Settings: &settings.Settings{GitHubJWTExpiration: tt.expiration}},
+				},
+			}
+
+			ip := NewInstallation(nil, run, &v1alpha1.Repository{}, &github.Provider{}, testNamespace.GetName())
+			now := time.Now()
+			token, err := ip.GenerateJWT(ctx)
+			assert.NilError(t, err)
+
+			claims := &JWTClaim{}
+			_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+			assert.NilError(t, err)
+			// numeric dates are truncated to the second
+			assert.Assert(t, claims.ExpiresAt.Sub(now.Add(tt.wantExpiration)).Abs() <= 2*time.Second, claims.ExpiresAt)
+			assert.Assert(t, claims.IssuedAt.Sub(now.Add(-jwtIssuedAtDrift)).Abs() <= 2*time.Second, claims.IssuedAt)
+		})
+	}
+}
+
 func Test_GetAndUpdateInstallationID(t *testing.T) {
 	tdata := testclient.Data{
 		Namespaces: []*corev1.Namespace{testNamespace},
@@ -244,6 +308,8 @@ func Test_GetAndUpdateInstallationID(t *testing.T) {
 		_, _ = fmt.Fprint(w, `{"total_count": 1,"repositories": [{"id":1,"html_url": "https://matched/by/incoming"},{"id":2,"html_url": "https://anotherrepo/that/would/failit"}]}`)
 	})
 	ip = NewInstallation(req, run, repo, gprovider, testNamespace.GetName())
+	ip.tokenCache = NewTokenCache(clockwork.NewRealClock())
+	ip.repoCache = github.NewRepoListCache(clockwork.NewRealClock())
 	_, token, installationID, err := ip.GetAndUpdateInstallationID(ctx)
 	assert.NilError(t, err)
 	assert.Equal(t, installationID, int64(120))
@@ -251,6 +317,227 @@ func Test_GetAndUpdateInstallationID(t *testing.T) {
 	assert.Equal(t, token, wantToken)
 }
 
+func Test_GetAndUpdateInstallationIDCachedToken(t *testing.T) {
+	tdata := testclient.Data{
+		Namespaces: []*corev1.Namespace{testNamespace},
+		Secret:     []*corev1.Secret{validSecret},
+	}
+	wantToken := "GOODTOKEN"
+	wantID := 120
+
+	fakeghclient, mux, serverURL, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	config := map[string]map[string]string{
+		fmt.Sprintf("%s/app/installations", serverURL): {
+			"body": fmt.Sprintf(`[{"id":%d}]`, wantID),
+			"code": "200",
+		},
+	}
+	httpTestClient := httptesthelper.MakeHTTPTestClient(config)
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+	logger, _ := logger.GetLogger()
+	run := &params.Run{
+		Clients: clients.Clients{
+			Log:            logger,
+			PipelineAsCode: stdata.PipelineAsCode,
+			Kube:           stdata.Kube,
+			HTTP:           *httpTestClient,
+		},
+		Info: info.Info{
+			Pac: &info.PacOpts{
+				Settings: &settings.Settings{},
+			},
+			Controller: &info.ControllerInfo{Secret: validSecret.GetName()},
+		},
+	}
+	ctx = info.StoreCurrentControllerName(ctx, "default")
+	ctx = info.StoreNS(ctx, testNamespace.GetName())
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "repo",
+		},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://matched/by/incoming",
+		},
+	}
+
+	tokenRequests := 0
+	mux.HandleFunc(fmt.Sprintf("/app/installations/%d/access_tokens", wantID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		tokenRequests++
+		_, _ = fmt.Fprintf(w, `{"token": "%s", "expires_at": "%s"}`, wantToken, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/installation/repositories", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `{"total_count": 1,"repositories": [{"id":1,"html_url": "https://matched/by/incoming"}]}`)
+	})
+	t.Setenv("PAC_GIT_PROVIDER_TOKEN_APIURL", serverURL+"/api/v3")
+
+	cache := NewTokenCache(clockwork.NewRealClock())
+	for i := 0; i < 2; i++ {
+		gprovider := &github.Provider{Client: fakeghclient, APIURL: &serverURL, Run: run}
+		ip := NewInstallation(httptest.NewRequest(http.MethodGet, "http://localhost", strings.NewReader("")), run, repo, gprovider, testNamespace.GetName())
+		ip.tokenCache = cache
+		ip.repoCache = github.NewRepoListCache(clockwork.NewRealClock())
+		_, token, installationID, err := ip.GetAndUpdateInstallationID(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, installationID, int64(wantID))
+		assert.Equal(t, token, wantToken)
+		assert.Equal(t, *gprovider.Token, wantToken)
+		assert.Assert(t, gprovider.ApplicationID != nil, "the application id should be set from the cache too")
+		assert.Equal(t, *gprovider.ApplicationID, int64(274799))
+	}
+	assert.Equal(t, tokenRequests, 1, "installation token should have been reused from the cache")
+
+	cache.Invalidate("", int64(wantID))
+	gprovider := &github.Provider{Client: fakeghclient, APIURL: &serverURL, Run: run}
+	ip := NewInstallation(httptest.NewRequest(http.MethodGet, "http://localhost", strings.NewReader("")), run, repo, gprovider, testNamespace.GetName())
+	ip.tokenCache = cache
+	ip.repoCache = github.NewRepoListCache(clockwork.NewRealClock())
+	_, _, _, err := ip.GetAndUpdateInstallationID(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, tokenRequests, 2, "installation token should have been regenerated after invalidation")
+}
+
+func Test_GetAndUpdateInstallationIDSeveralInstallations(t *testing.T) {
+	tests := []struct {
+		name           string
+		installations  string
+		permissions    map[int64]string
+		appPermissions string
+		wantID         int64
+		wantToken      string
+		wantErr        string
+		wantWarning    string
+	}{
+		{
+			name:        "read only installation skipped",
+			permissions: map[int64]string{120: `{"checks": "read"}`, 130: `{"checks": "write"}`},
+			wantID:      130,
+			wantToken:   "TOKEN-130",
+			wantWarning: "skipping installation 120: installation is missing checks:write permission",
+		},
+		{
+			name:        "first installation picked when both can write",
+			permissions: map[int64]string{120: `{"checks": "write"}`, 130: `{"checks": "write"}`},
+			wantID:      120,
+			wantToken:   "TOKEN-120",
+		},
+		{
+			name:        "no installation can write",
+			permissions: map[int64]string{120: `{"checks": "read"}`, 130: `{"checks": "read"}`},
+			wantErr:     "installation is missing checks:write permission, the github app pipelines-as-code requests it, the new permissions of the app have to be accepted on the installation",
+		},
+		{
+			name:           "app not requesting the permission",
+			permissions:    map[int64]string{120: `{"checks": "read"}`, 130: `{"checks": "read"}`},
+			appPermissions: `{"checks": "read"}`,
+			wantErr:        "installation is missing checks:write permission, the github app pipelines-as-code doesn't request checks:write, it has to be added to the permissions of the app",
+		},
+		{
+			name:          "suspended installation skipped",
+			installations: `[{"id":130}, {"id":120, "suspended_at": "2024-01-02T03:04:05Z", "account": {"login": "another"}}]`,
+			permissions:   map[int64]string{120: `{"checks": "write"}`, 130: `{"checks": "write"}`},
+			wantID:        130,
+			wantToken:     "TOKEN-130",
+			wantWarning:   "skipping installation 120: installation 120 on another is suspended since 2024-01-02T03:04:05Z",
+		},
+		{
+			name:          "installation of the repository suspended",
+			installations: `[{"id":120, "suspended_at": "2024-01-02T03:04:05Z", "account": {"login": "by"}}]`,
+			wantErr:       "installation 120 on by is suspended since 2024-01-02T03:04:05Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tdata := testclient.Data{
+				Namespaces: []*corev1.Namespace{testNamespace},
+				Secret:     []*corev1.Secret{validSecret},
+			}
+			fakeghclient, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			// not sorted by id on purpose
+			if tt.installations == "" {
+				tt.installations = `[{"id":130}, {"id":120}]`
+			}
+			if tt.appPermissions == "" {
+				tt.appPermissions = `{"checks": "write"}`
+			}
+			config := map[string]map[string]string{
+				fmt.Sprintf("%s/app/installations", serverURL): {
+					"body": tt.installations,
+					"code": "200",
+				},
+				fmt.Sprintf("%s/api/v3/app", serverURL): {
+					"body": fmt.Sprintf(`{"id": 274799, "slug": "pipelines-as-code", "permissions": %s}`, tt.appPermissions),
+					"code": "200",
+				},
+			}
+			httpTestClient := httptesthelper.MakeHTTPTestClient(config)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			logger, observer := logger.GetLogger()
+			run := &params.Run{
+				Clients: clients.Clients{
+					Log:            logger,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Kube:           stdata.Kube,
+					HTTP:           *httpTestClient,
+				},
+				Info: info.Info{
+					Pac: &info.PacOpts{
+						Settings: &settings.Settings{},
+					},
+					Controller: &info.ControllerInfo{Secret: validSecret.GetName()},
+				},
+			}
+			ctx = info.StoreCurrentControllerName(ctx, "default")
+			ctx = info.StoreNS(ctx, testNamespace.GetName())
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "repo",
+				},
+				Spec: v1alpha1.RepositorySpec{
+					URL: "https://matched/by/incoming",
+				},
+			}
+
+			for id, permissions := range tt.permissions {
+				id, permissions := id, permissions
+				mux.HandleFunc(fmt.Sprintf("/app/installations/%d/access_tokens", id), func(w http.ResponseWriter, r *http.Request) {
+					testMethod(t, r, "POST")
+					_, _ = fmt.Fprintf(w, `{"token": "TOKEN-%d", "expires_at": "%s", "permissions": %s}`,
+						id, time.Now().Add(time.Hour).Format(time.RFC3339), permissions)
+				})
+			}
+			mux.HandleFunc("/installation/repositories", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprint(w, `{"total_count": 1,"repositories": [{"id":1,"html_url": "https://matched/by/incoming"}]}`)
+			})
+			t.Setenv("PAC_GIT_PROVIDER_TOKEN_APIURL", serverURL+"/api/v3")
+
+			gprovider := &github.Provider{Client: fakeghclient, APIURL: &serverURL, Run: run}
+			ip := NewInstallation(httptest.NewRequest(http.MethodGet, "http://localhost", strings.NewReader("")), run, repo, gprovider, testNamespace.GetName())
+			ip.tokenCache = NewTokenCache(clockwork.NewRealClock())
+			ip.repoCache = github.NewRepoListCache(clockwork.NewRealClock())
+			ip.metadataCache = NewAppMetadataCache(clockwork.NewRealClock(), time.Hour)
+			_, token, installationID, err := ip.GetAndUpdateInstallationID(ctx)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, installationID, tt.wantID)
+			assert.Equal(t, token, tt.wantToken)
+			assert.Equal(t, *gprovider.Token, tt.wantToken)
+			if tt.wantWarning != "" {
+				assert.Equal(t, observer.FilterMessage(tt.wantWarning).Len(), 1, observer.All())
+			} else {
+				assert.Equal(t, observer.FilterMessageSnippet("skipping installation").Len(), 0)
+			}
+		})
+	}
+}
+
 func testMethod(t *testing.T, r *http.Request, want string) {
 	t.Helper()
 	if got := r.Method; got != want {
@@ -259,41 +546,303 @@ func testMethod(t *testing.T, r *http.Request, want string) {
 }
 
 func Test_ListRepos(t *testing.T) {
+	tests := []struct {
+		name    string
+		specURL string
+		want    bool
+	}{
+		{name: "same url", specURL: "https://matched/by/incoming", want: true},
+		{name: "git suffix", specURL: "https://matched/by/incoming.git", want: true},
+		{name: "trailing slash", specURL: "https://matched/by/incoming/", want: true},
+		{name: "host case", specURL: "HTTPS://Matched/by/incoming", want: true},
+		{name: "sub-group", specURL: "https://matched/by/sub/group/incoming.git", want: true},
+		{name: "other repo", specURL: "https://matched/by/other", want: false},
+		{name: "parent group", specURL: "https://matched/by", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			mux.HandleFunc("user/installations/1/repositories/2", func(rw http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(rw)
+			})
+
+			mux.HandleFunc("/installation/repositories", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Authorization", "Bearer 12345")
+				w.Header().Set("Accept", "application/vnd.github+json")
+				_, _ = fmt.Fprint(w, `{"total_count": 2,"repositories": [{"id":1,"html_url": "https://matched/by/incoming"},{"id":2,"html_url": "https://matched/by/sub/group/incoming"}]}`)
+			})
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "repo",
+				},
+				Spec: v1alpha1.RepositorySpec{
+					URL: tt.specURL,
+					Incomings: &[]v1alpha1.Incoming{
+						{
+							Targets: []string{"main"},
+							Secret: v1alpha1.Secret{
+								Name: "secret",
+							},
+						},
+					},
+				},
+			}
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			gprovider := &github.Provider{Client: fakeclient}
+			ip := NewInstallation(httptest.NewRequest(http.MethodGet, "http://localhost", strings.NewReader("")),
+				&params.Run{}, repo, gprovider, testNamespace.GetName())
+			ip.repoCache = github.NewRepoListCache(clockwork.NewRealClock())
+			exist, err := ip.listRepos(ctx, "", 1)
+			assert.NilError(t, err)
+			assert.Equal(t, exist, tt.want)
+		})
+	}
+}
+
+func TestGetReponse(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	done := make(chan struct{})
+	defer close(done)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"id": 1}`)
+	}))
+	defer server.Close()
+	run := &params.Run{
+		Clients: clients.Clients{HTTP: http.Client{}},
+		Info: info.Info{
+			Pac: &info.PacOpts{Settings: &settings.Settings{GitHubRequestTimeout: 1}},
+		},
+	}
+
+	res, err := GetReponse(ctx, http.MethodGet, server.URL+"/app", "jwt", run)
+	assert.NilError(t, err)
+	data, err := io.ReadAll(res.Body)
+	assert.NilError(t, err)
+	assert.NilError(t, res.Body.Close())
+	assert.Equal(t, string(data), `{"id": 1}`)
+
+	_, err = GetReponse(ctx, http.MethodGet, server.URL+"/slow", "jwt", run)
+	assert.ErrorContains(t, err, fmt.Sprintf("request to %s/slow timed out after 1s", server.URL))
+	assert.Assert(t, errors.Is(err, context.DeadlineExceeded))
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err = GetReponse(ctx, http.MethodGet, closed.URL+"/app", "jwt", run)
+	assert.ErrorContains(t, err, fmt.Sprintf("request to %s/app failed", closed.URL))
+	assert.Assert(t, !errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestGetReponseHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		version     string
+		wantAccept  string
+		wantVersion string
+	}{
+		{
+			name:       "default",
+			wantAccept: "application/vnd.github+json",
+		},
+		{
+			name:        "configured",
+			accept:      "application/vnd.github.v3+json",
+			version:     github.KnownGoodAPIVersion,
+			wantAccept:  "application/vnd.github.v3+json",
+			wantVersion: "2022-11-28",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(github.GitHubAcceptHeaderEnv, tt.accept)
+			t.Setenv(github.GitHubAPIVersionEnv, tt.version)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			var header http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				header = r.Header
+			}))
+			defer server.Close()
+			run := &params.Run{
+				Clients: clients.Clients{HTTP: http.Client{}},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+			}
+
+			res, err := GetReponse(ctx, http.MethodGet, server.URL+"/app", "jwt", run)
+			assert.NilError(t, err)
+			assert.NilError(t, res.Body.Close())
+			assert.Equal(t, header.Get("Accept"), tt.wantAccept)
+			assert.Equal(t, header.Get("Authorization"), "Bearer jwt")
+			assert.Equal(t, len(header.Values(github.APIVersionHeader)) > 0, tt.wantVersion != "")
+			assert.Equal(t, header.Get(github.APIVersionHeader), tt.wantVersion)
+		})
+	}
+}
+
+func TestGetReponseReauth(t *testing.T) {
+	tests := []struct {
+		name                  string
+		unauthorizedResponses int
+		wantRequests          int
+		wantErr               string
+		wantRetryLog          bool
+	}{
+		{
+			name:         "authorized",
+			wantRequests: 1,
+		},
+		{
+			name:                  "authorized with a new jwt",
+			unauthorizedResponses: 1,
+			wantRequests:          2,
+			wantRetryLog:          true,
+		},
+		{
+			name:                  "retried only once",
+			unauthorizedResponses: 5,
+			wantRequests:          2,
+			wantErr:               "Non-OK HTTP status while getting app metadata",
+			wantRetryLog:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tdata := testclient.Data{
+				Namespaces: []*corev1.Namespace{testNamespace},
+				Secret:     []*corev1.Secret{validSecret},
+			}
+			fakeghclient, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			apiURL := serverURL + "/api/v3"
+
+			requests := 0
+			mux.HandleFunc("/app", func(w http.ResponseWriter, r *http.Request) {
+				assert.Assert(t, r.Header.Get("Authorization") != "")
+				requests++
+				if requests <= tt.unauthorizedResponses {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = fmt.Fprint(w, `{"message": "'Expiration time' claim ('exp') is too far in the future"}`)
+					return
+				}
+				_, _ = fmt.Fprint(w, `{"id": 274799, "slug": "pipelines-as-code"}`)
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			fakelogger, observer := logger.GetLogger()
+			run := &params.Run{
+				Clients: clients.Clients{
+					Log:            fakelogger,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Kube:           stdata.Kube,
+				},
+				Info: info.Info{
+					Pac:        &info.PacOpts{Settings: &settings.Settings{}},
+					Controller: &info.ControllerInfo{Secret: validSecret.GetName()},
+				},
+			}
+			ctx = info.StoreNS(ctx, testNamespace.GetName())
+
+			gprovider := &github.Provider{Client: fakeghclient, APIURL: &apiURL, Run: run}
+			ip := NewInstallation(nil, run, nil, gprovider, testNamespace.GetName())
+			ip.metadataCache = NewAppMetadataCache(clockwork.NewFakeClock(), time.Hour)
+			metadata, err := ip.AppMetadata(ctx)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, metadata.Slug, "pipelines-as-code")
+			}
+			assert.Equal(t, requests, tt.wantRequests)
+			assert.Equal(t, observer.FilterMessageSnippet("retrying once with a new jwt").Len() == 1, tt.wantRetryLog)
+		})
+	}
+}
+
+func Test_ListReposCached(t *testing.T) {
 	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
 	defer teardown()
-
-	mux.HandleFunc("user/installations/1/repositories/2", func(rw http.ResponseWriter, _ *http.Request) {
-		fmt.Fprint(rw)
+	requests := 0
+	mux.HandleFunc("/installation/repositories", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = fmt.Fprint(w, `{"total_count": 1,"repositories": [{"id":1,"html_url": "https://matched/by/incoming"}]}`)
 	})
 
+	ctx, _ := rtesting.SetupFakeContext(t)
+	clock := clockwork.NewFakeClock()
+	cache := github.NewRepoListCache(clock)
+	run := &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{GitHubRepoListCacheTTL: 60}}}}
+	listRepos := func(installationID int64) bool {
+		repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{URL: "https://matched/by/incoming"}}
+		ip := NewInstallation(nil, run, repo, &github.Provider{Client: fakeclient}, testNamespace.GetName())
+		ip.repoCache = cache
+		exist, err := ip.listRepos(ctx, "", installationID)
+		assert.NilError(t, err)
+		return exist
+	}
+
+	assert.Assert(t, listRepos(1))
+	assert.Assert(t, listRepos(1))
+	assert.Equal(t, requests, 1, "the repositories should have been listed from the cache")
+
+	assert.Assert(t, listRepos(2))
+	assert.Equal(t, requests, 2, "the repositories of another installation should be listed")
+
+	clock.Advance(time.Minute)
+	assert.Assert(t, listRepos(1))
+	assert.Equal(t, requests, 3, "the repositories should have been listed again after the ttl")
+
+	cache.Invalidate("", 1)
+	assert.Assert(t, listRepos(1))
+	assert.Equal(t, requests, 4, "the repositories should have been listed again after the invalidation")
+}
+
+func Test_ListReposRelistedOnCacheMiss(t *testing.T) {
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	requests := 0
 	mux.HandleFunc("/installation/repositories", func(w http.ResponseWriter, _ *http.Request) {
-		w.Header().Set("Authorization", "Bearer 12345")
-		w.Header().Set("Accept", "application/vnd.github+json")
+		requests++
 		_, _ = fmt.Fprint(w, `{"total_count": 1,"repositories": [{"id":1,"html_url": "https://matched/by/incoming"}]}`)
 	})
 
-	repo := &v1alpha1.Repository{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "repo",
-		},
-		Spec: v1alpha1.RepositorySpec{
-			URL: "https://matched/by/incoming",
-			Incomings: &[]v1alpha1.Incoming{
-				{
-					Targets: []string{"main"},
-					Secret: v1alpha1.Secret{
-						Name: "secret",
-					},
-				},
-			},
-		},
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cache := github.NewRepoListCache(clockwork.NewFakeClock())
+	// cached before the repository has been added to the installation
+	cache.Set("", 1, []string{"https://matched/other"}, time.Minute)
+	run := &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}}}
+	listRepos := func(url string) bool {
+		repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{URL: url}}
+		ip := NewInstallation(nil, run, repo, &github.Provider{Client: fakeclient}, testNamespace.GetName())
+		ip.repoCache = cache
+		exist, err := ip.listRepos(ctx, "", 1)
+		assert.NilError(t, err)
+		return exist
 	}
 
-	ctx, _ := rtesting.SetupFakeContext(t)
-	gprovider := &github.Provider{Client: fakeclient}
-	ip := NewInstallation(httptest.NewRequest(http.MethodGet, "http://localhost", strings.NewReader("")),
-		&params.Run{}, repo, gprovider, testNamespace.GetName())
-	exist, err := ip.listRepos(ctx)
-	assert.NilError(t, err)
-	assert.Equal(t, exist, true)
+	assert.Assert(t, listRepos("https://matched/by/incoming"))
+	assert.Equal(t, requests, 1, "the repositories should have been listed again")
+	repos, ok := cache.Get("", 1)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, repos, []string{"https://matched/by/incoming"})
+
+	assert.Assert(t, !listRepos("https://not/in/installation"))
+	assert.Equal(t, requests, 2, "the repositories should only be listed again once")
+}
+
+func TestRepoListCacheTTL(t *testing.T) {
+	assert.Equal(t, repoListCacheTTL(&params.Run{}), DefaultRepoListCacheTTL)
+	assert.Equal(t, repoListCacheTTL(&params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+		GitHubRepoListCacheTTL: 30,
+	}}}}), 30*time.Second)
 }
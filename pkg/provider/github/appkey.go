@@ -0,0 +1,79 @@
+package github
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// appKey is the application ID and private key of the GitHub App loaded from
+// the controller secret.
+type appKey struct {
+	// hash is the hash of the secret data they have been loaded from, to
+	// detect a rotation.
+	hash          [sha256.Size]byte
+	applicationID int64
+	privateKey    []byte
+	parsed        *rsa.PrivateKey
+}
+
+// AppKeyCache keeps the private keys of the GitHub App parsed by secret and
+// only parses them again when the secret has changed, i.e: on a key rotation,
+// safe for concurrent use.
+type AppKeyCache struct {
+	mutex sync.Mutex
+	keys  map[string]*appKey
+}
+
+func NewAppKeyCache() *AppKeyCache {
+	return &AppKeyCache{keys: map[string]*appKey{}}
+}
+
+// load returns the key of the secret, validating the private key when it has
+// never been seen or has been rotated.
+func (c *AppKeyCache) load(secretRef string, applicationID int64, privateKey []byte) (*appKey, bool, error) {
+	hash := sha256.Sum256(bytes.Join([][]byte{[]byte(fmt.Sprint(applicationID)), privateKey}, []byte{0}))
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, ok := c.keys[secretRef]
+	if ok && cached.hash == hash {
+		return cached, false, nil
+	}
+
+	parsed, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("the github private key of secret %s is malformed, could not parse private key: %w", secretRef, err)
+	}
+	key := &appKey{
+		hash:          hash,
+		applicationID: applicationID,
+		privateKey:    privateKey,
+		parsed:        parsed,
+	}
+	c.keys[secretRef] = key
+	return key, ok, nil
+}
+
+// Invalidate forgets the key of a secret, forcing it to be parsed again on
+// the next load.
+func (c *AppKeyCache) Invalidate(secretRef string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.keys, secretRef)
+}
+
+// appKeys is the cache of the keys shared by all the providers of the
+// controller.
+var appKeys = NewAppKeyCache()
+
+func (v *Provider) appKeys() *AppKeyCache {
+	if v.appKeyCache != nil {
+		return v.appKeyCache
+	}
+	return appKeys
+}
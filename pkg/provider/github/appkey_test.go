@@ -0,0 +1,105 @@
+package github
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func generatePrivateKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestAppKeyCacheLoad(t *testing.T) {
+	cache := NewAppKeyCache()
+
+	key, rotated, err := cache.load("ns/secret", 12345, []byte(fakePrivateKey))
+	assert.NilError(t, err)
+	assert.Assert(t, !rotated)
+	assert.Equal(t, key.applicationID, int64(12345))
+	assert.Assert(t, key.parsed != nil)
+
+	// the same secret is not parsed again
+	again, rotated, err := cache.load("ns/secret", 12345, []byte(fakePrivateKey))
+	assert.NilError(t, err)
+	assert.Assert(t, !rotated)
+	assert.Assert(t, again == key)
+
+	// a rotated private key is picked up
+	newPrivateKey := generatePrivateKey(t)
+	rotatedKey, rotated, err := cache.load("ns/secret", 12345, newPrivateKey)
+	assert.NilError(t, err)
+	assert.Assert(t, rotated)
+	assert.DeepEqual(t, rotatedKey.privateKey, newPrivateKey)
+	assert.Assert(t, !rotatedKey.parsed.Equal(key.parsed))
+
+	// a malformed private key is an error, and the last valid one is kept
+	_, _, err = cache.load("ns/secret", 12345, []byte("invalid-key"))
+	assert.ErrorContains(t, err, "the github private key of secret ns/secret is malformed, could not parse private key")
+	kept, _, err := cache.load("ns/secret", 12345, newPrivateKey)
+	assert.NilError(t, err)
+	assert.Assert(t, kept == rotatedKey)
+
+	// an invalidated key is parsed again
+	cache.Invalidate("ns/secret")
+	reloaded, rotated, err := cache.load("ns/secret", 12345, newPrivateKey)
+	assert.NilError(t, err)
+	assert.Assert(t, !rotated)
+	assert.Assert(t, reloaded != rotatedKey)
+}
+
+func TestGetAppIDAndPrivateKeyRotation(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipelines-as-code-secret",
+			Namespace: "pac",
+		},
+		Data: map[string][]byte{
+			"github-application-id": []byte("12345"),
+			"github-private-key":    []byte(fakePrivateKey),
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Secret: []*corev1.Secret{secret}})
+
+	observer, logs := logger.GetLogger()
+	gprovider := New()
+	gprovider.Logger = observer
+	gprovider.appKeyCache = NewAppKeyCache()
+	gprovider.Run = params.New()
+	gprovider.Run.Info.Controller = &info.ControllerInfo{Secret: secret.GetName()}
+
+	_, privateKey, err := gprovider.GetAppIDAndPrivateKey(ctx, "pac", stdata.Kube)
+	assert.NilError(t, err)
+	assert.Equal(t, string(privateKey), fakePrivateKey)
+
+	newPrivateKey := generatePrivateKey(t)
+	secret.Data["github-private-key"] = newPrivateKey
+	_, err = stdata.Kube.CoreV1().Secrets("pac").Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+
+	_, privateKey, err = gprovider.GetAppIDAndPrivateKey(ctx, "pac", stdata.Kube)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, privateKey, newPrivateKey)
+	assert.Equal(t, logs.FilterMessageSnippet("has changed, using the new one").Len(), 1)
+
+	secret.Data["github-private-key"] = []byte("invalid-key")
+	_, err = stdata.Kube.CoreV1().Secrets("pac").Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	_, _, err = gprovider.GetAppIDAndRSAPrivateKey(ctx, "pac", stdata.Kube)
+	assert.ErrorContains(t, err, "the github private key of secret pac/pipelines-as-code-secret is malformed")
+}
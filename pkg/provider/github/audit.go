@@ -0,0 +1,116 @@
+package github
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// statusAuditVersion is the version of the StatusAudit schema, bumped only
+// when a field changes in a way the existing consumers cannot parse.
+const statusAuditVersion = 1
+
+// StatusAudit is the record of a status posted on GitHub, written as a JSON
+// line with the audit-statuses setting. The schema is stable: fields are only
+// added, never renamed or removed without bumping Version. It never contains
+// the tokens nor the webhook secret of the event.
+type StatusAudit struct {
+	// Version is the version of the schema, currently 1.
+	Version int `json:"version"`
+	// Time is when the status has been posted, in UTC.
+	Time         time.Time `json:"time"`
+	Organization string    `json:"organization"`
+	Repository   string    `json:"repository"`
+	SHA          string    `json:"sha"`
+	PipelineRun  string    `json:"pipelinerun,omitempty"`
+	// Name is the name of the check run and the context of the commit
+	// status.
+	Name string `json:"name"`
+	// Reporters are how the status has been posted, check_run and/or
+	// commit_status.
+	Reporters  []string `json:"reporters"`
+	Status     string   `json:"status"`
+	Conclusion string   `json:"conclusion,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	TargetURL  string   `json:"target_url,omitempty"`
+	// DryRun is set when the status has only been logged.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Error is why posting the status has failed, empty when it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// redactedURL removes the credentials of a URL, the URL is kept as is when it
+// cannot be parsed.
+func redactedURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// auditStatus logs the audit record of a posted status, or writes it to the
+// auditWriter when set.
+func (v *Provider) auditStatus(runevent *info.Event, statusOpts provider.StatusOpts, checkRun, commitStatus bool, err error) {
+	record := StatusAudit{
+		Version:      statusAuditVersion,
+		Time:         time.Now().UTC(),
+		Organization: runevent.Organization,
+		Repository:   runevent.Repository,
+		SHA:          runevent.SHA,
+		PipelineRun:  statusOpts.PipelineRunName,
+		Name:         getCheckName(v.Logger, statusOpts, v.Run.Info.Pac, runevent),
+		Reporters:    []string{},
+		Status:       statusOpts.Status,
+		Conclusion:   statusOpts.Conclusion,
+		Title:        statusOpts.Title,
+		Summary:      statusOpts.Summary,
+		TargetURL:    redactedURL(statusOpts.DetailsURL),
+		DryRun:       v.Run.Info.Pac.DryRun,
+	}
+	if checkRun {
+		record.Reporters = append(record.Reporters, settings.StatusModeCheckRun)
+	}
+	if commitStatus {
+		record.Reporters = append(record.Reporters, settings.StatusModeCommitStatus)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	// the secrets of the event may end up in a summary or an error message
+	secrets := []string{}
+	if runevent.Provider != nil {
+		secrets = append(secrets, runevent.Provider.Token, runevent.Provider.WebhookSecret)
+	}
+	if v.Token != nil {
+		secrets = append(secrets, *v.Token)
+	}
+	for _, field := range []*string{&record.Name, &record.Title, &record.Summary, &record.TargetURL, &record.Error} {
+		for _, secret := range secrets {
+			if secret != "" {
+				*field = strings.ReplaceAll(*field, secret, "*****")
+			}
+		}
+	}
+
+	line, merr := json.Marshal(record)
+	if merr != nil {
+		v.statusLogger(runevent, statusOpts).Errorf("cannot serialize the audit record of the status: %v", merr)
+		return
+	}
+	if v.auditWriter == nil {
+		v.statusLogger(runevent, statusOpts).Infof("status audit: %s", line)
+		return
+	}
+	if _, werr := v.auditWriter.Write(append(line, '\n')); werr != nil {
+		v.statusLogger(runevent, statusOpts).Errorf("cannot write the audit record of the status: %v", werr)
+	}
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pipelineIdentity identifies the pipeline a PipelineRun is a run of, the new
+// runs of a pipeline (i.e: on /retest) are expected to share its check name.
+func pipelineIdentity(pr *tektonv1.PipelineRun) string {
+	if name := pr.GetAnnotations()[keys.OriginalPRName]; name != "" {
+		return name
+	}
+	if pr.GetGenerateName() != "" {
+		return pr.GetGenerateName()
+	}
+	return pr.GetName()
+}
+
+// runNameSuffix returns the part of the name of the PipelineRun
+// generated by kubernetes, or a short hash of its name when it has not been
+// generated.
+func runNameSuffix(pr *tektonv1.PipelineRun) string {
+	if generateName := pr.GetGenerateName(); generateName != "" && strings.HasPrefix(pr.GetName(), generateName) &&
+		len(pr.GetName()) > len(generateName) {
+		return strings.TrimPrefix(pr.GetName(), generateName)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(pr.GetName())))[:5]
+}
+
+// createdBefore returns true when a PipelineRun has been created before
+// another one, by name when they have been created at the same time.
+func createdBefore(a, b *tektonv1.PipelineRun) bool {
+	createdA, createdB := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !createdA.Equal(&createdB) {
+		return createdA.Before(&createdB)
+	}
+	return a.GetName() < b.GetName()
+}
+
+// checkNameSuffix returns the suffix disambiguating the check name of the
+// PipelineRun of the status when it collides with the check name of another
+// pipeline on the commit. The check name belongs to the pipeline of the
+// oldest PipelineRun getting it, so the check name of a PipelineRun never
+// changes while it runs and the new runs of a pipeline keep its name.
+func (v *Provider) checkNameSuffix(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts) string {
+	pr := statusOpts.PipelineRun
+	if pr == nil || statusOpts.PipelineRunName == "" || v.Run == nil || v.Run.Clients.Tekton == nil {
+		return ""
+	}
+	prs, err := v.Run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.SHA, formatting.CleanValueKubernetes(runevent.SHA)),
+	})
+	if err != nil {
+		if v.Logger != nil {
+			v.Logger.Warnf("cannot list the pipelineruns of commit %s to detect the check name collisions: %v", runevent.SHA, err)
+		}
+		return ""
+	}
+
+	name := getCheckName(v.Logger, statusOpts, v.Run.Info.Pac, runevent)
+	owner := pr
+	for i := range prs.Items {
+		other := &prs.Items[i]
+		if !createdBefore(other, owner) {
+			continue
+		}
+		otherOpts := provider.StatusOpts{
+			PipelineRunName:         other.GetName(),
+			OriginalPipelineRunName: other.GetAnnotations()[keys.OriginalPRName],
+		}
+		if getCheckName(v.Logger, otherOpts, v.Run.Info.Pac, runevent) == name {
+			owner = other
+		}
+	}
+	if pipelineIdentity(owner) == pipelineIdentity(pr) {
+		return ""
+	}
+	suffix := runNameSuffix(pr)
+	if v.Logger != nil {
+		v.Logger.Infof("check name %s of pipelinerun %s collides with the one of pipelinerun %s, suffixing it with %s",
+			name, pr.GetName(), owner.GetName(), suffix)
+	}
+	return suffix
+}
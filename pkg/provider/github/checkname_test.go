@@ -0,0 +1,81 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRunNameSuffix(t *testing.T) {
+	generated := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build-abcde", GenerateName: "build-"}}
+	assert.Equal(t, runNameSuffix(generated), "abcde")
+
+	named := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "build"}}
+	assert.Equal(t, len(runNameSuffix(named)), 5)
+	assert.Equal(t, runNameSuffix(named), runNameSuffix(named.DeepCopy()), "the suffix should be deterministic")
+}
+
+func TestCreateStatusCheckNameCollision(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pipelineRun := func(name, generateName, originalName string, age time.Duration) *tektonv1.PipelineRun {
+		annotations := map[string]string{}
+		if originalName != "" {
+			annotations[keys.OriginalPRName] = originalName
+		}
+		return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			GenerateName:      generateName,
+			Namespace:         "ns",
+			Labels:            map[string]string{keys.SHA: "sha"},
+			Annotations:       annotations,
+			CreationTimestamp: metav1.NewTime(created.Add(age)),
+		}}
+	}
+	// build and test have no original name, their check names are both the
+	// application name
+	build := pipelineRun("build-abcde", "build-", "", 0)
+	test := pipelineRun("test-fghij", "test-", "", time.Minute)
+	// a new run of build, i.e: on /retest
+	rebuild := pipelineRun("build-klmno", "build-", "", 2*time.Minute)
+	lint := pipelineRun("lint-pqrst", "lint-", "lint", 3*time.Minute)
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1.PipelineRun{build, test, rebuild, lint},
+	})
+	v, _, repositories, _ := newFakeProvider()
+	v.Run.Clients.Tekton = stdata.Pipeline
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+
+	for _, pr := range []*tektonv1.PipelineRun{test, build, rebuild, lint, test} {
+		assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+			Status:                  "in_progress",
+			Conclusion:              "pending",
+			PipelineRun:             pr,
+			PipelineRunName:         pr.GetName(),
+			OriginalPipelineRunName: pr.GetAnnotations()[keys.OriginalPRName],
+		}))
+	}
+
+	contexts := []string{}
+	for _, status := range repositories.Statuses {
+		contexts = append(contexts, status.GetContext())
+	}
+	assert.DeepEqual(t, contexts, []string{
+		// the newer pipeline gets a suffix, even when reported first
+		"Pipelines as Code CI (fghij)",
+		"Pipelines as Code CI",
+		// a new run of the same pipeline shares its name
+		"Pipelines as Code CI",
+		"Pipelines as Code CI / lint",
+		"Pipelines as Code CI (fghij)",
+	})
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const supersededCheckRunSummary = "The commit is not the head of the pull request anymore, this check run will not be updated."
+
+// CancelInProgressCheckRuns completes as cancelled the check runs of our
+// application still in progress on the previous head commit of an updated
+// pull request, when the PipelineRun they report is not running anymore
+// (i.e: deleted or finished without reporting), otherwise they would show as
+// pending forever on the pull request after a force push. Nothing is cancelled
+// without the id of our application, the check runs of the other applications
+// of the commit would be listed too.
+func (v *Provider) CancelInProgressCheckRuns(ctx context.Context, runevent *info.Event) error {
+	if checkRun, _ := v.statusReporters(runevent); !checkRun || !v.hasStatusClients() || runevent.PreviousSHA == "" || runevent.PreviousSHA == runevent.SHA {
+		return nil
+	}
+	if v.ApplicationID == nil || v.repo == nil {
+		if v.Logger != nil {
+			v.Logger.Debugf("not cancelling the check runs of commit %s, the github application or the repository is unknown", runevent.PreviousSHA)
+		}
+		return nil
+	}
+
+	running, err := v.runningPipelineRuns(ctx, v.repo.GetNamespace(), runevent.PreviousSHA)
+	if err != nil {
+		return err
+	}
+
+	opt := github.ListOptions{PerPage: v.paginedNumber}
+	superseded := []*github.CheckRun{}
+	for {
+		res, resp, err := v.checks().ListCheckRunsForRef(ctx, runevent.Organization, runevent.Repository,
+			runevent.PreviousSHA, &github.ListCheckRunsOptions{
+				AppID:       v.ApplicationID,
+				Status:      github.String("in_progress"),
+				ListOptions: opt,
+			})
+		if err != nil {
+			return fmt.Errorf("cannot list the check runs of commit %s: %w", runevent.PreviousSHA, err)
+		}
+		for _, checkRun := range res.CheckRuns {
+			if checkRun.GetExternalID() == "" || running[checkRun.GetExternalID()] {
+				continue
+			}
+			superseded = append(superseded, checkRun)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	for _, checkRun := range superseded {
+		_, _, err := v.checks().UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, checkRun.GetID(),
+			github.UpdateCheckRunOptions{
+				Name:        checkRun.GetName(),
+				Status:      github.String("completed"),
+				Conclusion:  github.String("cancelled"),
+				CompletedAt: &github.Timestamp{Time: time.Now()},
+				Output: &github.CheckRunOutput{
+					Title:   github.String("Superseded"),
+					Summary: github.String(supersededCheckRunSummary),
+				},
+			})
+		if err != nil {
+			return fmt.Errorf("cannot cancel check run %s: %w", checkRun.GetName(), err)
+		}
+		if v.Logger != nil {
+			v.Logger.Infof("cancelled check run %s of pipelinerun %s superseded on commit %s",
+				checkRun.GetName(), checkRun.GetExternalID(), runevent.PreviousSHA)
+		}
+	}
+	return nil
+}
+
+// runningPipelineRuns returns the names of the PipelineRuns of the commit in
+// the namespace of the repository which are not done yet, their check runs
+// will still be updated.
+func (v *Provider) runningPipelineRuns(ctx context.Context, namespace, sha string) (map[string]bool, error) {
+	running := map[string]bool{}
+	if v.Run == nil || v.Run.Clients.Tekton == nil {
+		return running, nil
+	}
+	prs, err := v.Run.Clients.Tekton.TektonV1().PipelineRuns(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.SHA, formatting.CleanValueKubernetes(sha)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the pipelineruns of commit %s: %w", sha, err)
+	}
+	for _, pr := range prs.Items {
+		if !pr.IsDone() {
+			running[pr.GetName()] = true
+		}
+	}
+	return running, nil
+}
+
+// resolveCheckRunTarget sets the PipelineRun to re-run or to cancel from the
+// check run of the event, with the name in the .tekton directory of the
+// PipelineRun named by the external id of the check run. The external id of
+// the check run of a task is the name of its TaskRun, the PipelineRun is then
+// the one the TaskRun is a child of. Only the PipelineRuns of the Repository
+// are looked at, another Repository may have the same commit.
+func (v *Provider) resolveCheckRunTarget(ctx context.Context, runevent *info.Event) error {
+	externalID := runevent.CheckRunExternalID
+	if externalID == "" {
+		return nil
+	}
+	if v.repo == nil || v.Run == nil || v.Run.Clients.Tekton == nil {
+		return fmt.Errorf("cannot look up the PipelineRun of check run %s without its repository", externalID)
+	}
+	prs, err := v.Run.Clients.Tekton.TektonV1().PipelineRuns(v.repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s",
+			keys.SHA, formatting.CleanValueKubernetes(runevent.SHA),
+			keys.Repository, formatting.CleanValueKubernetes(v.repo.GetName())),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot list the pipelineruns of commit %s: %w", runevent.SHA, err)
+	}
+
+	var target *tektonv1.PipelineRun
+	for i := range prs.Items {
+		if prs.Items[i].GetName() == externalID {
+			target = &prs.Items[i]
+			break
+		}
+	}
+	// not a PipelineRun, the check run of a task reports a TaskRun
+	for i := 0; target == nil && i < len(prs.Items); i++ {
+		for _, child := range prs.Items[i].Status.ChildReferences {
+			if child.Kind == "TaskRun" && child.Name == externalID {
+				target = &prs.Items[i]
+				break
+			}
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("cannot find the PipelineRun or TaskRun %s of the check run in repository %s/%s",
+			externalID, v.repo.GetNamespace(), v.repo.GetName())
+	}
+	name := target.GetAnnotations()[keys.OriginalPRName]
+	if name == "" {
+		return fmt.Errorf("PipelineRun %s of check run %s has no %s annotation", target.GetName(), externalID, keys.OriginalPRName)
+	}
+	if runevent.CancelPipelineRuns {
+		runevent.TargetCancelPipelineRun = name
+	} else {
+		runevent.TargetTestPipelineRun = name
+	}
+	return nil
+}
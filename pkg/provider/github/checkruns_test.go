@@ -0,0 +1,235 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCancelInProgressCheckRuns(t *testing.T) {
+	runningPR := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr-running",
+			Namespace: "ns",
+			Labels:    map[string]string{keys.SHA: "previoussha"},
+		},
+	}
+	// running in the namespace of another repository with the same name
+	otherNamespacePR := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr-finished",
+			Namespace: "other",
+			Labels:    map[string]string{keys.SHA: "previoussha"},
+		},
+	}
+	tests := []struct {
+		name          string
+		previousSHA   string
+		noAppID       bool
+		noRepo        bool
+		statusMode    string
+		wantCancelled []int64
+	}{
+		{
+			name:          "stale check runs of the previous head are cancelled",
+			previousSHA:   "previoussha",
+			wantCancelled: []int64{1, 3},
+		},
+		{
+			name:        "not a pull request update",
+			previousSHA: "",
+		},
+		{
+			name:        "head not changed",
+			previousSHA: "sha",
+		},
+		{
+			name:        "unknown application id",
+			previousSHA: "previoussha",
+			noAppID:     true,
+		},
+		{
+			name:        "unknown repository",
+			previousSHA: "previoussha",
+			noRepo:      true,
+		},
+		{
+			name:        "reported as commit statuses",
+			previousSHA: "previoussha",
+			statusMode:  settings.StatusModeCommitStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			mux.HandleFunc("/repos/owner/repository/commits/previoussha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("app_id"), "42")
+				assert.Equal(t, r.URL.Query().Get("status"), "in_progress")
+				_, _ = fmt.Fprint(w, `{"total_count": 4, "check_runs": [
+					{"id": 1, "name": "Pipelines as Code CI / deleted", "external_id": "pr-deleted"},
+					{"id": 2, "name": "Pipelines as Code CI / running", "external_id": "pr-running"},
+					{"id": 3, "name": "Pipelines as Code CI / finished", "external_id": "pr-finished"},
+					{"id": 4, "name": "Pending approval"}
+				]}`)
+			})
+			cancelled := []int64{}
+			for _, id := range []int64{1, 2, 3, 4} {
+				id := id
+				mux.HandleFunc(fmt.Sprintf("/repos/owner/repository/check-runs/%d", id), func(w http.ResponseWriter, r *http.Request) {
+					checkRun := &github.UpdateCheckRunOptions{}
+					assert.NilError(t, json.NewDecoder(r.Body).Decode(checkRun))
+					assert.Equal(t, checkRun.GetStatus(), "completed")
+					assert.Equal(t, checkRun.GetConclusion(), "cancelled")
+					cancelled = append(cancelled, id)
+					_, _ = fmt.Fprintf(w, `{"id": %d}`, id)
+				})
+			}
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				PipelineRuns: []*tektonv1.PipelineRun{runningPR, otherNamespacePR},
+			})
+			cnx := New()
+			cnx.Client = fakeclient
+			cnx.Logger, _ = logger.GetLogger()
+			cnx.Run = params.New()
+			cnx.Run.Clients = clients.Clients{Tekton: stdata.Pipeline}
+			cnx.Run.Info.Pac = &info.PacOpts{Settings: &settings.Settings{StatusMode: tt.statusMode}}
+			if !tt.noAppID {
+				cnx.ApplicationID = github.Int64(42)
+			}
+			if !tt.noRepo {
+				cnx.repo = &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+			}
+			event := &info.Event{
+				Organization:   "owner",
+				Repository:     "repository",
+				SHA:            "sha",
+				PreviousSHA:    tt.previousSHA,
+				InstallationID: 12345,
+			}
+			assert.NilError(t, cnx.CancelInProgressCheckRuns(ctx, event))
+			if tt.wantCancelled == nil {
+				tt.wantCancelled = []int64{}
+			}
+			assert.DeepEqual(t, cancelled, tt.wantCancelled)
+		})
+	}
+}
+
+func TestResolveCheckRunTarget(t *testing.T) {
+	pipelineRun := func(name, namespace, repo, originalName string, taskRuns ...string) *tektonv1.PipelineRun {
+		pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{keys.SHA: "sha", keys.Repository: repo},
+			Annotations: map[string]string{keys.OriginalPRName: originalName},
+		}}
+		for _, taskRun := range taskRuns {
+			child := tektonv1.ChildStatusReference{Name: taskRun}
+			child.Kind = "TaskRun"
+			pr.Status.ChildReferences = append(pr.Status.ChildReferences, child)
+		}
+		return pr
+	}
+	pipelineRuns := []*tektonv1.PipelineRun{
+		pipelineRun("pr-abcde", "ns", "repo", "pr", "pr-abcde-unit"),
+		pipelineRun("e2e-tests", "ns", "repo", "e2e-tests"),
+		// the same commit tested by another Repository
+		pipelineRun("other-abcde", "other-ns", "fork", "other", "other-abcde-unit"),
+		pipelineRun("other-fghij", "ns", "fork", "other"),
+	}
+
+	tests := []struct {
+		name       string
+		externalID string
+		cancel     bool
+		wantTest   string
+		wantCancel string
+		wantErr    string
+	}{
+		{
+			name: "no check run",
+		},
+		{
+			name:       "check run of a pipelinerun",
+			externalID: "pr-abcde",
+			wantTest:   "pr",
+		},
+		{
+			name:       "pipelinerun without a generated name",
+			externalID: "e2e-tests",
+			wantTest:   "e2e-tests",
+		},
+		{
+			name:       "check run of a task",
+			externalID: "pr-abcde-unit",
+			wantTest:   "pr",
+		},
+		{
+			name:       "cancelled check run",
+			externalID: "pr-abcde",
+			cancel:     true,
+			wantCancel: "pr",
+		},
+		{
+			name:       "pipelinerun of another namespace",
+			externalID: "other-abcde",
+			wantErr:    "cannot find the PipelineRun or TaskRun other-abcde of the check run in repository ns/repo",
+		},
+		{
+			name:       "task of another namespace",
+			externalID: "other-abcde-unit",
+			wantErr:    "cannot find the PipelineRun or TaskRun other-abcde-unit of the check run in repository ns/repo",
+		},
+		{
+			name:       "pipelinerun of another repository",
+			externalID: "other-fghij",
+			wantErr:    "cannot find the PipelineRun or TaskRun other-fghij of the check run in repository ns/repo",
+		},
+		{
+			name:       "deleted pipelinerun",
+			externalID: "pr-fghij",
+			wantErr:    "cannot find the PipelineRun or TaskRun pr-fghij of the check run in repository ns/repo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: pipelineRuns})
+			v := &Provider{
+				Run:  &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline}},
+				repo: &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}},
+			}
+			event := &info.Event{SHA: "sha", CheckRunExternalID: tt.externalID}
+			event.CancelPipelineRuns = tt.cancel
+
+			err := v.resolveCheckRunTarget(ctx, event)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, event.TargetTestPipelineRun, tt.wantTest)
+			assert.Equal(t, event.TargetCancelPipelineRun, tt.wantCancel)
+		})
+	}
+}
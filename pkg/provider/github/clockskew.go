@@ -0,0 +1,51 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
+)
+
+// maxClockSkew is the difference between our clock and the one of GitHub
+// from which we consider a refused JWT is caused by a clock skew, GitHub
+// accepts the JWT issued up to 60 seconds in the future.
+const maxClockSkew = 30 * time.Second
+
+// ErrClockSkew is returned when GitHub refuses to mint an installation token
+// and our clock is too far from the one of GitHub.
+type ErrClockSkew struct {
+	// Skew is how much our clock is ahead of the one of GitHub, negative
+	// when behind.
+	Skew time.Duration
+	Err  error
+}
+
+func (e *ErrClockSkew) Error() string {
+	return fmt.Sprintf("%v: the local clock is %s off from the GitHub server clock, make sure the clock of the cluster nodes is synchronized with NTP", e.Err, e.Skew)
+}
+
+func (e *ErrClockSkew) Unwrap() error {
+	return e.Err
+}
+
+// checkClockSkew wraps a 401 error minting an installation token in an
+// ErrClockSkew when the Date header of the GitHub response is too far from
+// now.
+func checkClockSkew(err error, now time.Time) error {
+	var httpErr *ghinstallation.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Response == nil || httpErr.Response.StatusCode != http.StatusUnauthorized {
+		return err
+	}
+	date, perr := http.ParseTime(httpErr.Response.Header.Get("Date"))
+	if perr != nil {
+		return err
+	}
+	skew := now.Sub(date)
+	if skew.Abs() < maxClockSkew {
+		return err
+	}
+	return &ErrClockSkew{Skew: skew.Round(time.Second), Err: err}
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/env"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestAppTokenClockSkew(t *testing.T) {
+	testNamespace := "pipelinesascode"
+	secretName := "pipelines-as-code-secret"
+	ctx, _ := rtesting.SetupFakeContext(t)
+	seedData, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Secret: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: testNamespace,
+				},
+				Data: map[string][]byte{
+					"github-application-id": []byte("12345"),
+					"github-private-key":    []byte(fakePrivateKey),
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		statusCode int
+		serverTime time.Time
+		wantSkew   bool
+	}{
+		{
+			name:       "server clock far behind",
+			statusCode: http.StatusUnauthorized,
+			serverTime: time.Now().Add(-10 * time.Minute),
+			wantSkew:   true,
+		},
+		{
+			name:       "server clock far ahead",
+			statusCode: http.StatusUnauthorized,
+			serverTime: time.Now().Add(10 * time.Minute),
+			wantSkew:   true,
+		},
+		{
+			name:       "clocks in sync",
+			statusCode: http.StatusUnauthorized,
+			serverTime: time.Now(),
+		},
+		{
+			name:       "not an unauthorized error",
+			statusCode: http.StatusInternalServerError,
+			serverTime: time.Now().Add(-10 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc(fmt.Sprintf("/app/installations/%d/access_tokens", testInstallationID), func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Date", tt.serverTime.UTC().Format(http.TimeFormat))
+				w.WriteHeader(tt.statusCode)
+			})
+			defer env.Patch(t, "PAC_GIT_PROVIDER_TOKEN_APIURL", serverURL+"/api/v3")()
+
+			logger, _ := logger.GetLogger()
+			gprovider := Provider{
+				Logger: logger,
+				Run: &params.Run{
+					Clients: clients.Clients{Log: logger, Kube: seedData.Kube},
+					Info: info.Info{
+						Pac:        &info.PacOpts{Settings: &settings.Settings{}},
+						Controller: &info.ControllerInfo{Secret: secretName},
+					},
+				},
+			}
+			_, err := gprovider.GetAppToken(ctx, seedData.Kube, "", testInstallationID, testNamespace)
+			assert.Assert(t, err != nil)
+
+			var skewErr *ErrClockSkew
+			if !tt.wantSkew {
+				assert.Assert(t, !errors.As(err, &skewErr), err.Error())
+				return
+			}
+			assert.Assert(t, errors.As(err, &skewErr), err.Error())
+			want := time.Now().Sub(tt.serverTime)
+			assert.Assert(t, (skewErr.Skew-want).Abs() < 5*time.Second, "skew %s, want %s", skewErr.Skew, want)
+			assert.ErrorContains(t, err, "synchronized with NTP")
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+)
+
+// defaultCommentLogSnippetLines is the number of lines of the logs of the
+// failed tasks in the comment when none is set in the settings.
+const defaultCommentLogSnippetLines = 20
+
+// statusCommentMarker is the hidden marker of the comment of the status of a
+// PipelineRun on a pull request, to edit it instead of adding another comment
+// on every run.
+func statusCommentMarker(status provider.StatusOpts) string {
+	name := status.OriginalPipelineRunName
+	if name == "" {
+		name = status.PipelineRunName
+	}
+	return fmt.Sprintf("<!-- pac-status:%s -->", name)
+}
+
+// makeStatusComment makes the comment of the status on the pull request,
+// starting with its marker.
+func makeStatusComment(status provider.StatusOpts) *github.IssueComment {
+	marker := statusCommentMarker(status)
+	text := formatting.TruncateText(fmt.Sprintf("%s<br>%s", status.Summary, status.Text), maxTextSize-len(marker)-1, statusLogURL(status))
+	return &github.IssueComment{Body: github.String(marker + "\n" + text)}
+}
+
+// failedTaskLogs renders the last lines of the logs of the failed tasks of the
+// PipelineRun in collapsible blocks, with a note for the tasks whose logs are
+// not available (i.e: the pod is gone or the step is still streaming them).
+// The values of the secrets attached to the PipelineRun are hidden from the
+// logs.
+func (v *Provider) failedTaskLogs(ctx context.Context, status provider.StatusOpts) string {
+	if status.PipelineRun == nil {
+		return ""
+	}
+	if v.kinteract == nil {
+		v.Logger.Warnf("cannot get the logs of the failed tasks of pipelinerun %s without a kubernetes client", status.PipelineRunName)
+		return ""
+	}
+	lines := v.Run.Info.Pac.CommentLogSnippetLines
+	if lines <= 0 {
+		lines = defaultCommentLogSnippetLines
+	}
+	var secretValues []ktypes.SecretValue
+	blocks := []string{}
+	for _, ti := range sort.TaskInfos(kstatus.CollectFailedTasksLogSnippet(ctx, v.Run, v.kinteract, status.PipelineRun, int64(lines))) {
+		name := ti.Name
+		if ti.DisplayName != "" {
+			name = ti.DisplayName
+		}
+		if ti.LogSnippet == "" {
+			note := fmt.Sprintf("The logs of task <b>%s</b> are not available", name)
+			if logURL := statusLogURL(status); logURL != "" {
+				note += fmt.Sprintf(", see the [full logs](%s)", logURL)
+			}
+			blocks = append(blocks, note+".")
+			continue
+		}
+		if secretValues == nil {
+			secretValues = secrets.GetSecretsAttachedToPipelineRun(ctx, v.kinteract, status.PipelineRun)
+		}
+		blocks = append(blocks, fmt.Sprintf("<details>\n<summary>Last %d lines of the logs of task <b>%s</b></summary>\n\n<pre>%s</pre>\n</details>",
+			lines, name, html.EscapeString(secrets.ReplaceSecretsInText(ti.LogSnippet, secretValues))))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// findStatusComment returns the id of the comment of the pull request with
+// with the marker, nil when there is none.
+func (v *Provider) findStatusComment(ctx context.Context, runevent *info.Event, marker string) (*int64, error) {
+	opt := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: v.paginedNumber},
+	}
+	for {
+		comments, resp, err := v.issues().ListComments(ctx, runevent.Organization, runevent.Repository,
+			runevent.PullRequestNumber, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.GetBody(), marker) {
+				return comment.ID, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return nil, nil
+}
+
+// upsertStatusComment edits the comment of the status of the PipelineRun on
+// the pull request, or creates it when there is none yet, so there is only
+// one comment per PipelineRun whatever the number of runs.
+func (v *Provider) upsertStatusComment(ctx context.Context, runevent *info.Event, status provider.StatusOpts) error {
+	comment := makeStatusComment(status)
+	commentID, err := v.findStatusComment(ctx, runevent, statusCommentMarker(status))
+	if err != nil {
+		return fmt.Errorf("cannot list the comments of pull request %d: %w", runevent.PullRequestNumber, err)
+	}
+	if commentID != nil {
+		_, _, err = v.issues().EditComment(ctx, runevent.Organization, runevent.Repository, *commentID, comment)
+		return err
+	}
+	_, _, err = v.issues().CreateComment(ctx, runevent.Organization, runevent.Repository,
+		runevent.PullRequestNumber, comment)
+	return err
+}
@@ -0,0 +1,264 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestStatusCommentMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		status provider.StatusOpts
+		want   string
+	}{
+		{
+			name:   "original pipelinerun name",
+			status: provider.StatusOpts{PipelineRunName: "pr-abcde", OriginalPipelineRunName: "pr"},
+			want:   "<!-- pac-status:pr -->",
+		},
+		{
+			name:   "no original pipelinerun name",
+			status: provider.StatusOpts{PipelineRunName: "pr-abcde"},
+			want:   "<!-- pac-status:pr-abcde -->",
+		},
+		{
+			name: "no pipelinerun",
+			want: "<!-- pac-status: -->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, statusCommentMarker(tt.status), tt.want)
+		})
+	}
+}
+
+func TestUpsertStatusComment(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         [][]string
+		wantEditedID  int64
+		wantCreated   bool
+		pipelineRun   string
+		originalPRRun string
+	}{
+		{
+			name:          "created when there is no comment",
+			pages:         [][]string{{"LGTM"}},
+			originalPRRun: "pr",
+			pipelineRun:   "pr-abcde",
+			wantCreated:   true,
+		},
+		{
+			name: "edited on the second page",
+			pages: [][]string{
+				{"LGTM", "<!-- pac-status:other -->\nother"},
+				{"LGTM", "<!-- pac-status:pr -->\nprevious run"},
+			},
+			originalPRRun: "pr",
+			pipelineRun:   "pr-fghij",
+			wantEditedID:  4,
+		},
+		{
+			name:         "edited by pipelinerun name without original name",
+			pages:        [][]string{{"<!-- pac-status:pr-abcde -->\nprevious"}},
+			pipelineRun:  "pr-abcde",
+			wantEditedID: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			event := &info.Event{Organization: "owner", Repository: "repo", PullRequestNumber: 10}
+			status := provider.StatusOpts{
+				PipelineRunName:         tt.pipelineRun,
+				OriginalPipelineRunName: tt.originalPRRun,
+				Summary:                 "summary",
+				Text:                    "text",
+			}
+			created := false
+			mux.HandleFunc("/repos/owner/repo/issues/10/comments", func(rw http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					created = true
+					comment := &github.IssueComment{}
+					assert.NilError(t, json.NewDecoder(r.Body).Decode(comment))
+					assert.Equal(t, comment.GetBody(), statusCommentMarker(status)+"\nsummary<br>text")
+					_, _ = fmt.Fprint(rw, `{}`)
+					return
+				}
+				page := 1
+				if r.URL.Query().Get("page") == "2" {
+					page = 2
+				}
+				if page < len(tt.pages) {
+					rw.Header().Add("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page+1))
+				}
+				comments := []*github.IssueComment{}
+				for i, body := range tt.pages[page-1] {
+					comments = append(comments, &github.IssueComment{
+						ID:   github.Int64(int64((page-1)*len(tt.pages[0]) + i + 1)),
+						Body: github.String(body),
+					})
+				}
+				assert.NilError(t, json.NewEncoder(rw).Encode(comments))
+			})
+			edited := int64(0)
+			mux.HandleFunc("/repos/owner/repo/issues/comments/", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodPatch)
+				var err error
+				edited, err = strconv.ParseInt(path.Base(r.URL.Path), 10, 64)
+				assert.NilError(t, err)
+				_, _ = fmt.Fprint(rw, `{}`)
+			})
+
+			cnx := New()
+			cnx.Client = fakeclient
+			assert.NilError(t, cnx.upsertStatusComment(ctx, event, status))
+			assert.Equal(t, created, tt.wantCreated)
+			assert.Equal(t, edited, tt.wantEditedID)
+		})
+	}
+}
+
+func TestCreateStatusCommentLogSnippet(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	failedTaskRun := func(name string) *tektonv1.TaskRun {
+		return tektontest.MakeTaskRunCompletion(clock, name, "ns", "pr", map[string]string{},
+			tektonv1.TaskRunStatusFields{
+				PodName: name + "-pod",
+				Steps: []tektonv1.StepState{{
+					Name:           "step",
+					ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+				}},
+			},
+			knativeduckv1.Conditions{{
+				Type:   knativeapi.ConditionSucceeded,
+				Status: corev1.ConditionFalse,
+				Reason: tektonv1.PipelineRunReasonFailed.String(),
+			}}, 10)
+	}
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		conclusion  string
+		buildLogs   string
+		wantText    []string
+		notWantText []string
+	}{
+		{
+			name:       "failure with the log snippet",
+			enabled:    true,
+			conclusion: "failure",
+			wantText: []string{
+				"<details>\n<summary>Last 5 lines of the logs of task <b>build</b></summary>\n\n<pre>error: &lt;main&gt; does not compile</pre>\n</details>",
+				"The logs of task <b>test</b> are not available, see the [full logs](https://console/pr).",
+			},
+		},
+		{
+			name:       "secret in the logs",
+			enabled:    true,
+			conclusion: "failure",
+			buildLogs:  "pushing with token s3cr3t-t0k3n",
+			wantText: []string{
+				"<pre>pushing with token *****</pre>",
+			},
+			notWantText: []string{"s3cr3t-t0k3n"},
+		},
+		{
+			name:        "log snippet disabled",
+			conclusion:  "failure",
+			notWantText: []string{"Last 5 lines", "are not available"},
+		},
+		{
+			name:        "success",
+			enabled:     true,
+			conclusion:  "success",
+			notWantText: []string{"Last 5 lines", "are not available"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			v, _, _, issues := newFakeProvider()
+			v.Run.Info.Pac.CommentLogSnippet = tt.enabled
+			v.Run.Info.Pac.CommentLogSnippetLines = 5
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				TaskRuns: []*tektonv1.TaskRun{failedTaskRun("build"), failedTaskRun("test")},
+			})
+			v.Run.Clients = clients.Clients{Tekton: stdata.Pipeline, Log: v.Logger}
+			if tt.buildLogs == "" {
+				tt.buildLogs = "error: <main> does not compile"
+			}
+			v.kinteract = &kubernetestint.KinterfaceTest{
+				GetPodLogsOutput: map[string]string{"build-pod": tt.buildLogs},
+				GetSecretResult:  map[string]string{"push-secret": "s3cr3t-t0k3n"},
+			}
+			pr := tektontest.MakePRCompletion(clock, "pr", "ns", tektonv1.PipelineRunReasonFailed.String(), nil, map[string]string{}, 10)
+			pr.Status.ChildReferences = []tektonv1.ChildStatusReference{
+				{TypeMeta: runtime.TypeMeta{Kind: "TaskRun"}, Name: "build", PipelineTaskName: "build"},
+				{TypeMeta: runtime.TypeMeta{Kind: "TaskRun"}, Name: "test", PipelineTaskName: "test"},
+			}
+			pr.Spec.PipelineSpec = &tektonv1.PipelineSpec{Tasks: []tektonv1.PipelineTask{{
+				Name: "build",
+				TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{Steps: []tektonv1.Step{{
+					Name: "step",
+					Env: []corev1.EnvVar{{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "push-secret"},
+						Key:                  "token",
+					}}}},
+				}}}},
+			}}}
+			event := &info.Event{
+				Organization:      "owner",
+				Repository:        "repo",
+				SHA:               "sha",
+				EventType:         triggertype.PullRequest.String(),
+				PullRequestNumber: 1,
+			}
+
+			assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+				PipelineRun:     pr,
+				PipelineRunName: "pr",
+				Status:          "completed",
+				Conclusion:      tt.conclusion,
+				Text:            "tasks status",
+				DetailsURL:      "https://console/pr",
+			}))
+			assert.Equal(t, len(issues.Comments), 1)
+			body := issues.Comments[0].GetBody()
+			for _, want := range tt.wantText {
+				assert.Assert(t, strings.Contains(body, want), body)
+			}
+			for _, notWant := range tt.notWantText {
+				assert.Assert(t, !strings.Contains(body, notWant), body)
+			}
+		})
+	}
+}
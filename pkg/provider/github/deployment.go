@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// deploymentEnvironment returns the environment a PipelineRun deploys to
+// from its annotation, empty when it doesn't.
+func deploymentEnvironment(statusOpts provider.StatusOpts) string {
+	if statusOpts.PipelineRun == nil {
+		return ""
+	}
+	return statusOpts.PipelineRun.GetAnnotations()[keys.Environment]
+}
+
+// deploymentState maps the status of a PipelineRun to the state of a
+// deployment status.
+func deploymentState(statusOpts provider.StatusOpts) string {
+	switch statusOpts.Status {
+	case "queued":
+		return "queued"
+	case "in_progress":
+		return "in_progress"
+	}
+	switch statusOpts.Conclusion {
+	case "success":
+		return "success"
+	case "failure":
+		return "failure"
+	case "pending", "action_required":
+		return "pending"
+	default:
+		// cancelled, neutral and skipped runs haven't deployed anything
+		return "inactive"
+	}
+}
+
+// deploymentAllowed returns true when the PipelineRuns of the repository can
+// report a deployment to the environment, from the environments allowed on
+// the Repository CR or else by the status-deployment-environments setting.
+// The annotation comes from the PipelineRun which may be written by anyone
+// opening a pull request, so nothing is allowed unless an admin did.
+func (v *Provider) deploymentAllowed(environment string) bool {
+	if v.repo != nil && v.repo.Spec.Settings != nil && len(v.repo.Spec.Settings.DeploymentEnvironments) > 0 {
+		return provider.MatchGlobs(environment, v.repo.Spec.Settings.DeploymentEnvironments)
+	}
+	return provider.MatchGlobs(environment, strings.Split(v.Run.Info.Pac.StatusDeploymentEnvironments, ","))
+}
+
+// createDeploymentStatus reports the status of a PipelineRun annotated with
+// an allowed environment as a status of the deployment of the commit to the
+// environment, the deployment is created with the first status.
+func (v *Provider) createDeploymentStatus(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts) error {
+	environment := deploymentEnvironment(statusOpts)
+	if environment == "" {
+		return nil
+	}
+	if !v.deploymentAllowed(environment) {
+		v.Logger.Infof("not reporting pipelinerun %s as a deployment, environment %s is not allowed for the repository",
+			statusOpts.PipelineRunName, environment)
+		return nil
+	}
+	name := statusOpts.OriginalPipelineRunName
+	if name == "" {
+		name = statusOpts.PipelineRunName
+	}
+	task := fmt.Sprintf("deploy:%s", name)
+	request := &github.DeploymentStatusRequest{
+		State:       github.String(deploymentState(statusOpts)),
+		LogURL:      github.String(statusOpts.DetailsURL),
+		Description: github.String(statusOpts.Title),
+		Environment: github.String(environment),
+	}
+
+	if v.Run.Info.Pac.DryRun {
+		return v.logDryRun("deployment status", environment, request.GetState(), statusOpts.Summary, request)
+	}
+
+	start := time.Now()
+	deployments, _, err := v.repositories().ListDeployments(ctx, runevent.Organization, runevent.Repository,
+		&github.DeploymentsListOptions{SHA: runevent.SHA, Task: task, Environment: environment})
+	v.recordAPILatency("ListDeployments", time.Since(start))
+	if err != nil {
+		return err
+	}
+	var deploymentID int64
+	if len(deployments) > 0 {
+		deploymentID = deployments[0].GetID()
+	} else {
+		start = time.Now()
+		deployment, _, err := v.repositories().CreateDeployment(ctx, runevent.Organization, runevent.Repository, &github.DeploymentRequest{
+			Ref:         github.String(runevent.SHA),
+			Task:        github.String(task),
+			Environment: github.String(environment),
+			Description: github.String(fmt.Sprintf("PipelineRun %s", name)),
+			AutoMerge:   github.Bool(false),
+			// our own check runs are still in progress, they must not block
+			// the deployment, the environments skipping the required
+			// contexts are the ones allowed by an admin
+			RequiredContexts: &[]string{},
+		})
+		v.recordAPILatency("CreateDeployment", time.Since(start))
+		if err != nil {
+			return err
+		}
+		deploymentID = deployment.GetID()
+	}
+
+	start = time.Now()
+	_, _, err = v.repositories().CreateDeploymentStatus(ctx, runevent.Organization, runevent.Repository, deploymentID, request)
+	v.recordAPILatency("CreateDeploymentStatus", time.Since(start))
+	return err
+}
@@ -0,0 +1,164 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestDeploymentState(t *testing.T) {
+	tests := []struct {
+		status, conclusion, want string
+	}{
+		{status: "queued", conclusion: "pending", want: "queued"},
+		{status: "in_progress", conclusion: "pending", want: "in_progress"},
+		{status: "completed", conclusion: "success", want: "success"},
+		{status: "completed", conclusion: "failure", want: "failure"},
+		{status: "completed", conclusion: "pending", want: "pending"},
+		{status: "completed", conclusion: "cancelled", want: "inactive"},
+		{status: "completed", conclusion: "neutral", want: "inactive"},
+		{status: "completed", conclusion: "skipped", want: "inactive"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.status, tt.conclusion), func(t *testing.T) {
+			assert.Equal(t, deploymentState(provider.StatusOpts{Status: tt.status, Conclusion: tt.conclusion}), tt.want)
+		})
+	}
+}
+
+func TestCreateStatusDeployment(t *testing.T) {
+	event := &info.Event{
+		Organization:   "owner",
+		Repository:     "repo",
+		SHA:            "sha",
+		InstallationID: 1,
+	}
+	// the check run already exists, so the PipelineRun doesn't get patched
+	// with its id
+	pipelineRun := func(annotations map[string]string) *tektonv1.PipelineRun {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[keys.CheckRunID] = "1"
+		return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-abcde", Annotations: annotations}}
+	}
+	newCheckRun := func() *github.CheckRun {
+		return &github.CheckRun{ID: github.Int64(1), Name: github.String("Pipelines as Code CI / pr"), HeadSHA: github.String("sha")}
+	}
+
+	t.Run("annotated with an environment", func(t *testing.T) {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		v, checks, repositories, _ := newFakeProvider()
+		v.Run.Info.Pac.StatusDeploymentEnvironments = "prod-*, staging"
+		checks.AddCheckRun("owner", "repo", newCheckRun())
+		pr := pipelineRun(map[string]string{keys.Environment: "staging"})
+		for _, status := range []provider.StatusOpts{
+			{Status: "in_progress", Conclusion: "pending"},
+			{Status: "completed", Conclusion: "success"},
+		} {
+			status.PipelineRun = pr
+			status.PipelineRunName = "pr-abcde"
+			status.OriginalPipelineRunName = "pr"
+			status.DetailsURL = "https://console/pr-abcde"
+			assert.NilError(t, v.CreateStatus(ctx, event, status))
+		}
+
+		assert.Equal(t, len(checks.CheckRuns), 1)
+		assert.Equal(t, len(repositories.Deployments), 1)
+		deployment := repositories.Deployments[0]
+		assert.Equal(t, deployment.GetEnvironment(), "staging")
+		assert.Equal(t, deployment.GetSHA(), "sha")
+		assert.Equal(t, deployment.GetTask(), "deploy:pr")
+		assert.Equal(t, len(deployment.Statuses), 2)
+		assert.Equal(t, deployment.Statuses[0].GetState(), "in_progress")
+		assert.Equal(t, deployment.Statuses[1].GetState(), "success")
+		assert.Equal(t, deployment.Statuses[1].GetLogURL(), "https://console/pr-abcde")
+	})
+
+	t.Run("not annotated", func(t *testing.T) {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		v, checks, repositories, _ := newFakeProvider()
+		checks.AddCheckRun("owner", "repo", newCheckRun())
+		assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+			PipelineRun:     pipelineRun(nil),
+			PipelineRunName: "pr-abcde",
+			Status:          "completed",
+			Conclusion:      "success",
+		}))
+		assert.Equal(t, len(checks.CheckRuns), 1)
+		assert.Equal(t, len(repositories.Deployments), 0)
+	})
+
+	t.Run("deployment failing", func(t *testing.T) {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		v, checks, repositories, _ := newFakeProvider()
+		v.Run.Info.Pac.StatusDeploymentEnvironments = "staging"
+		checks.AddCheckRun("owner", "repo", newCheckRun())
+		repositories.Err = fmt.Errorf("resource not accessible by integration")
+		assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+			PipelineRun:     pipelineRun(map[string]string{keys.Environment: "staging"}),
+			PipelineRunName: "pr-abcde",
+			Status:          "completed",
+			Conclusion:      "success",
+		}))
+		assert.Equal(t, len(checks.CheckRuns), 1)
+	})
+
+	allowedTests := []struct {
+		name            string
+		setting         string
+		repoEnvironment []string
+		wantDeployments int
+	}{
+		{
+			name:            "no environment allowed",
+			wantDeployments: 0,
+		},
+		{
+			name:            "environment not allowed by the settings",
+			setting:         "prod-*",
+			wantDeployments: 0,
+		},
+		{
+			name:            "environment allowed on the repository",
+			repoEnvironment: []string{"stag*"},
+			wantDeployments: 1,
+		},
+		{
+			name:            "repository overriding the settings",
+			setting:         "staging",
+			repoEnvironment: []string{"prod"},
+			wantDeployments: 0,
+		},
+	}
+	for _, tt := range allowedTests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			v, checks, repositories, _ := newFakeProvider()
+			v.Run.Info.Pac.StatusDeploymentEnvironments = tt.setting
+			if tt.repoEnvironment != nil {
+				v.repo = &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{
+					Settings: &v1alpha1.Settings{DeploymentEnvironments: tt.repoEnvironment},
+				}}
+			}
+			checks.AddCheckRun("owner", "repo", newCheckRun())
+			assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+				PipelineRun:     pipelineRun(map[string]string{keys.Environment: "staging"}),
+				PipelineRunName: "pr-abcde",
+				Status:          "completed",
+				Conclusion:      "success",
+			}))
+			assert.Equal(t, len(checks.CheckRuns), 1)
+			assert.Equal(t, len(repositories.Deployments), tt.wantDeployments)
+		})
+	}
+}
@@ -40,6 +40,14 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 	}
 
 	_ = json.Unmarshal([]byte(payload), &eventInt)
+	// the repositories of the installation have changed, the cached ones are
+	// invalidated once the signature of the event has been validated
+	if event, ok := eventInt.(*github.InstallationRepositoriesEvent); ok {
+		if event.GetInstallation() == nil {
+			return setLoggerAndProceed(false, "installation_repositories: no installation in payload", nil)
+		}
+		return setLoggerAndProceed(true, "", nil)
+	}
 	eType, errReason := detectTriggerTypeFromPayload(eventType, eventInt)
 	if eType != "" {
 		return setLoggerAndProceed(true, "", nil)
@@ -59,7 +67,7 @@ func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype
 		}
 		return "", "no pusher in payload"
 	case *github.PullRequestEvent:
-		if provider.Valid(event.GetAction(), []string{"opened", "synchronize", "synchronized", "reopened"}) {
+		if provider.Valid(event.GetAction(), []string{"opened", "synchronize", "synchronized", "reopened", "ready_for_review"}) {
 			return triggertype.PullRequest, ""
 		}
 		return "", fmt.Sprintf("pull_request: unsupported action \"%s\"", event.GetAction())
@@ -87,6 +95,12 @@ func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype
 		if event.GetAction() == "rerequested" && event.GetCheckRun() != nil {
 			return triggertype.CheckRunRerequested, ""
 		}
+		if isRerunRequestedAction(event) {
+			return triggertype.CheckRunRerequested, ""
+		}
+		if isCancelRequestedAction(event) {
+			return triggertype.Cancel, ""
+		}
 		return "", fmt.Sprintf("check_run: unsupported action \"%s\"", event.GetAction())
 	case *github.CommitCommentEvent:
 		if event.GetAction() == "created" {
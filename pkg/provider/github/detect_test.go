@@ -58,6 +58,51 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "valid check run re-run action Event",
+			event: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				RequestedAction: &github.RequestedAction{
+					Identifier: RerunActionIdentifier,
+				},
+				CheckRun: &github.CheckRun{
+					ID: github.Int64(123),
+				},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "valid check run cancel action Event",
+			event: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				RequestedAction: &github.RequestedAction{
+					Identifier: CancelActionIdentifier,
+				},
+				CheckRun: &github.CheckRun{
+					ID: github.Int64(123),
+				},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "check run action Event not from us",
+			event: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				RequestedAction: &github.RequestedAction{
+					Identifier: "something-else",
+				},
+				CheckRun: &github.CheckRun{
+					ID: github.Int64(123),
+				},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: false,
+		},
 		{
 			name: "unsupported Event",
 			event: github.CommitCommentEvent{
@@ -208,6 +253,15 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "pull request event ready for review",
+			event: github.PullRequestEvent{
+				Action: github.String("ready_for_review"),
+			},
+			eventType:  "pull_request",
+			isGH:       true,
+			processReq: true,
+		},
 		{
 			name: "pull request event not supported action",
 			event: github.PullRequestEvent{
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// statusError maps the errors of the GitHub API reporting a status to the
+// kinds of provider.StatusError, the other errors are returned as is.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var (
+		statusErr         *provider.StatusError
+		rateLimitErr      *github.RateLimitError
+		abuseRateLimitErr *github.AbuseRateLimitError
+		missingErr        *MissingPermissionsError
+		responseErr       *github.ErrorResponse
+	)
+	switch {
+	case errors.As(err, &statusErr):
+		return err
+	case errors.As(err, &rateLimitErr), errors.As(err, &abuseRateLimitErr):
+		return provider.NewStatusError(provider.ErrRateLimited, err)
+	case errors.As(err, &missingErr):
+		return provider.NewStatusError(provider.ErrPermission, err)
+	case errors.As(err, &responseErr) && responseErr.Response != nil:
+		switch responseErr.Response.StatusCode {
+		case http.StatusUnauthorized:
+			return provider.NewStatusError(provider.ErrNoToken, err)
+		case http.StatusForbidden:
+			return provider.NewStatusError(provider.ErrPermission, err)
+		case http.StatusTooManyRequests:
+			return provider.NewStatusError(provider.ErrRateLimited, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"gotest.tools/v3/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	responseError := func(code int) error {
+		return &github.ErrorResponse{Response: &http.Response{
+			StatusCode: code,
+			Request:    &http.Request{Method: http.MethodPost},
+		}}
+	}
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:     "rate limit",
+			err:      &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			wantKind: provider.ErrRateLimited,
+		},
+		{
+			name:     "secondary rate limit",
+			err:      fmt.Errorf("cannot update check run: %w", &github.AbuseRateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}),
+			wantKind: provider.ErrRateLimited,
+		},
+		{
+			name:     "too many requests",
+			err:      responseError(http.StatusTooManyRequests),
+			wantKind: provider.ErrRateLimited,
+		},
+		{
+			name:     "bad credentials",
+			err:      responseError(http.StatusUnauthorized),
+			wantKind: provider.ErrNoToken,
+		},
+		{
+			name:     "forbidden",
+			err:      responseError(http.StatusForbidden),
+			wantKind: provider.ErrPermission,
+		},
+		{
+			name:     "missing permissions",
+			err:      &MissingPermissionsError{Missing: []string{"checks:write"}},
+			wantKind: provider.ErrPermission,
+		},
+		{
+			name: "not found",
+			err:  responseError(http.StatusNotFound),
+		},
+		{
+			name:     "already a status error",
+			err:      provider.NewStatusError(provider.ErrNoToken, errors.New("no token")),
+			wantKind: provider.ErrNoToken,
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statusError(tt.err)
+			if tt.err == nil {
+				assert.NilError(t, got)
+				return
+			}
+			assert.Assert(t, errors.Is(got, tt.err))
+			var statusErr *provider.StatusError
+			if tt.wantKind == nil {
+				assert.Assert(t, !errors.As(got, &statusErr))
+				return
+			}
+			assert.Assert(t, errors.As(got, &statusErr))
+			assert.Equal(t, statusErr.Kind, tt.wantKind)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// validationFailureReasons are the reasons of the PipelineRuns which have
+// failed before running any task because they are invalid.
+var validationFailureReasons = map[string]bool{
+	tektonv1.PipelineRunReasonFailedValidation.String():                true,
+	tektonv1.PipelineRunReasonCouldntGetPipeline.String():              true,
+	tektonv1.PipelineRunReasonCouldntGetTask.String():                  true,
+	tektonv1.PipelineRunReasonInvalidBindings.String():                 true,
+	tektonv1.PipelineRunReasonInvalidWorkspaceBinding.String():         true,
+	tektonv1.PipelineRunReasonInvalidTaskRunSpec.String():              true,
+	tektonv1.PipelineRunReasonParameterTypeMismatch.String():           true,
+	tektonv1.PipelineRunReasonObjectParameterMissKeys.String():         true,
+	tektonv1.PipelineRunReasonParamArrayIndexingInvalid.String():       true,
+	tektonv1.PipelineRunReasonParameterMissing.String():                true,
+	tektonv1.PipelineRunReasonInvalidGraph.String():                    true,
+	tektonv1.PipelineRunReasonInvalidMatrixParameterTypes.String():     true,
+	tektonv1.PipelineRunReasonInvalidTaskResultReference.String():      true,
+	tektonv1.PipelineRunReasonInvalidPipelineResultReference.String():  true,
+	tektonv1.PipelineRunReasonRequiredWorkspaceMarkedOptional.String(): true,
+	tektonv1.PipelineRunReasonResourceVerificationFailed.String():      true,
+	tektonv1.PipelineRunReasonCELEvaluationFailed.String():             true,
+	tektonv1.PipelineRunReasonInvalidParamValue.String():               true,
+	tektonv1.PipelineRunReasonCouldntGetPipelineResult.String():        true,
+}
+
+// failureReasonTitle returns a short description of why a PipelineRun has
+// failed from the reason of its failed condition, shown in the title of the
+// check run, i.e: "Timeout" or "task unit-tests failed". It is empty when
+// there is no reason.
+func failureReasonTitle(statusOpts provider.StatusOpts) string {
+	reason := statusOpts.FailureReason
+	switch {
+	case reason == "":
+		return ""
+	case reason == tektonv1.PipelineRunReasonTimedOut.String():
+		return "Timeout"
+	case reason == tektonv1.PipelineRunReasonCancelled.String(),
+		reason == tektonv1.PipelineRunReasonCancelledRunningFinally.String(),
+		reason == tektonv1.PipelineRunReasonStoppedRunningFinally.String():
+		return "Cancelled"
+	case validationFailureReasons[reason]:
+		return fmt.Sprintf("Validation error (%s)", reason)
+	case reason == tektonv1.PipelineRunReasonFailed.String():
+		switch names := formatting.FailedTaskNames(statusOpts.TaskRunStatuses); len(names) {
+		case 0:
+			return "Task failure"
+		case 1:
+			return fmt.Sprintf("task %s failed", names[0])
+		default:
+			return fmt.Sprintf("%d tasks failed", len(names))
+		}
+	}
+	return reason
+}
+
+// withFailureReason puts the reason of a failed PipelineRun in the title of
+// its check run and its message in the summary, the details stay in the
+// text.
+func withFailureReason(statusOpts provider.StatusOpts) provider.StatusOpts {
+	if statusOpts.Status != "completed" || statusOpts.Conclusion != "failure" {
+		return statusOpts
+	}
+	if reason := failureReasonTitle(statusOpts); reason != "" {
+		statusOpts.Title = fmt.Sprintf("%s: %s", statusOpts.Title, reason)
+	}
+	if message := strings.TrimSpace(statusOpts.FailureMessage); message != "" {
+		statusOpts.Summary = fmt.Sprintf("%s\n\n**Reason:** %s", statusOpts.Summary, message)
+	}
+	return statusOpts
+}
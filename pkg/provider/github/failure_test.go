@@ -0,0 +1,173 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func makeTaskRunStatuses(statuses map[string]corev1.ConditionStatus) map[string]*tektonv1.PipelineRunTaskRunStatus {
+	trStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{}
+	for name, status := range statuses {
+		trStatus["pr-"+name] = &tektonv1.PipelineRunTaskRunStatus{
+			PipelineTaskName: name,
+			Status: &tektonv1.TaskRunStatus{
+				Status: duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}}},
+			},
+		}
+	}
+	return trStatus
+}
+
+func TestFailureReasonTitle(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusOpts provider.StatusOpts
+		want       string
+	}{
+		{
+			name:       "no reason",
+			statusOpts: provider.StatusOpts{},
+			want:       "",
+		},
+		{
+			name:       "timeout",
+			statusOpts: provider.StatusOpts{FailureReason: "PipelineRunTimeout"},
+			want:       "Timeout",
+		},
+		{
+			name:       "validation",
+			statusOpts: provider.StatusOpts{FailureReason: "PipelineValidationFailed"},
+			want:       "Validation error (PipelineValidationFailed)",
+		},
+		{
+			name:       "task not found",
+			statusOpts: provider.StatusOpts{FailureReason: "CouldntGetTask"},
+			want:       "Validation error (CouldntGetTask)",
+		},
+		{
+			name: "one task failed",
+			statusOpts: provider.StatusOpts{
+				FailureReason: "Failed",
+				TaskRunStatuses: makeTaskRunStatuses(map[string]corev1.ConditionStatus{
+					"clone": corev1.ConditionTrue,
+					"unit":  corev1.ConditionFalse,
+				}),
+			},
+			want: "task unit failed",
+		},
+		{
+			name: "several tasks failed",
+			statusOpts: provider.StatusOpts{
+				FailureReason: "Failed",
+				TaskRunStatuses: makeTaskRunStatuses(map[string]corev1.ConditionStatus{
+					"lint": corev1.ConditionFalse,
+					"unit": corev1.ConditionFalse,
+				}),
+			},
+			want: "2 tasks failed",
+		},
+		{
+			name:       "task failure without the tasks",
+			statusOpts: provider.StatusOpts{FailureReason: "Failed"},
+			want:       "Task failure",
+		},
+		{
+			name:       "cancelled",
+			statusOpts: provider.StatusOpts{FailureReason: "Cancelled"},
+			want:       "Cancelled",
+		},
+		{
+			name:       "unknown reason",
+			statusOpts: provider.StatusOpts{FailureReason: "SomethingElse"},
+			want:       "SomethingElse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, failureReasonTitle(tt.statusOpts), tt.want)
+		})
+	}
+}
+
+func TestCreateStatusFailureReason(t *testing.T) {
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha", InstallationID: 1}
+	tests := []struct {
+		name           string
+		conclusion     string
+		reason         string
+		message        string
+		wantTitle      string
+		wantSummaryMsg string
+	}{
+		{
+			name:           "timeout",
+			conclusion:     "failure",
+			reason:         "PipelineRunTimeout",
+			message:        `PipelineRun "pr-abcde" failed to finish within "1h0m0s"`,
+			wantTitle:      "Failed: Timeout",
+			wantSummaryMsg: `**Reason:** PipelineRun "pr-abcde" failed to finish within "1h0m0s"`,
+		},
+		{
+			name:           "validation",
+			conclusion:     "failure",
+			reason:         "PipelineValidationFailed",
+			message:        "invalid workspace binding",
+			wantTitle:      "Failed: Validation error (PipelineValidationFailed)",
+			wantSummaryMsg: "**Reason:** invalid workspace binding",
+		},
+		{
+			name:           "task failure",
+			conclusion:     "failure",
+			reason:         "Failed",
+			message:        "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 0",
+			wantTitle:      "Failed: task unit failed",
+			wantSummaryMsg: "**Reason:** Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 0",
+		},
+		{
+			name:       "success",
+			conclusion: "success",
+			reason:     "Succeeded",
+			message:    "Tasks Completed: 2 (Failed: 0, Cancelled 0), Skipped: 0",
+			wantTitle:  "Success",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			v, checks, _, _ := newFakeProvider()
+
+			assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+				Status:                  "completed",
+				Conclusion:              tt.conclusion,
+				PipelineRunName:         "pr-abcde",
+				OriginalPipelineRunName: "pr",
+				Text:                    "the task table",
+				FailureReason:           tt.reason,
+				FailureMessage:          tt.message,
+				TaskRunStatuses: makeTaskRunStatuses(map[string]corev1.ConditionStatus{
+					"clone": corev1.ConditionTrue,
+					"unit":  corev1.ConditionFalse,
+				}),
+			}))
+			assert.Equal(t, len(checks.CheckRuns), 1)
+			updates := checks.Updates[checks.CheckRuns[0].GetID()]
+			output := updates[len(updates)-1].Output
+			assert.Equal(t, output.GetTitle(), tt.wantTitle)
+			assert.Equal(t, output.GetText(), "the task table")
+			if tt.wantSummaryMsg != "" {
+				assert.Assert(t, strings.Contains(output.GetSummary(), tt.wantSummaryMsg), output.GetSummary())
+			} else {
+				assert.Assert(t, !strings.Contains(output.GetSummary(), "**Reason:**"), output.GetSummary())
+			}
+		})
+	}
+}
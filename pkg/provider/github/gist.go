@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// matchGistRepositories returns true when the "owner/repo" of the event is
+// matched by one of the comma separated globs.
+func matchGistRepositories(runevent *info.Event, repositories string) bool {
+	return provider.MatchGlobs(runevent.Organization+"/"+runevent.Repository, strings.Split(repositories, ","))
+}
+
+// gistAllowed returns true when the summary of the statuses of the repository
+// can be published as a gist: a secret gist can be read by anyone with its URL
+// so only the public repositories and the allowed ones are.
+// The visibility of the repository is cached.
+func (v *Provider) gistAllowed(ctx context.Context, runevent *info.Event) bool {
+	if matchGistRepositories(runevent, v.Run.Info.Pac.StatusGistRepositories) {
+		return true
+	}
+	fullName := runevent.Organization + "/" + runevent.Repository
+	apiURL := ""
+	if v.APIURL != nil {
+		apiURL = *v.APIURL
+	}
+	if private, ok := v.repoVisibilities().Get(apiURL, fullName); ok {
+		return !private
+	}
+	start := time.Now()
+	repo, _, err := v.repositories().Get(ctx, runevent.Organization, runevent.Repository)
+	v.recordAPILatency("GetRepository", time.Since(start))
+	if err != nil {
+		v.Logger.Warnf("cannot get the visibility of %s, not publishing the status as a gist: %v", fullName, err)
+		return false
+	}
+	v.repoVisibilities().Set(apiURL, fullName, repo.GetPrivate())
+	return !repo.GetPrivate()
+}
+
+// makeStatusGistContent renders the summary of a status with the task table
+// and a link to the logs.
+func makeStatusGistContent(checkName string, status provider.StatusOpts) string {
+	content := fmt.Sprintf("# %s: %s\n\n%s\n", checkName, status.Title, status.Summary)
+	if status.DetailsURL != "" {
+		content += fmt.Sprintf("\n[Logs](%s)\n", status.DetailsURL)
+	}
+	return content + "\n" + status.Text + "\n"
+}
+
+// createStatusGist publishes the summary of a completed status as a secret
+// gist and returns its URL. The gist of a PipelineRun is recorded on it and
+// edited on its next statuses rather than creating a new one. It is best
+// effort: on failure it is logged and an empty URL is returned, the commit
+// status links to the details URL then.
+func (v *Provider) createStatusGist(ctx context.Context, runevent *info.Event, status provider.StatusOpts, checkName string) string {
+	gists := v.gists()
+	if gists == nil || !v.gistAllowed(ctx, runevent) {
+		return ""
+	}
+	filename := status.PipelineRunName
+	if filename == "" {
+		filename = "status"
+	}
+	gist := &github.Gist{
+		Description: github.String(fmt.Sprintf("%s of %s/%s@%s", checkName, runevent.Organization, runevent.Repository, runevent.SHA)),
+		Public:      github.Bool(false),
+		Files: map[github.GistFilename]github.GistFile{
+			github.GistFilename(filename + ".md"): {Content: github.String(makeStatusGistContent(checkName, status))},
+		},
+	}
+	if status.PipelineRun != nil {
+		if id := status.PipelineRun.GetAnnotations()[keys.StatusGistID]; id != "" {
+			start := time.Now()
+			edited, _, err := gists.Edit(ctx, id, gist)
+			v.recordAPILatency("EditGist", time.Since(start))
+			if err == nil {
+				return edited.GetHTMLURL()
+			}
+			v.Logger.Warnf("cannot edit the gist %s of the status of %s, publishing a new one: %v", id, status.PipelineRunName, err)
+		}
+	}
+	start := time.Now()
+	created, _, err := gists.Create(ctx, gist)
+	v.recordAPILatency("CreateGist", time.Since(start))
+	if err != nil {
+		v.Logger.Warnf("cannot publish the status of %s as a gist, linking to the details instead: %v", status.PipelineRunName, err)
+		return ""
+	}
+	if status.PipelineRun != nil {
+		patch := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{keys.StatusGistID: created.GetID()},
+			},
+		}
+		if _, err := action.PatchPipelineRun(ctx, v.Logger, "status gist id", v.Run.Clients.Tekton, status.PipelineRun, patch); err != nil {
+			v.Logger.Warnf("cannot record the gist %s on %s, the next status publishes a new one: %v", created.GetID(), status.PipelineRunName, err)
+		}
+	}
+	return created.GetHTMLURL()
+}
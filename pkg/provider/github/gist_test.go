@@ -0,0 +1,181 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	ghtesting "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/testing"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+var _ GistsAPI = (*ghtesting.Gists)(nil)
+
+func TestMatchGistRepositories(t *testing.T) {
+	event := &info.Event{Organization: "owner", Repository: "repo"}
+	assert.Assert(t, !matchGistRepositories(event, ""))
+	assert.Assert(t, matchGistRepositories(event, "owner/repo"))
+	assert.Assert(t, matchGistRepositories(event, "other/*, owner/*"))
+	assert.Assert(t, !matchGistRepositories(event, "other/*,owner/rep"))
+}
+
+func TestCreateStatusGist(t *testing.T) {
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+	tests := []struct {
+		name       string
+		statusGist bool
+		private    bool
+		allowed    string
+		status     string
+		gistErr    error
+		wantGist   bool
+		wantTarget string
+	}{
+		{
+			name:       "disabled",
+			status:     "completed",
+			wantTarget: "https://console/pr-abcde",
+		},
+		{
+			name:       "public repository",
+			statusGist: true,
+			status:     "completed",
+			wantGist:   true,
+			wantTarget: "https://gist.github.com/1",
+		},
+		{
+			name:       "private repository",
+			statusGist: true,
+			private:    true,
+			status:     "completed",
+			wantTarget: "https://console/pr-abcde",
+		},
+		{
+			name:       "allowed private repository",
+			statusGist: true,
+			private:    true,
+			allowed:    "owner/*",
+			status:     "completed",
+			wantGist:   true,
+			wantTarget: "https://gist.github.com/1",
+		},
+		{
+			name:       "in progress",
+			statusGist: true,
+			status:     "in_progress",
+			wantTarget: "https://console/pr-abcde",
+		},
+		{
+			name:       "gist error",
+			statusGist: true,
+			status:     "completed",
+			gistErr:    fmt.Errorf("403 Forbidden"),
+			wantTarget: "https://console/pr-abcde",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			v, _, repositories, _ := newFakeProvider()
+			gists := &ghtesting.Gists{Err: tt.gistErr}
+			v.Gists = gists
+			v.repoVisibilityCache = NewRepoVisibilityCache(clockwork.NewFakeClock())
+			repositories.PrivateRepositories = map[string]bool{"owner/repo": tt.private}
+			v.Run.Info.Pac.StatusGist = tt.statusGist
+			v.Run.Info.Pac.StatusGistRepositories = tt.allowed
+
+			assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+				Status:                  tt.status,
+				Conclusion:              "failure",
+				PipelineRunName:         "pr-abcde",
+				OriginalPipelineRunName: "pr",
+				DetailsURL:              "https://console/pr-abcde",
+				Text:                    "<table><tr><td>unit</td><td>failed</td></tr></table>",
+			}))
+			assert.Equal(t, len(repositories.Statuses), 1)
+			assert.Equal(t, repositories.Statuses[0].GetTargetURL(), tt.wantTarget)
+			assert.Equal(t, repositories.Statuses[0].GetState(), map[string]string{"completed": "failure", "in_progress": "pending"}[tt.status])
+			if !tt.wantGist {
+				assert.Equal(t, len(gists.Gists), 0)
+				return
+			}
+			assert.Equal(t, len(gists.Gists), 1)
+			gist := gists.Gists[0]
+			assert.Assert(t, !gist.GetPublic(), "the gist should be secret")
+			assert.Equal(t, gist.GetDescription(), "Pipelines as Code CI / pr of owner/repo@sha")
+			file := gist.Files["pr-abcde.md"]
+			content := file.GetContent()
+			assert.Assert(t, strings.HasPrefix(content, "# Pipelines as Code CI / pr: Failed\n"), content)
+			assert.Assert(t, strings.Contains(content, "[Logs](https://console/pr-abcde)"), content)
+			assert.Assert(t, strings.Contains(content, "<td>unit</td>"), content)
+		})
+	}
+}
+
+func TestStatusGistReused(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-abcde", Namespace: "ns"}}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*tektonv1.PipelineRun{pr}})
+	v, _, repositories, _ := newFakeProvider()
+	v.Run.Clients = clients.Clients{Tekton: stdata.Pipeline}
+	gists := &ghtesting.Gists{}
+	v.Gists = gists
+	v.repoVisibilityCache = NewRepoVisibilityCache(clockwork.NewFakeClock())
+	v.Run.Info.Pac.StatusGist = true
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+	status := provider.StatusOpts{
+		Status:          "completed",
+		Conclusion:      "failure",
+		PipelineRunName: "pr-abcde",
+		PipelineRun:     pr,
+		Text:            "first",
+	}
+
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+	assert.Equal(t, len(gists.Gists), 1)
+	patched, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, "pr-abcde", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, patched.GetAnnotations()[keys.StatusGistID], "1")
+
+	// the next status of the PipelineRun edits its gist
+	status.PipelineRun = patched
+	status.Text = "second"
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+	assert.Equal(t, len(gists.Gists), 1)
+	assert.Equal(t, gists.Edits, 1)
+	file := gists.Gists[0].Files["pr-abcde.md"]
+	assert.Assert(t, strings.Contains(file.GetContent(), "second"))
+	assert.Equal(t, repositories.Statuses[1].GetTargetURL(), "https://gist.github.com/1")
+
+	// a gist which has been deleted is published again
+	patched.Annotations[keys.StatusGistID] = "42"
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+	assert.Equal(t, len(gists.Gists), 2)
+	assert.Equal(t, repositories.Statuses[2].GetTargetURL(), "https://gist.github.com/2")
+}
+
+func TestGistAllowedCachesVisibility(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	clock := clockwork.NewFakeClock()
+	v, _, repositories, _ := newFakeProvider()
+	v.repoVisibilityCache = NewRepoVisibilityCache(clock)
+	event := &info.Event{Organization: "owner", Repository: "repo"}
+
+	assert.Assert(t, v.gistAllowed(ctx, event))
+
+	// the visibility isn't looked up again until it expires
+	repositories.PrivateRepositories = map[string]bool{"owner/repo": true}
+	assert.Assert(t, v.gistAllowed(ctx, event))
+	clock.Advance(repoVisibilityTTL + time.Second)
+	assert.Assert(t, !v.gistAllowed(ctx, event))
+}
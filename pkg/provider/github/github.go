@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -16,6 +17,8 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
@@ -31,11 +34,18 @@ const (
 	// TODO: makes this configurable for GHE in the ConfigMap.
 	// on our GHE instance, it looks like this :
 	// https://raw.ghe.openshiftpipelines.com/pac/chmouel-test/main/README.md
-	// we can perhaps do some autodetection with event.Provider.GHEURL and adding
+	// we can perhaps do some autodetection with event.GHEHost and adding
 	// a raw into it.
 	publicRawURLHost = "raw.githubusercontent.com"
 
 	defaultPaginedNumber = 100
+
+	// maxTextSize is the maximum size of a check run output text or summary
+	// or an issue comment body as documented in the GitHub API.
+	maxTextSize = 65535
+	// maxTitleLength is the number of characters GitHub accepts in the title
+	// of a check run output.
+	maxTitleLength = 255
 )
 
 var _ provider.Interface = (*Provider)(nil)
@@ -52,6 +62,40 @@ type Provider struct {
 	repo          *v1alpha1.Repository
 	eventEmitter  *events.EventEmitter
 	paginedNumber int
+	// tokenExpiresAt is when the installation token generated from the
+	// GitHub App expires.
+	tokenExpiresAt time.Time
+	// postedStatuses overrides the cache of the idempotency tokens of the
+	// statuses already posted.
+	postedStatuses *provider.IdempotencyCache
+	// rateLimiters overrides the rate limiters shared by the requests to
+	// GitHub.
+	rateLimiters *RateLimiters
+	// rateLimiter is the rate limiter of the host and installation of the
+	// client.
+	rateLimiter *RateLimiter
+	// appKeyCache overrides the cache of the private keys of the GitHub App
+	// shared by the providers.
+	appKeyCache *AppKeyCache
+	// repoVisibilityCache overrides the cache of the visibility of the
+	// repositories shared by the providers.
+	repoVisibilityCache *RepoVisibilityCache
+	// kinteract is the kubernetes interaction getting the logs of the failed
+	// tasks and the secrets to hide from them, built once with the client.
+	kinteract kubeinteraction.Interface
+	// Metrics records the latency of the calls to the GitHub API reporting
+	// the statuses when set.
+	Metrics *metrics.Recorder
+	// auditWriter overrides the logger receiving the JSON line of every
+	// status posted with the audit-statuses setting.
+	auditWriter io.Writer
+	// Checks, Repositories, Issues and Gists override the services of the
+	// Client used to report the statuses when set, i.e: with the fakes of the
+	// testing package.
+	Checks       ChecksAPI
+	Repositories RepositoriesAPI
+	Issues       IssuesAPI
+	Gists        GistsAPI
 	skippedRun
 }
 
@@ -72,12 +116,7 @@ func New() *Provider {
 
 // detectGHERawURL Detect if we have a raw URL in GHE.
 func detectGHERawURL(event *info.Event, taskHost string) bool {
-	gheURL, err := url.Parse(event.GHEURL)
-	if err != nil {
-		// should not happen but may as well make sure
-		return false
-	}
-	return taskHost == fmt.Sprintf("raw.%s", gheURL.Host)
+	return event.GHEHost != "" && taskHost == fmt.Sprintf("raw.%s", event.GHEHost)
 }
 
 // splitGithubURL Take a Github url and split it with org/repo path ref, supports rawURL.
@@ -149,7 +188,7 @@ func (v *Provider) InitAppClient(ctx context.Context, kube kubernetes.Interface,
 	var err error
 	// TODO: move this out of here when we move al config inside context
 	ns := info.GetNS(ctx)
-	event.Provider.Token, err = v.GetAppToken(ctx, kube, event.GHEURL, event.InstallationID, ns)
+	event.Provider.Token, err = v.GetAppToken(ctx, kube, event.GHEHost, event.InstallationID, ns)
 	if err != nil {
 		return err
 	}
@@ -182,16 +221,19 @@ func (v *Provider) GetConfig() *info.ProviderConfig {
 		TaskStatusTMPL: taskStatusTemplate,
 		APIURL:         apiPublicURL,
 		Name:           v.providerName,
+		// no MaxTextSize, the text is truncated by the provider at a row of
+		// the task status table with a link to the full logs.
 	}
 }
 
-func makeClient(ctx context.Context, apiURL, token string) (*github.Client, string, *string) {
+func makeClient(ctx context.Context, limiter *RateLimiter, apiURL, token string) (*github.Client, string, *string) {
 	var client *github.Client
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 
 	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = limiter.Transport(tc.Transport)
 	if apiURL != "" {
 		if !strings.HasPrefix(apiURL, "https") && !strings.HasPrefix(apiURL, "http") {
 			apiURL = "https://" + apiURL
@@ -265,11 +307,18 @@ func (v *Provider) checkWebhookSecretValidity(ctx context.Context, cw clockwork.
 }
 
 func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.Event, repo *v1alpha1.Repository, eventsEmitter *events.EventEmitter) error {
-	client, providerName, apiURL := makeClient(ctx, event.Provider.URL, event.Provider.Token)
+	client, providerName, apiURL := makeClient(ctx, v.limiter(event.Provider.URL, event.InstallationID), event.Provider.URL, event.Provider.Token)
 	v.providerName = providerName
 	v.Run = run
 	v.repo = repo
 	v.eventEmitter = eventsEmitter
+	if v.kinteract == nil {
+		kinteract, err := kubeinteraction.NewKubernetesInteraction(run)
+		if err != nil {
+			return fmt.Errorf("cannot create the kubernetes interaction: %w", err)
+		}
+		v.kinteract = kinteract
+	}
 
 	// check that the Client is not already set, so we don't override our fakeclient
 	// from unittesting.
@@ -279,6 +328,14 @@ func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.E
 
 	v.APIURL = apiURL
 
+	if repo != nil && repo.Spec.Settings != nil && repo.Spec.Settings.ApplicationName != "" {
+		event.ApplicationName = repo.Spec.Settings.ApplicationName
+	}
+
+	if err := v.resolveCheckRunTarget(ctx, event); err != nil {
+		return err
+	}
+
 	if event.Provider.WebhookSecretFromRepo {
 		// check the webhook secret is valid and not ratelimited
 		if err := v.checkWebhookSecretValidity(ctx, clockwork.NewRealClock()); err != nil {
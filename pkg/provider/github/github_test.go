@@ -108,7 +108,7 @@ func TestGithubSplitURL(t *testing.T) {
 		wantRepo string
 		wantRef  string
 		wantPath string
-		gheURL   string
+		gheHost  string
 		wantErr  bool
 	}{
 		{
@@ -172,7 +172,7 @@ func TestGithubSplitURL(t *testing.T) {
 		{
 			name:     "raw GHE URL",
 			url:      "https://raw.ghe.domain.com/owner/repo/branch/file?token=TOKEN",
-			gheURL:   "https://ghe.domain.com",
+			gheHost:  "ghe.domain.com",
 			wantOrg:  "owner",
 			wantRepo: "repo",
 			wantRef:  "branch",
@@ -181,7 +181,7 @@ func TestGithubSplitURL(t *testing.T) {
 		{
 			name:     "not matching ghe but allowed from public gh",
 			url:      fmt.Sprintf("https://%s/owner/repo/branch/file?token=TOKEN", publicRawURLHost),
-			gheURL:   "https://foo.com",
+			gheHost:  "foo.com",
 			wantOrg:  "owner",
 			wantRepo: "repo",
 			wantRef:  "branch",
@@ -190,26 +190,20 @@ func TestGithubSplitURL(t *testing.T) {
 		{
 			name:    "not matching raw",
 			url:     "https://bar.com/owner/repo/branch/file?token=TOKEN",
-			gheURL:  "https://foo.com",
+			gheHost: "foo.com",
 			wantErr: true,
 		},
 		{
 			name:    "not a full direct url",
 			url:     "https://raw.ghe/owner/repo/branch?token=TOKEN",
-			gheURL:  "https://raw.ghe",
-			wantErr: true,
-		},
-		{
-			name:    "bad formatted ghe url",
-			url:     "https://raw.ghe/owner/repo/branch?token=TOKEN",
-			gheURL:  "https:raw.ghe",
+			gheHost: "raw.ghe",
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			event := info.NewEvent()
-			event.GHEURL = tt.gheURL
+			event.GHEHost = tt.gheHost
 			org, repo, path, ref, err := splitGithubURL(event, tt.url)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SplitURL() error = %v, wantErr %v", err, tt.wantErr)
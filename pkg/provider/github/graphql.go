@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// checkRunsQuery gets the check runs of the GitHub App on a commit in a
+// single request, where the REST API needs one request per page.
+const checkRunsQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!, $appId: Int) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) {
+      ... on Commit {
+        checkSuites(first: 10, filterBy: {appId: $appId}) {
+          pageInfo { hasNextPage }
+          nodes {
+            checkRuns(first: 100) {
+              pageInfo { hasNextPage }
+              nodes { databaseId externalId status title summary }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+type graphqlPageInfo struct {
+	HasNextPage bool `json:"hasNextPage"`
+}
+
+type graphqlCheckRun struct {
+	DatabaseID int64  `json:"databaseId"`
+	ExternalID string `json:"externalId"`
+	Status     string `json:"status"`
+	Title      string `json:"title"`
+	Summary    string `json:"summary"`
+}
+
+type checkRunsQueryResponse struct {
+	Data struct {
+		Repository *struct {
+			Object *struct {
+				CheckSuites struct {
+					PageInfo graphqlPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						CheckRuns struct {
+							PageInfo graphqlPageInfo   `json:"pageInfo"`
+							Nodes    []graphqlCheckRun `json:"nodes"`
+						} `json:"checkRuns"`
+					} `json:"nodes"`
+				} `json:"checkSuites"`
+			} `json:"object"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphqlError `json:"errors"`
+}
+
+// graphqlURL returns the GraphQL endpoint of a GitHub API, the one of GitHub
+// Enterprise is /api/graphql next to the /api/v3 of the REST API.
+func graphqlURL(baseURL *url.URL) string {
+	u := *baseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/graphql"
+	}
+	return u.String()
+}
+
+// useGraphQL returns whether the existing check runs are looked up with
+// GraphQL, it needs the client the GraphQL requests are sent with.
+func (v *Provider) useGraphQL() bool {
+	return v.Client != nil && v.Run != nil && v.Run.Info.Pac != nil && v.Run.Info.Pac.UseGraphQL
+}
+
+// graphqlCheckRuns gets the check runs of our GitHub App on the commit of the
+// event with a single GraphQL query, it fails when the GraphQL API is not
+// available or when the check runs don't fit in a single response so the
+// REST API can be used instead.
+func (v *Provider) graphqlCheckRuns(ctx context.Context, runevent *info.Event) ([]*github.CheckRun, error) {
+	variables := map[string]any{
+		"owner": runevent.Organization,
+		"name":  runevent.Repository,
+		"oid":   runevent.SHA,
+		"appId": v.ApplicationID,
+	}
+	req, err := v.Client.NewRequest(http.MethodPost, graphqlURL(v.Client.BaseURL),
+		&graphqlRequest{Query: checkRunsQuery, Variables: variables})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &checkRunsQueryResponse{}
+	start := time.Now()
+	_, err = v.Client.Do(ctx, req, response)
+	v.recordAPILatency("GraphQLCheckRuns", time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Errors) > 0 {
+		messages := make([]string, 0, len(response.Errors))
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, fmt.Errorf("graphql query of the check runs failed: %s", strings.Join(messages, ", "))
+	}
+	if response.Data.Repository == nil || response.Data.Repository.Object == nil {
+		return nil, fmt.Errorf("graphql query of the check runs: commit %s not found in %s/%s",
+			runevent.SHA, runevent.Organization, runevent.Repository)
+	}
+
+	suites := response.Data.Repository.Object.CheckSuites
+	if suites.PageInfo.HasNextPage {
+		return nil, fmt.Errorf("graphql query of the check runs: too many check suites on commit %s", runevent.SHA)
+	}
+	checkruns := []*github.CheckRun{}
+	for _, suite := range suites.Nodes {
+		if suite.CheckRuns.PageInfo.HasNextPage {
+			return nil, fmt.Errorf("graphql query of the check runs: too many check runs on commit %s", runevent.SHA)
+		}
+		for _, run := range suite.CheckRuns.Nodes {
+			checkruns = append(checkruns, &github.CheckRun{
+				ID:         github.Int64(run.DatabaseID),
+				ExternalID: github.String(run.ExternalID),
+				// the GraphQL enums are upper case, the REST ones lower case
+				Status: github.String(strings.ToLower(run.Status)),
+				Output: &github.CheckRunOutput{
+					Title:   github.String(run.Title),
+					Summary: github.String(run.Summary),
+				},
+			})
+		}
+	}
+	return checkruns, nil
+}
@@ -0,0 +1,180 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGraphqlURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{baseURL: "https://api.github.com/", want: "https://api.github.com/graphql"},
+		{baseURL: "https://ghe.example.com/api/v3/", want: "https://ghe.example.com/api/graphql"},
+		{baseURL: "https://ghe.example.com/prefix/", want: "https://ghe.example.com/prefix/graphql"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			baseURL, err := url.Parse(tt.baseURL)
+			assert.NilError(t, err)
+			assert.Equal(t, graphqlURL(baseURL), tt.want)
+		})
+	}
+}
+
+func TestGetExistingCheckRunIDGraphQL(t *testing.T) {
+	// the check run of the PipelineRun is the last of 250 check runs, three
+	// pages of the REST API
+	const numberOfCheckRuns = 250
+	checkRunsJSON := func(from, to int) []map[string]any {
+		runs := []map[string]any{}
+		for i := from; i < to; i++ {
+			externalID := fmt.Sprintf("pr-%d", i)
+			if i == numberOfCheckRuns-1 {
+				externalID = "pr-abcde"
+			}
+			runs = append(runs, map[string]any{
+				"id": 1000 + i, "databaseId": 1000 + i, "external_id": externalID, "externalId": externalID,
+				"status": "COMPLETED", "title": "Success", "summary": "done",
+			})
+		}
+		return runs
+	}
+
+	tests := []struct {
+		name            string
+		useGraphQL      bool
+		graphqlStatus   int
+		graphqlResponse func() map[string]any
+		wantGraphQL     int
+		wantREST        int
+	}{
+		{
+			name:     "rest",
+			wantREST: 3,
+		},
+		{
+			name:       "graphql",
+			useGraphQL: true,
+			graphqlResponse: func() map[string]any {
+				return map[string]any{"data": map[string]any{"repository": map[string]any{"object": map[string]any{
+					"checkSuites": map[string]any{
+						"pageInfo": map[string]any{"hasNextPage": false},
+						"nodes": []map[string]any{{"checkRuns": map[string]any{
+							"pageInfo": map[string]any{"hasNextPage": false},
+							"nodes":    checkRunsJSON(0, numberOfCheckRuns),
+						}}},
+					},
+				}}}}
+			},
+			wantGraphQL: 1,
+		},
+		{
+			name:          "graphql not available",
+			useGraphQL:    true,
+			graphqlStatus: http.StatusNotFound,
+			wantGraphQL:   1,
+			wantREST:      3,
+		},
+		{
+			name:       "graphql errors",
+			useGraphQL: true,
+			graphqlResponse: func() map[string]any {
+				return map[string]any{"errors": []map[string]any{{"message": "Field 'checkSuites' doesn't exist"}}}
+			},
+			wantGraphQL: 1,
+			wantREST:    3,
+		},
+		{
+			name:       "graphql with too many check runs",
+			useGraphQL: true,
+			graphqlResponse: func() map[string]any {
+				return map[string]any{"data": map[string]any{"repository": map[string]any{"object": map[string]any{
+					"checkSuites": map[string]any{
+						"pageInfo": map[string]any{"hasNextPage": false},
+						"nodes": []map[string]any{{"checkRuns": map[string]any{
+							"pageInfo": map[string]any{"hasNextPage": true},
+							"nodes":    checkRunsJSON(0, 100),
+						}}},
+					},
+				}}}}
+			},
+			wantGraphQL: 1,
+			wantREST:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			graphqlRequests, restRequests := 0, 0
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+				graphqlRequests++
+				assert.Equal(t, r.Method, http.MethodPost)
+				request := &graphqlRequest{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(request))
+				// the check suites have to be filtered on our application
+				assert.Equal(t, request.Variables["appId"], float64(4242))
+				assert.Equal(t, request.Variables["oid"], "sha")
+				if tt.graphqlStatus != 0 {
+					w.WriteHeader(tt.graphqlStatus)
+					return
+				}
+				assert.NilError(t, json.NewEncoder(w).Encode(tt.graphqlResponse()))
+			})
+			mux.HandleFunc("/api/v3/repos/owner/repo/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				restRequests++
+				assert.Equal(t, r.URL.Query().Get("app_id"), "4242")
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				if page == 0 {
+					page = 1
+				}
+				from, to := (page-1)*100, page*100
+				if to >= numberOfCheckRuns {
+					to = numberOfCheckRuns
+				} else {
+					w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, server.URL, r.URL.Path, page+1))
+				}
+				assert.NilError(t, json.NewEncoder(w).Encode(map[string]any{
+					"total_count": numberOfCheckRuns,
+					"check_runs":  checkRunsJSON(from, to),
+				}))
+			})
+
+			client := github.NewClient(nil)
+			client.BaseURL, _ = url.Parse(server.URL + "/api/v3/")
+			v := &Provider{
+				Client:        client,
+				ApplicationID: github.Int64(4242),
+				paginedNumber: 100,
+				Run:           params.New(),
+			}
+			v.Run.Info.Pac = &info.PacOpts{Settings: &settings.Settings{UseGraphQL: tt.useGraphQL}}
+			event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+
+			id, err := v.getExistingCheckRunID(ctx, event, provider.StatusOpts{PipelineRunName: "pr-abcde"})
+			assert.NilError(t, err)
+			assert.Assert(t, id != nil)
+			assert.Equal(t, *id, int64(1000+numberOfCheckRuns-1))
+			assert.Equal(t, graphqlRequests, tt.wantGraphQL)
+			assert.Equal(t, restRequests, tt.wantREST)
+		})
+	}
+}
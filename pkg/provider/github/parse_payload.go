@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
 	oGitHub "github.com/google/go-github/v57/github"
@@ -29,20 +31,54 @@ import (
 // It takes a context, namespace, and Kubernetes client as input parameters.
 // It returns the application ID (int64), private key ([]byte), and an error if any.
 func (v *Provider) GetAppIDAndPrivateKey(ctx context.Context, ns string, kube kubernetes.Interface) (int64, []byte, error) {
+	key, err := v.loadAppKey(ctx, ns, kube)
+	if err != nil {
+		return 0, []byte{}, err
+	}
+	return key.applicationID, key.privateKey, nil
+}
+
+// GetAppIDAndRSAPrivateKey is like GetAppIDAndPrivateKey but returns the
+// parsed private key to sign with.
+func (v *Provider) GetAppIDAndRSAPrivateKey(ctx context.Context, ns string, kube kubernetes.Interface) (int64, *rsa.PrivateKey, error) {
+	key, err := v.loadAppKey(ctx, ns, kube)
+	if err != nil {
+		return 0, nil, err
+	}
+	return key.applicationID, key.parsed, nil
+}
+
+// InvalidateAppKey forgets the private key loaded from the secret of the
+// namespace, it's parsed again on the next load.
+func (v *Provider) InvalidateAppKey(ns string) {
+	v.appKeys().Invalidate(ns + "/" + v.Run.Info.Controller.Secret)
+}
+
+// loadAppKey reads the secret of the GitHub App every time so a rotated
+// private key is picked up without restarting the controller, the private
+// key is validated when it has changed.
+func (v *Provider) loadAppKey(ctx context.Context, ns string, kube kubernetes.Interface) (*appKey, error) {
 	paramsinfo := &v.Run.Info
 	secret, err := kube.CoreV1().Secrets(ns).Get(ctx, paramsinfo.Controller.Secret, v1.GetOptions{})
 	if err != nil {
-		return 0, []byte{}, fmt.Errorf("could not get the secret %s in ns %s: %w", paramsinfo.Controller.Secret, ns, err)
+		return nil, fmt.Errorf("could not get the secret %s in ns %s: %w", paramsinfo.Controller.Secret, ns, err)
 	}
 
 	appID := secret.Data[keys.GithubApplicationID]
 	applicationID, err := strconv.ParseInt(strings.TrimSpace(string(appID)), 10, 64)
 	if err != nil {
-		return 0, []byte{}, fmt.Errorf("could not parse the github application_id number from secret: %w", err)
+		return nil, fmt.Errorf("could not parse the github application_id number from secret: %w", err)
 	}
 
-	privateKey := secret.Data[keys.GithubPrivateKey]
-	return applicationID, privateKey, nil
+	secretRef := ns + "/" + paramsinfo.Controller.Secret
+	key, rotated, err := v.appKeys().load(secretRef, applicationID, secret.Data[keys.GithubPrivateKey])
+	if err != nil {
+		return nil, err
+	}
+	if rotated && v.Logger != nil {
+		v.Logger.Infof("the github application id or private key of secret %s has changed, using the new one", secretRef)
+	}
+	return key, nil
 }
 
 func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, gheURL string, installationID int64, ns string) (string, error) {
@@ -51,7 +87,15 @@ func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, g
 		return "", err
 	}
 	v.ApplicationID = &applicationID
-	tr := http.DefaultTransport
+	var pacOpts *info.PacOpts
+	if v.Run != nil {
+		pacOpts = v.Run.Info.Pac
+	}
+	tr, err := NewTransport(pacOpts)
+	if err != nil {
+		return "", err
+	}
+	tr = AppHeadersTransport(v.limiter(gheURL, installationID).Transport(tr))
 
 	itr, err := ghinstallation.New(tr, applicationID, installationID, privateKey)
 	if err != nil {
@@ -84,13 +128,40 @@ func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, g
 	// Get a token ASAP because we need it for setting private repos
 	token, err := itr.Token(ctx)
 	if err != nil {
+		return "", checkClockSkew(err, time.Now())
+	}
+	granted, err := itr.Permissions()
+	if err != nil {
+		return "", err
+	}
+	if err := checkRequiredPermissions(granted); err != nil {
 		return "", err
 	}
 	v.Token = github.String(token)
+	if expiresAt, _, err := itr.Expiry(); err == nil {
+		v.tokenExpiresAt = expiresAt
+	}
 
 	return token, err
 }
 
+// TokenExpiresAt returns when the installation token generated by GetAppToken
+// expires.
+func (v *Provider) TokenExpiresAt() time.Time {
+	return v.tokenExpiresAt
+}
+
+// InitClientFromToken initialize the client from an installation token of
+// installationID we already have instead of generating a new one.
+func (v *Provider) InitClientFromToken(ctx context.Context, gheURL string, installationID int64, token string) {
+	// same hack as in GetAppToken for the unittests
+	if reqTokenURL := os.Getenv("PAC_GIT_PROVIDER_TOKEN_APIURL"); reqTokenURL != "" {
+		gheURL = reqTokenURL
+	}
+	v.Client, v.providerName, v.APIURL = makeClient(ctx, v.limiter(gheURL, installationID), gheURL, token)
+	v.Token = github.String(token)
+}
+
 func (v *Provider) parseEventType(request *http.Request, event *info.Event) error {
 	event.EventType = request.Header.Get("X-GitHub-Event")
 	if event.EventType == "" {
@@ -181,7 +252,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 
 	processedEvent.Event = eventInt
 	processedEvent.InstallationID = installationIDFrompayload
-	processedEvent.GHEURL = event.Provider.URL
+	processedEvent.GHEHost = event.Provider.URL
 	processedEvent.Provider.URL = event.Provider.URL
 
 	// regenerate token scoped to the repo IDs
@@ -222,10 +293,16 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 			return nil, fmt.Errorf("check run rerequest is only supported with github apps integration")
 		}
 
+		if isRerunRequestedAction(gitEvent) {
+			return v.handleReRunActionEvent(ctx, gitEvent)
+		}
+		if isCancelRequestedAction(gitEvent) {
+			return v.handleCancelActionEvent(ctx, gitEvent)
+		}
 		if *gitEvent.Action != "rerequested" {
 			return nil, fmt.Errorf("only issue recheck is supported in checkrunevent")
 		}
-		return v.handleReRequestEvent(ctx, gitEvent)
+		return v.handleCheckRunRerequested(ctx, gitEvent)
 	case *github.CheckSuiteEvent:
 		if v.Client == nil {
 			return nil, fmt.Errorf("check suite rerequest is only supported with github apps integration")
@@ -284,11 +361,16 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		processedEvent.EventType = event.EventType
 		processedEvent.PullRequestNumber = gitEvent.GetPullRequest().GetNumber()
 		processedEvent.PullRequestTitle = gitEvent.GetPullRequest().GetTitle()
+		processedEvent.PullRequestDraft = gitEvent.GetPullRequest().GetDraft()
+		processedEvent.PreviousSHA = gitEvent.GetBefore()
 		// getting the repository ids of the base and head of the pull request
 		// to scope the token to
 		v.RepositoryIDs = []int64{
 			gitEvent.GetPullRequest().GetBase().GetRepo().GetID(),
 		}
+	case *github.InstallationRepositoriesEvent:
+		processedEvent.EventType = event.EventType
+		processedEvent.Sender = gitEvent.GetSender().GetLogin()
 	default:
 		return nil, errors.New("this event is not supported")
 	}
@@ -324,6 +406,76 @@ func (v *Provider) handleReRequestEvent(ctx context.Context, event *github.Check
 	return v.getPullRequest(ctx, runevent)
 }
 
+// handleCheckRunRerequested handles a click on the native GitHub Re-run of a
+// check run, only its PipelineRun is re-run when the check run is one of ours
+// or all of them when it is the aggregated check run.
+func (v *Provider) handleCheckRunRerequested(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
+	checkRun := event.GetCheckRun()
+	if v.ApplicationID != nil && checkRun.GetApp().GetID() != *v.ApplicationID {
+		return nil, fmt.Errorf("check run %d has not been created by the github application %d, not re-running it",
+			checkRun.GetID(), *v.ApplicationID)
+	}
+	runevent, err := v.handleReRequestEvent(ctx, event)
+	if err != nil {
+		return runevent, err
+	}
+	externalID := checkRun.GetExternalID()
+	if externalID == "" || externalID == aggregatedCheckRunExternalID {
+		return runevent, nil
+	}
+	runevent.CheckRunExternalID = externalID
+	v.Logger.Infof("Re-run of the PipelineRun of check run %s on %s/%s has been requested from its check run", externalID, runevent.Organization, runevent.Repository)
+	return runevent, nil
+}
+
+// isRerunRequestedAction checks if the event is a click on the Re-run button
+// we add on failed check runs.
+func isRerunRequestedAction(event *github.CheckRunEvent) bool {
+	return event.GetAction() == "requested_action" && event.GetCheckRun() != nil &&
+		event.GetRequestedAction().Identifier == RerunActionIdentifier
+}
+
+// handleReRunActionEvent re-runs only the PipelineRun of the check run on
+// which the Re-run button has been clicked, the PipelineRun name is stored as
+// the check run external id.
+func (v *Provider) handleReRunActionEvent(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
+	externalID := event.GetCheckRun().GetExternalID()
+	if externalID == "" {
+		return nil, fmt.Errorf("check run %d has no external id, cannot know which PipelineRun to re-run", event.GetCheckRun().GetID())
+	}
+	runevent, err := v.handleReRequestEvent(ctx, event)
+	if err != nil {
+		return runevent, err
+	}
+	runevent.CheckRunExternalID = externalID
+	v.Logger.Infof("Re-run of the PipelineRun of check run %s on %s/%s has been requested", externalID, runevent.Organization, runevent.Repository)
+	return runevent, nil
+}
+
+// isCancelRequestedAction checks if the event is a click on the Cancel button
+// we add on running check runs.
+func isCancelRequestedAction(event *github.CheckRunEvent) bool {
+	return event.GetAction() == "requested_action" && event.GetCheckRun() != nil &&
+		event.GetRequestedAction().Identifier == CancelActionIdentifier
+}
+
+// handleCancelActionEvent cancels the PipelineRun of the check run on which the
+// Cancel button has been clicked, the same way as a /cancel comment would.
+func (v *Provider) handleCancelActionEvent(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
+	externalID := event.GetCheckRun().GetExternalID()
+	if externalID == "" {
+		return nil, fmt.Errorf("check run %d has no external id, cannot know which PipelineRun to cancel", event.GetCheckRun().GetID())
+	}
+	runevent, err := v.handleReRequestEvent(ctx, event)
+	if err != nil {
+		return runevent, err
+	}
+	runevent.CancelPipelineRuns = true
+	runevent.CheckRunExternalID = externalID
+	v.Logger.Infof("Cancellation of the PipelineRun of check run %s on %s/%s has been requested", externalID, runevent.Organization, runevent.Repository)
+	return runevent, nil
+}
+
 func (v *Provider) handleCheckSuites(ctx context.Context, event *github.CheckSuiteEvent) (*info.Event, error) {
 	runevent := info.NewEvent()
 	runevent.Organization = event.GetRepo().GetOwner().GetLogin()
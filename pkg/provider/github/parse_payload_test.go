@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v59/github"
 	"gotest.tools/v3/assert"
@@ -71,6 +73,26 @@ var samplePRevent = github.PullRequestEvent{
 	Repo: sampleRepo,
 }
 
+var sampleDraftPRevent = github.PullRequestEvent{
+	Action: github.String("ready_for_review"),
+	PullRequest: &github.PullRequest{
+		Head: &github.PullRequestBranch{
+			SHA: github.String("sampleHeadsha"),
+			Ref: github.String("headred"),
+		},
+		Base: &github.PullRequestBranch{
+			SHA: github.String("basesha"),
+			Ref: github.String("baseref"),
+		},
+		User: &github.User{
+			Login: github.String("user"),
+		},
+		Title: github.String("my first PR"),
+		Draft: github.Bool(true),
+	},
+	Repo: sampleRepo,
+}
+
 var samplePR = github.PullRequest{
 	Number: github.Int(54321),
 	Head: &github.PullRequestBranch{
@@ -100,8 +122,11 @@ func TestParsePayLoad(t *testing.T) {
 		shaRet                     string
 		targetPipelinerun          string
 		targetCancelPipelinerun    string
+		checkRunExternalID         string
 		wantedBranchName           string
 		isCancelPipelineRunEnabled bool
+		applicationID              *int64
+		wantDraft                  bool
 	}{
 		{
 			name:          "bad/unknown event",
@@ -123,6 +148,15 @@ func TestParsePayLoad(t *testing.T) {
 			triggerTarget:      "pull_request",
 			payloadEventStruct: github.PullRequestReviewCommentEvent{Action: github.String("created")},
 		},
+		{
+			name:          "good/installation repositories",
+			eventType:     InstallationRepositoriesEventType,
+			triggerTarget: "pull_request",
+			payloadEventStruct: github.InstallationRepositoriesEvent{
+				Action: github.String("added"),
+				Sender: &github.User{Login: github.String("owner")},
+			},
+		},
 		{
 			name:               "bad/check run only issue recheck supported",
 			wantErrString:      "only issue recheck is supported",
@@ -203,6 +237,150 @@ func TestParsePayLoad(t *testing.T) {
 			muxReplies: map[string]interface{}{"/repos/owner/reponame/pulls/54321": samplePR},
 			shaRet:     "samplePRsha",
 		},
+		{
+			name:          "good/rerequest check_run of a pipelinerun",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "issue-recheck",
+			applicationID: github.Int64(4242),
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("rerequested"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					ExternalID: github.String("pipelinerun-abcde"),
+					App:        &github.App{ID: github.Int64(4242)},
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+			muxReplies:         map[string]interface{}{"/repos/owner/reponame/pulls/54321": samplePR},
+			shaRet:             "samplePRsha",
+			checkRunExternalID: "pipelinerun-abcde",
+		},
+		{
+			name:          "good/rerequest aggregated check_run",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "issue-recheck",
+			applicationID: github.Int64(4242),
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("rerequested"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					ExternalID: github.String(aggregatedCheckRunExternalID),
+					App:        &github.App{ID: github.Int64(4242)},
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+			muxReplies: map[string]interface{}{"/repos/owner/reponame/pulls/54321": samplePR},
+			shaRet:     "samplePRsha",
+		},
+		{
+			name:          "bad/rerequest check_run of another app",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "issue-recheck",
+			applicationID: github.Int64(4242),
+			wantErrString: "has not been created by the github application 4242",
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("rerequested"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					ID:         github.Int64(1),
+					ExternalID: github.String("pipelinerun-abcde"),
+					App:        &github.App{ID: github.Int64(1111)},
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+		},
+		{
+			name:          "good/re-run action on a failed check_run",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "issue-recheck",
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				Repo:   sampleRepo,
+				RequestedAction: &github.RequestedAction{
+					Identifier: RerunActionIdentifier,
+				},
+				CheckRun: &github.CheckRun{
+					ExternalID: github.String("pipelinerun-abcde"),
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+			muxReplies:         map[string]interface{}{"/repos/owner/reponame/pulls/54321": samplePR},
+			shaRet:             "samplePRsha",
+			checkRunExternalID: "pipelinerun-abcde",
+		},
+		{
+			name:          "bad/re-run action without external id",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "issue-recheck",
+			wantErrString: "has no external id, cannot know which PipelineRun to re-run",
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				Repo:   sampleRepo,
+				RequestedAction: &github.RequestedAction{
+					Identifier: RerunActionIdentifier,
+				},
+				CheckRun: &github.CheckRun{
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+		},
+		{
+			name:          "good/cancel action on a running check_run",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "cancel",
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				Repo:   sampleRepo,
+				RequestedAction: &github.RequestedAction{
+					Identifier: CancelActionIdentifier,
+				},
+				CheckRun: &github.CheckRun{
+					ExternalID: github.String("pipelinerun-abcde"),
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+			muxReplies:                 map[string]interface{}{"/repos/owner/reponame/pulls/54321": samplePR},
+			shaRet:                     "samplePRsha",
+			checkRunExternalID:         "pipelinerun-abcde",
+			isCancelPipelineRunEnabled: true,
+		},
+		{
+			name:          "bad/cancel action without external id",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: "cancel",
+			wantErrString: "has no external id, cannot know which PipelineRun to cancel",
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.String("requested_action"),
+				Repo:   sampleRepo,
+				RequestedAction: &github.RequestedAction{
+					Identifier: CancelActionIdentifier,
+				},
+				CheckRun: &github.CheckRun{
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+			},
+		},
 		// all checks in a check_suite
 		{
 			name:          "good/rerequest check_suite on pull request",
@@ -267,6 +445,14 @@ func TestParsePayLoad(t *testing.T) {
 			payloadEventStruct: samplePRevent,
 			shaRet:             "sampleHeadsha",
 		},
+		{
+			name:               "good/draft pull request",
+			eventType:          "pull_request",
+			triggerTarget:      "pull_request",
+			payloadEventStruct: sampleDraftPRevent,
+			shaRet:             "sampleHeadsha",
+			wantDraft:          true,
+		},
 		{
 			name:          "good/push",
 			eventType:     "push",
@@ -565,8 +751,9 @@ func TestParsePayLoad(t *testing.T) {
 			}
 			logger, _ := logger.GetLogger()
 			gprovider := Provider{
-				Client: ghClient,
-				Logger: logger,
+				Client:        ghClient,
+				Logger:        logger,
+				ApplicationID: tt.applicationID,
 			}
 			request := &http.Request{Header: map[string][]string{}}
 			request.Header.Set("X-GitHub-Event", tt.eventType)
@@ -593,10 +780,17 @@ func TestParsePayLoad(t *testing.T) {
 			assert.Equal(t, tt.shaRet, ret.SHA)
 			if tt.eventType == "pull_request" {
 				assert.Equal(t, "my first PR", ret.PullRequestTitle)
+				assert.Equal(t, tt.wantDraft, ret.PullRequestDraft)
+			}
+			if tt.eventType == InstallationRepositoriesEventType {
+				assert.Equal(t, InstallationRepositoriesEventType, ret.EventType)
+				assert.Equal(t, "owner", ret.Sender)
 			}
 			if tt.eventType == "commit_comment" {
 				assert.Equal(t, tt.wantedBranchName, ret.HeadBranch)
 				assert.Equal(t, tt.wantedBranchName, ret.BaseBranch)
+			}
+			if tt.eventType == "commit_comment" || tt.eventType == "check_run" {
 				assert.Equal(t, tt.isCancelPipelineRunEnabled, ret.CancelPipelineRuns)
 			}
 			if tt.targetPipelinerun != "" {
@@ -605,6 +799,7 @@ func TestParsePayLoad(t *testing.T) {
 			if tt.targetCancelPipelinerun != "" {
 				assert.Equal(t, tt.targetCancelPipelinerun, ret.TargetCancelPipelineRun)
 			}
+			assert.Equal(t, tt.checkRunExternalID, ret.CheckRunExternalID)
 		})
 	}
 }
@@ -835,3 +1030,80 @@ func TestAppTokenGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestAppTokenPermissions(t *testing.T) {
+	testNamespace := "pipelinesascode"
+	secretName := "pipelines-as-code-secret"
+	ctx, _ := rtesting.SetupFakeContext(t)
+	seedData, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Secret: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: testNamespace,
+				},
+				Data: map[string][]byte{
+					"github-application-id": []byte("12345"),
+					"github-private-key":    []byte(fakePrivateKey),
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name            string
+		grantedResponse string
+		wantRequestBody string
+		wantErrSubst    string
+	}{
+		{
+			name:            "permissions granted",
+			grantedResponse: `{"contents": "write", "checks": "write"}`,
+			wantRequestBody: `{}`,
+		},
+		{
+			name:            "installation missing checks write",
+			grantedResponse: `{"contents": "write", "checks": "read"}`,
+			wantRequestBody: `{}`,
+			wantErrSubst:    "installation is missing checks:write permission",
+		},
+		{
+			name:            "no permissions in the response",
+			grantedResponse: `null`,
+			wantRequestBody: `{}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc(fmt.Sprintf("/app/installations/%d/access_tokens", testInstallationID), func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NilError(t, err)
+				assert.Equal(t, strings.TrimSpace(string(body)), tt.wantRequestBody)
+				_, _ = fmt.Fprintf(w, `{"token": "orgtoken", "expires_at": "%s", "permissions": %s}`,
+					time.Now().Add(time.Hour).Format(time.RFC3339), tt.grantedResponse)
+			})
+			defer env.Patch(t, "PAC_GIT_PROVIDER_TOKEN_APIURL", serverURL+"/api/v3")()
+
+			logger, _ := logger.GetLogger()
+			gprovider := Provider{
+				Logger: logger,
+				Run: &params.Run{
+					Clients: clients.Clients{Log: logger, Kube: seedData.Kube},
+					Info: info.Info{
+						Pac:        &info.PacOpts{Settings: &settings.Settings{}},
+						Controller: &info.ControllerInfo{Secret: secretName},
+					},
+				},
+			}
+			token, err := gprovider.GetAppToken(ctx, seedData.Kube, "", testInstallationID, testNamespace)
+			if tt.wantErrSubst != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubst)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, token, "orgtoken")
+		})
+	}
+}
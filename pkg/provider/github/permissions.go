@@ -0,0 +1,89 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	oGitHub "github.com/google/go-github/v57/github"
+)
+
+var permissionLevels = map[string]int{
+	"read":  1,
+	"write": 2,
+	"admin": 3,
+}
+
+// requiredPermissions are the permissions the installation needs for us to
+// report the statuses.
+var requiredPermissions = map[string]string{
+	"checks": "write",
+}
+
+// MissingPermissionsError is returned when the installation doesn't have the
+// permissions we need to report the statuses.
+type MissingPermissionsError struct {
+	Missing []string
+}
+
+func (e *MissingPermissionsError) Error() string {
+	return fmt.Sprintf("installation is missing %s permission", strings.Join(e.Missing, ", "))
+}
+
+// NotRequested returns the missing permissions which are not granted either by
+// the permissions requested by the GitHub App itself, they cannot be granted
+// by the installation until the app requests them.
+func (e *MissingPermissionsError) NotRequested(appPermissions map[string]string) []string {
+	notRequested := []string{}
+	for _, missing := range e.Missing {
+		name, level, _ := strings.Cut(missing, ":")
+		if permissionLevels[appPermissions[name]] < permissionLevels[level] {
+			notRequested = append(notRequested, missing)
+		}
+	}
+	return notRequested
+}
+
+// checkRequiredPermissions makes sure the installation has the permissions we
+// need to report the statuses, so we fail early with a clear error instead of
+// an opaque 403 when creating the check run. Nothing is checked when GitHub
+// doesn't tell us the permissions of the token.
+func checkRequiredPermissions(granted oGitHub.InstallationPermissions) error {
+	grantedMap, err := permissionsToMap(granted)
+	if err != nil {
+		return err
+	}
+	if len(grantedMap) == 0 {
+		return nil
+	}
+	if missing := missingPermissions(requiredPermissions, grantedMap); len(missing) > 0 {
+		return &MissingPermissionsError{Missing: missing}
+	}
+	return nil
+}
+
+// missingPermissions returns the sorted "name:level" of the requested
+// permissions not granted at least at the requested level.
+func missingPermissions(requested, granted map[string]string) []string {
+	missing := []string{}
+	for name, level := range requested {
+		if permissionLevels[granted[name]] < permissionLevels[level] {
+			missing = append(missing, fmt.Sprintf("%s:%s", name, level))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func permissionsToMap(permissions oGitHub.InstallationPermissions) (map[string]string, error) {
+	data, err := json.Marshal(permissions)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]string{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
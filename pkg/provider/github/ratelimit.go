@@ -0,0 +1,217 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+)
+
+// rateLimitReserve is the number of requests kept in reserve before the
+// primary rate limit of GitHub, once reached the requests wait for the rate
+// limit to be reset instead of failing.
+const rateLimitReserve = 50
+
+// RateLimiter throttles the requests to the GitHub API as a token bucket
+// filled from the X-RateLimit-Remaining header of the responses and refilled
+// at the time of the X-RateLimit-Reset header, safe for concurrent use.
+type RateLimiter struct {
+	mutex   sync.Mutex
+	clock   clockwork.Clock
+	reserve int
+	// remaining is -1 until we have seen the rate limit of a response or
+	// once it has been reset.
+	remaining int
+	reset     time.Time
+}
+
+func NewRateLimiter(clock clockwork.Clock, reserve int) *RateLimiter {
+	return &RateLimiter{
+		clock:     clock,
+		reserve:   reserve,
+		remaining: -1,
+	}
+}
+
+// Remaining returns how many requests can still be sent before hitting the
+// rate limit from what we have seen on the responses, -1 when unknown.
+func (l *RateLimiter) Remaining() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.refill()
+	return l.remaining
+}
+
+// Transport returns a transport sending the requests through the limiter,
+// base defaults to http.DefaultTransport.
+func (l *RateLimiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitedTransport{limiter: l, base: base}
+}
+
+// refill forgets the remaining requests once the rate limit has been reset,
+// it has to be called with the mutex held.
+func (l *RateLimiter) refill() {
+	if l.remaining >= 0 && !l.clock.Now().Before(l.reset) {
+		l.remaining = -1
+	}
+}
+
+// wait blocks until a request can be sent without going over the reserve,
+// or the context is done.
+func (l *RateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mutex.Lock()
+		l.refill()
+		if l.remaining < 0 || l.remaining > l.reserve {
+			if l.remaining > 0 {
+				l.remaining--
+			}
+			l.mutex.Unlock()
+			return nil
+		}
+		delay := l.reset.Sub(l.clock.Now())
+		l.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-l.clock.After(delay):
+		}
+	}
+}
+
+// update records the rate limit of a response, responses of an older rate
+// limit window are ignored.
+func (l *RateLimiter) update(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetUnix, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	reset := time.Unix(resetUnix, 0)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.refill()
+	switch {
+	case l.remaining < 0, reset.After(l.reset):
+		l.remaining = remaining
+		l.reset = reset
+	case reset.Equal(l.reset) && remaining < l.remaining:
+		l.remaining = remaining
+	}
+}
+
+type rateLimitedTransport struct {
+	limiter *RateLimiter
+	base    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.limiter.update(resp.Header)
+	}
+	return resp, err
+}
+
+// rateLimitKey identifies a rate limit of GitHub, each installation of the
+// application has its own on each GitHub host.
+type rateLimitKey struct {
+	host           string
+	installationID int64
+}
+
+// RateLimiters holds the rate limiters of the GitHub hosts and installations,
+// safe for concurrent use.
+type RateLimiters struct {
+	mutex    sync.Mutex
+	clock    clockwork.Clock
+	reserve  int
+	limiters map[rateLimitKey]*RateLimiter
+}
+
+func NewRateLimiters(clock clockwork.Clock, reserve int) *RateLimiters {
+	return &RateLimiters{
+		clock:    clock,
+		reserve:  reserve,
+		limiters: map[rateLimitKey]*RateLimiter{},
+	}
+}
+
+// Get returns the rate limiter of the requests to the GitHub API at apiURL
+// with the token of installationID, 0 for the requests not authenticated as
+// an installation. An empty apiURL is the one of the public GitHub.
+func (r *RateLimiters) Get(apiURL string, installationID int64) *RateLimiter {
+	key := rateLimitKey{host: rateLimitHost(apiURL), installationID: installationID}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	limiter, ok := r.limiters[key]
+	if !ok {
+		limiter = NewRateLimiter(r.clock, r.reserve)
+		r.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// rateLimitHost returns the host of apiURL, the public GitHub API when empty.
+func rateLimitHost(apiURL string) string {
+	if apiURL == "" {
+		apiURL = keys.PublicGithubAPIURL
+	}
+	if !strings.HasPrefix(apiURL, "https://") && !strings.HasPrefix(apiURL, "http://") {
+		apiURL = "https://" + apiURL
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Host == "" {
+		return apiURL
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+// rateLimiters are the rate limiters shared by the requests to GitHub of all
+// the providers of the controller.
+var rateLimiters = NewRateLimiters(clockwork.NewRealClock(), rateLimitReserve)
+
+// RateLimitedTransport returns a transport sending the requests to the GitHub
+// API at apiURL with the token of installationID through their shared rate
+// limiter.
+func RateLimitedTransport(apiURL string, installationID int64, base http.RoundTripper) http.RoundTripper {
+	return rateLimiters.Get(apiURL, installationID).Transport(base)
+}
+
+// limiter returns the rate limiter of the requests to apiURL with the token
+// of installationID, it is kept as the one of the client of the provider.
+func (v *Provider) limiter(apiURL string, installationID int64) *RateLimiter {
+	limiters := rateLimiters
+	if v.rateLimiters != nil {
+		limiters = v.rateLimiters
+	}
+	v.rateLimiter = limiters.Get(apiURL, installationID)
+	return v.rateLimiter
+}
+
+// RateLimitRemaining returns how many requests can still be sent to GitHub
+// with the client of the provider before hitting the rate limit, -1 when
+// unknown.
+func (v *Provider) RateLimitRemaining() int {
+	if v.rateLimiter == nil {
+		return -1
+	}
+	return v.rateLimiter.Remaining()
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+// rateLimitServer answers with the rate limit headers of remaining requests
+// resetting at reset.
+func rateLimitServer(t *testing.T, remaining *int, reset time.Time) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(*remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		_, _ = fmt.Fprint(w, `{}`)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doRequest(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestRateLimiterRemaining(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Unix(1700000000, 0))
+	limiter := NewRateLimiter(clock, 10)
+	remaining := 100
+	ts := rateLimitServer(t, &remaining, clock.Now().Add(time.Hour))
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	assert.Equal(t, limiter.Remaining(), -1)
+	assert.NilError(t, doRequest(context.Background(), client, ts.URL))
+	assert.Equal(t, limiter.Remaining(), 100)
+
+	// a late response of the same window doesn't raise the budget back
+	limiter.update(http.Header{
+		"X-Ratelimit-Remaining": []string{"200"},
+		"X-Ratelimit-Reset":     []string{strconv.FormatInt(clock.Now().Add(time.Hour).Unix(), 10)},
+	})
+	assert.Equal(t, limiter.Remaining(), 100)
+
+	// the budget is refilled once the rate limit is reset
+	clock.Advance(time.Hour)
+	assert.Equal(t, limiter.Remaining(), -1)
+}
+
+func TestRateLimiterBlocksOnLowBudget(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Unix(1700000000, 0))
+	limiter := NewRateLimiter(clock, 10)
+	remaining := 10
+	ts := rateLimitServer(t, &remaining, clock.Now().Add(time.Minute))
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	// learn the budget, now at the reserve
+	assert.NilError(t, doRequest(context.Background(), client, ts.URL))
+	assert.Equal(t, limiter.Remaining(), 10)
+
+	done := make(chan error)
+	go func() {
+		done <- doRequest(context.Background(), client, ts.URL)
+	}()
+	clock.BlockUntil(1)
+	select {
+	case <-done:
+		t.Fatal("request should wait for the rate limit to be reset")
+	default:
+	}
+	remaining = 5000
+	clock.Advance(time.Minute)
+	assert.NilError(t, <-done)
+
+	// a request waiting for the reset gives up with its context
+	remaining = 0
+	ts = rateLimitServer(t, &remaining, clock.Now().Add(time.Minute))
+	assert.NilError(t, doRequest(context.Background(), client, ts.URL))
+	assert.Equal(t, limiter.Remaining(), 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- doRequest(ctx, client, ts.URL)
+	}()
+	clock.BlockUntil(1)
+	cancel()
+	assert.ErrorContains(t, <-done, "context canceled")
+}
+
+func TestProviderRateLimitRemaining(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	clock := clockwork.NewFakeClockAt(time.Unix(1700000000, 0))
+	remaining := 4242
+	ts := rateLimitServer(t, &remaining, clock.Now().Add(time.Hour))
+
+	v := New()
+	v.rateLimiters = NewRateLimiters(clock, rateLimitReserve)
+	assert.Equal(t, v.RateLimitRemaining(), -1)
+	v.Client, _, _ = makeClient(ctx, v.limiter(ts.URL, 1), ts.URL+"/api/v3", "token")
+	assert.Equal(t, v.RateLimitRemaining(), -1)
+
+	_, _, err := v.Client.Repositories.Get(ctx, "owner", "repo")
+	assert.NilError(t, err)
+	assert.Equal(t, v.RateLimitRemaining(), 4242)
+
+	// another installation on the same host has its own rate limit
+	other := New()
+	other.rateLimiters = v.rateLimiters
+	other.limiter(ts.URL, 2)
+	assert.Equal(t, other.RateLimitRemaining(), -1)
+}
+
+func TestRateLimitersGet(t *testing.T) {
+	limiters := NewRateLimiters(clockwork.NewFakeClock(), rateLimitReserve)
+
+	public := limiters.Get("", 1)
+	assert.Assert(t, limiters.Get("https://api.github.com", 1) == public)
+	assert.Assert(t, limiters.Get("api.github.com", 1) == public)
+	assert.Assert(t, limiters.Get("", 2) != public)
+	assert.Assert(t, limiters.Get("", 0) != public)
+
+	enterprise := limiters.Get("ghe.example.com", 1)
+	assert.Assert(t, enterprise != public)
+	assert.Assert(t, limiters.Get("https://ghe.example.com/api/v3", 1) == enterprise)
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// installationRepos is the cache of the repositories of the installations
+// shared by all the providers of the controller, listing them on every event
+// can take a lot of requests for the large installations.
+var installationRepos = NewRepoListCache(clockwork.NewRealClock())
+
+// InstallationRepositoriesEventType is the type of the event sent when
+// repositories are added to or removed from an installation.
+const InstallationRepositoriesEventType = "installation_repositories"
+
+// InstallationRepos returns the cache of the repositories of the
+// installations shared by the controller.
+func InstallationRepos() *RepoListCache {
+	return installationRepos
+}
+
+type repoListCacheKey struct {
+	enterpriseHost string
+	installationID int64
+}
+
+type cachedRepoList struct {
+	repos     []string
+	expiresAt time.Time
+}
+
+// RepoListCache is an in-memory cache of the repositories accessible to the
+// installations of the GitHub App keyed by installation ID and enterprise
+// host, safe for concurrent use.
+type RepoListCache struct {
+	mutex sync.Mutex
+	clock clockwork.Clock
+	repos map[repoListCacheKey]cachedRepoList
+}
+
+func NewRepoListCache(clock clockwork.Clock) *RepoListCache {
+	return &RepoListCache{
+		clock: clock,
+		repos: map[repoListCacheKey]cachedRepoList{},
+	}
+}
+
+// Get returns the cached repositories of an installation if they have been
+// listed less than their TTL ago.
+func (c *RepoListCache) Get(enterpriseHost string, installationID int64) ([]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := repoListCacheKey{enterpriseHost: enterpriseHost, installationID: installationID}
+	cached, ok := c.repos[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.clock.Now().Before(cached.expiresAt) {
+		delete(c.repos, key)
+		return nil, false
+	}
+	return cached.repos, true
+}
+
+// Set stores the repositories of an installation for ttl, they are not cached
+// when ttl is not positive.
+func (c *RepoListCache) Set(enterpriseHost string, installationID int64, repos []string, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.repos[repoListCacheKey{enterpriseHost: enterpriseHost, installationID: installationID}] = cachedRepoList{
+		repos:     repos,
+		expiresAt: c.clock.Now().Add(ttl),
+	}
+}
+
+// Invalidate removes the cached repositories of an installation, i.e: when
+// repositories have been added to or removed from it.
+func (c *RepoListCache) Invalidate(enterpriseHost string, installationID int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.repos, repoListCacheKey{enterpriseHost: enterpriseHost, installationID: installationID})
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+)
+
+func TestRepoListCache(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewRepoListCache(clock)
+	repos := []string{"https://github.com/owner/repo"}
+
+	_, ok := cache.Get("", 1)
+	assert.Assert(t, !ok)
+
+	cache.Set("", 1, repos, time.Minute)
+	got, ok := cache.Get("", 1)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, got, repos)
+
+	_, ok = cache.Get("ghe.example.com", 1)
+	assert.Assert(t, !ok, "the installations of another host should not share the cache")
+	_, ok = cache.Get("", 2)
+	assert.Assert(t, !ok)
+
+	clock.Advance(time.Minute)
+	_, ok = cache.Get("", 1)
+	assert.Assert(t, !ok, "the repositories should have expired")
+
+	cache.Set("", 1, repos, time.Minute)
+	cache.Invalidate("", 1)
+	_, ok = cache.Get("", 1)
+	assert.Assert(t, !ok, "the repositories should have been invalidated")
+
+	cache.Set("", 1, repos, 0)
+	_, ok = cache.Get("", 1)
+	assert.Assert(t, !ok, "the repositories should not be cached without a ttl")
+}
+
+func TestRepoListCacheConcurrent(t *testing.T) {
+	cache := NewRepoListCache(clockwork.NewRealClock())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			cache.Set("", id%3, []string{fmt.Sprint(id)}, time.Minute)
+			cache.Get("", id%3)
+			cache.Invalidate("", (id+1)%3)
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+func TestDetectInstallationRepositories(t *testing.T) {
+	installationRepos.Set("ghe.example.com", 42, []string{"https://ghe.example.com/owner/repo"}, time.Minute)
+	defer installationRepos.Invalidate("ghe.example.com", 42)
+	gprovider := Provider{}
+	log, _ := logger.GetLogger()
+
+	payload, err := json.Marshal(github.InstallationRepositoriesEvent{
+		Action:       github.String("added"),
+		Installation: &github.Installation{ID: github.Int64(42)},
+	})
+	assert.NilError(t, err)
+	header := http.Header{}
+	header.Set("X-GitHub-Event", "installation_repositories")
+	header.Set("X-GitHub-Enterprise-Host", "ghe.example.com")
+
+	isGH, processReq, _, _, err := gprovider.Detect(&http.Request{Header: header}, string(payload), log)
+	assert.NilError(t, err)
+	assert.Assert(t, isGH)
+	assert.Assert(t, processReq, "the event should be processed to validate its signature")
+
+	_, ok := installationRepos.Get("ghe.example.com", 42)
+	assert.Assert(t, ok, "the repositories should not be invalidated before the signature is validated")
+}
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v59/github"
+)
+
+// ChecksAPI is the part of the checks API of GitHub used to report the
+// statuses as check runs, implemented by the checks service of the go-github
+// client.
+type ChecksAPI interface {
+	ListCheckRunsForRef(ctx context.Context, owner, repo, ref string, opts *github.ListCheckRunsOptions) (*github.ListCheckRunsResults, *github.Response, error)
+	CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+	UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+}
+
+// RepositoriesAPI is the part of the repositories API of GitHub used to
+// report the statuses as commit statuses and deployments, implemented by the
+// repositories service of the go-github client.
+type RepositoriesAPI interface {
+	CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
+	ListDeployments(ctx context.Context, owner, repo string, opts *github.DeploymentsListOptions) ([]*github.Deployment, *github.Response, error)
+	CreateDeployment(ctx context.Context, owner, repo string, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error)
+	CreateDeploymentStatus(ctx context.Context, owner, repo string, deployment int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error)
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+}
+
+// IssuesAPI is the part of the issues API of GitHub used for the comments on
+// the pull requests, implemented by the issues service of the go-github
+// client.
+type IssuesAPI interface {
+	ListComments(ctx context.Context, owner, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	EditComment(ctx context.Context, owner, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+}
+
+// GistsAPI is the part of the gists API of GitHub used to publish the
+// summary of the commit statuses, implemented by the gists service of the
+// go-github client.
+type GistsAPI interface {
+	Create(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error)
+	Edit(ctx context.Context, id string, gist *github.Gist) (*github.Gist, *github.Response, error)
+}
+
+var (
+	_ ChecksAPI       = (*github.ChecksService)(nil)
+	_ RepositoriesAPI = (*github.RepositoriesService)(nil)
+	_ IssuesAPI       = (*github.IssuesService)(nil)
+	_ GistsAPI        = (*github.GistsService)(nil)
+)
+
+func (v *Provider) checks() ChecksAPI {
+	if v.Checks != nil {
+		return v.Checks
+	}
+	return v.Client.Checks
+}
+
+func (v *Provider) repositories() RepositoriesAPI {
+	if v.Repositories != nil {
+		return v.Repositories
+	}
+	return v.Client.Repositories
+}
+
+func (v *Provider) issues() IssuesAPI {
+	if v.Issues != nil {
+		return v.Issues
+	}
+	return v.Client.Issues
+}
+
+// gists returns the gists API, nil when there is neither a client nor an
+// override.
+func (v *Provider) gists() GistsAPI {
+	if v.Gists != nil {
+		return v.Gists
+	}
+	if v.Client == nil {
+		return nil
+	}
+	return v.Client.Gists
+}
+
+// hasStatusClients returns true when we can report the statuses, either with
+// the client or with the APIs set on the provider.
+func (v *Provider) hasStatusClients() bool {
+	return v.Client != nil || (v.Checks != nil && v.Repositories != nil && v.Issues != nil)
+}
@@ -0,0 +1,184 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	ghtesting "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github/testing"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+var (
+	_ ChecksAPI       = (*ghtesting.Checks)(nil)
+	_ RepositoriesAPI = (*ghtesting.Repositories)(nil)
+	_ IssuesAPI       = (*ghtesting.Issues)(nil)
+)
+
+func newFakeProvider() (*Provider, *ghtesting.Checks, *ghtesting.Repositories, *ghtesting.Issues) {
+	checks, repositories, issues := &ghtesting.Checks{}, &ghtesting.Repositories{}, &ghtesting.Issues{}
+	v := &Provider{
+		Run:          params.New(),
+		Checks:       checks,
+		Repositories: repositories,
+		Issues:       issues,
+	}
+	v.Run.Info.Pac = &info.PacOpts{Settings: &settings.Settings{
+		ApplicationName: settings.PACApplicationNameDefaultValue,
+		CommentStrategy: settings.CommentStrategyAll,
+	}}
+	v.Logger, _ = logger.GetLogger()
+	return v, checks, repositories, issues
+}
+
+func TestCreateStatusWithFakeCheckRuns(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	v, checks, _, _ := newFakeProvider()
+	event := &info.Event{
+		Organization:   "owner",
+		Repository:     "repo",
+		SHA:            "sha",
+		InstallationID: 1,
+	}
+
+	assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+		PipelineRunName:         "pr-abcde",
+		OriginalPipelineRunName: "pr",
+		Status:                  "in_progress",
+	}))
+	assert.Equal(t, len(checks.CheckRuns), 1)
+	checkRun := checks.CheckRuns[0]
+	assert.Equal(t, checkRun.GetName(), "Pipelines as Code CI / pr")
+	assert.Equal(t, checkRun.GetHeadSHA(), "sha")
+	assert.Equal(t, checkRun.GetStatus(), "in_progress")
+
+	assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+		PipelineRunName:         "pr-abcde",
+		OriginalPipelineRunName: "pr",
+		Status:                  "completed",
+		Conclusion:              "success",
+	}))
+	assert.Equal(t, len(checks.CheckRuns), 1, "the check run of the PipelineRun should have been reused")
+	assert.Equal(t, checkRun.GetStatus(), "completed")
+	assert.Equal(t, checkRun.GetConclusion(), "success")
+	updates := checks.Updates[checkRun.GetID()]
+	assert.Equal(t, len(updates), 2)
+	assert.Assert(t, updates[1].CompletedAt != nil)
+}
+
+func TestCreateStatusWithFakeCommitStatuses(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	v, checks, repositories, issues := newFakeProvider()
+	event := &info.Event{
+		Organization:      "owner",
+		Repository:        "repo",
+		SHA:               "sha",
+		EventType:         triggertype.PullRequest.String(),
+		PullRequestNumber: 42,
+	}
+	status := provider.StatusOpts{
+		PipelineRunName:         "pr-abcde",
+		OriginalPipelineRunName: "pr",
+		Status:                  "completed",
+		Conclusion:              "failure",
+		Text:                    "first run",
+	}
+
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+	status.Text = "second run"
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+
+	assert.Equal(t, len(checks.CheckRuns), 0)
+	assert.Equal(t, len(repositories.Statuses), 2)
+	assert.Equal(t, repositories.Statuses[0].Ref, "sha")
+	assert.Equal(t, repositories.Statuses[0].GetState(), "failure")
+	assert.Equal(t, repositories.Statuses[0].GetContext(), "Pipelines as Code CI / pr")
+	assert.Equal(t, len(issues.Comments), 1, "the status comment should have been edited")
+	assert.Equal(t, issues.Comments[0].Number, 42)
+	assert.Assert(t, !strings.Contains(issues.Comments[0].GetBody(), "first run"))
+	assert.Assert(t, strings.Contains(issues.Comments[0].GetBody(), "second run"))
+}
+
+func TestCreateStatusFailedTaskDetailsURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		failedTaskLogURL string
+		want             string
+	}{
+		{
+			name: "pipelinerun logs",
+			want: "https://console/pr",
+		},
+		{
+			name:             "logs of the only failed task",
+			failedTaskLogURL: "https://console/pr?pipelineTask=unit",
+			want:             "https://console/pr?pipelineTask=unit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			v, checks, _, _ := newFakeProvider()
+			event := &info.Event{
+				Organization:   "owner",
+				Repository:     "repo",
+				SHA:            "sha",
+				InstallationID: 1,
+			}
+			assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+				PipelineRunName:  "pr",
+				Status:           "completed",
+				Conclusion:       "failure",
+				DetailsURL:       "https://console/pr",
+				FailedTaskLogURL: tt.failedTaskLogURL,
+			}))
+			assert.Equal(t, len(checks.CheckRuns), 1)
+			assert.Equal(t, checks.CheckRuns[0].GetDetailsURL(), tt.want)
+		})
+	}
+}
+
+func TestCreateStatusApprovalGate(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	v, checks, repositories, _ := newFakeProvider()
+	v.Run.Info.Pac.StatusMode = settings.StatusModeBoth
+	event := &info.Event{
+		Organization:   "owner",
+		Repository:     "repo",
+		SHA:            "sha",
+		InstallationID: 1,
+	}
+	status := provider.StatusOpts{
+		PipelineRunName:         "pr-abcde",
+		OriginalPipelineRunName: "pr",
+		Status:                  "in_progress",
+		Conclusion:              "pending",
+		ApprovalGate:            "approve-deploy",
+	}
+
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+	assert.Equal(t, len(checks.CheckRuns), 1)
+	checkRun := checks.CheckRuns[0]
+	assert.Equal(t, checkRun.GetStatus(), "completed")
+	assert.Equal(t, checkRun.GetConclusion(), "action_required")
+	update := checks.Updates[checkRun.GetID()][0]
+	assert.Equal(t, update.Output.GetTitle(), "Waiting for approval")
+	assert.Equal(t, update.Output.GetSummary(), "Pipelines as Code CI/pr is waiting for the approval of <b>approve-deploy</b>.")
+	assert.Equal(t, len(update.Actions), 0, "the gate is approved with the approval task, not from the check run")
+
+	assert.Equal(t, len(repositories.Statuses), 1)
+	assert.Equal(t, repositories.Statuses[0].GetState(), "pending")
+	assert.Equal(t, repositories.Statuses[0].GetDescription(), "Waiting for approval")
+
+	// once approved the PipelineRun is running again
+	status.ApprovalGate = ""
+	assert.NilError(t, v.CreateStatus(ctx, event, status))
+	assert.Equal(t, checkRun.GetStatus(), "in_progress")
+	assert.Equal(t, repositories.Statuses[1].GetState(), "pending")
+}
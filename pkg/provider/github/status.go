@@ -2,26 +2,76 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/google/go-github/v59/github"
+	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	knative1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// RerunActionIdentifier identifies the check run action button re-running
+	// a failed PipelineRun.
+	RerunActionIdentifier = "pac-rerun"
+	// RerunActionLabel is the label of the check run action button
+	// re-running a failed PipelineRun.
+	RerunActionLabel       = "Re-run"
+	rerunActionDescription = "Re-run this PipelineRun"
+	// CancelActionIdentifier identifies the check run action button
+	// cancelling a running PipelineRun.
+	CancelActionIdentifier = "pac-cancel"
+	// CancelActionLabel is the label of the check run action button
+	// cancelling a running PipelineRun.
+	CancelActionLabel       = "Cancel"
+	cancelActionDescription = "Cancel this PipelineRun"
+
+	// queuedCheckRunTitle is the title of the check run reported when an
+	// event is accepted, reused by the first PipelineRun started.
+	queuedCheckRunTitle = "CI is queued"
+
+	// maxCheckRunsPerCommit is the number of check runs GitHub shows on a
+	// commit, the ones over it are not shown and can't be required.
+	maxCheckRunsPerCommit = 1000
+	// checkRunsAggregationThreshold is the number of check runs on a commit
+	// from which the new PipelineRuns are reported in a single aggregated
+	// check run instead of one check run each.
+	checkRunsAggregationThreshold = maxCheckRunsPerCommit * 9 / 10
+	aggregatedCheckRunExternalID  = "pipelines-as-code-aggregated"
+	aggregatedCheckRunName        = "aggregated"
+
+	// maxStatusDescriptionLength and maxStatusContextLength are the number of
+	// characters GitHub accepts in the description and the context of a
+	// commit status.
+	maxStatusDescriptionLength = 140
+	maxStatusContextLength     = 255
+
+	// maxAnnotationsPerRequest is the number of annotations GitHub accepts
+	// in one check run update, the next ones have to be sent in other
+	// updates.
+	maxAnnotationsPerRequest = 50
 )
 
 const taskStatusTemplate = `
 <table>
-  <tr><th>Status</th><th>Duration</th><th>Name</th></tr>
+  <tr><th>Status</th><th>Duration</th><th>Name</th>{{- if .ShowResourceRequests }}<th>Resource Requests</th>{{- end }}</tr>
 
 {{- range $taskrun := .TaskRunList }}
 <tr>
@@ -29,25 +79,84 @@ const taskStatusTemplate = `
 <td>{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}</td><td>
 
 {{ $taskrun.ConsoleLogURL }}
+{{- with $taskrun.PodEvent }}<br>{{ . }}{{- end }}
 
-</td></tr>
+</td>{{- if $.ShowResourceRequests }}<td>{{ $taskrun.ResourceRequests }}</td>{{- end }}</tr>
 {{- end }}
 </table>`
 
-func getCheckName(status provider.StatusOpts, pacopts *info.PacOpts) string {
-	if pacopts.ApplicationName != "" {
-		if status.OriginalPipelineRunName == "" {
-			return pacopts.ApplicationName
+// getApplicationName returns the application name of the repository when
+// overridden or the one from the settings.
+func getApplicationName(pacopts *info.PacOpts, runevent *info.Event) string {
+	if runevent.ApplicationName != "" {
+		return runevent.ApplicationName
+	}
+	return pacopts.ApplicationName
+}
+
+// checkNameParams are the variables of the check-name-template setting.
+type checkNameParams struct {
+	AppName     string
+	PipelineRun string
+}
+
+// joinCheckName joins the application name and the PipelineRun name with the
+// check-name-template setting, or as "app / pipelinerun" when not set or when
+// the template cannot be rendered.
+func joinCheckName(logger *zap.SugaredLogger, nameTemplate, applicationName, pipelineRunName string) string {
+	name := fmt.Sprintf("%s / %s", applicationName, pipelineRunName)
+	if nameTemplate == "" {
+		return name
+	}
+	tmpl, err := template.New("check-name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		logger.Warnf("invalid check-name-template %q, using the default check name: %v", nameTemplate, err)
+		return name
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, checkNameParams{AppName: applicationName, PipelineRun: pipelineRunName}); err != nil {
+		logger.Warnf("cannot render check-name-template %q, using the default check name: %v", nameTemplate, err)
+		return name
+	}
+	if strings.TrimSpace(out.String()) == "" {
+		logger.Warnf("check-name-template %q renders an empty check name, using the default check name", nameTemplate)
+		return name
+	}
+	return out.String()
+}
+
+func getCheckName(logger *zap.SugaredLogger, status provider.StatusOpts, pacopts *info.PacOpts, runevent *info.Event) string {
+	name := status.OriginalPipelineRunName
+	if applicationName := getApplicationName(pacopts, runevent); applicationName != "" {
+		name = applicationName
+		if status.OriginalPipelineRunName != "" {
+			name = joinCheckName(logger, pacopts.CheckNameTemplate, applicationName, status.OriginalPipelineRunName)
 		}
-		return fmt.Sprintf("%s / %s", pacopts.ApplicationName, status.OriginalPipelineRunName)
 	}
-	return status.OriginalPipelineRunName
+	if status.CheckNameSuffix != "" {
+		name = fmt.Sprintf("%s (%s)", name, status.CheckNameSuffix)
+	}
+	// use a distinct name on protected tags so release gates can require it
+	if provider.IsProtectedTag(runevent, pacopts.ProtectedTags) {
+		name = fmt.Sprintf("%s (protected tag)", name)
+	}
+	return pacopts.StatusContextPrefix + name
 }
 
 func (v *Provider) getExistingCheckRunID(ctx context.Context, runevent *info.Event, status provider.StatusOpts) (*int64, error) {
+	if v.useGraphQL() {
+		checkruns, err := v.graphqlCheckRuns(ctx, runevent)
+		if err == nil {
+			return v.matchCheckRunID(checkruns, status), nil
+		}
+		if v.Logger != nil {
+			v.Logger.Debugf("cannot get the check runs with graphql, using the rest api: %v", err)
+		}
+	}
+
 	opt := github.ListOptions{PerPage: v.paginedNumber}
 	for {
-		res, resp, err := v.Client.Checks.ListCheckRunsForRef(ctx, runevent.Organization, runevent.Repository,
+		res, resp, err := v.checks().ListCheckRunsForRef(ctx, runevent.Organization, runevent.Repository,
 			runevent.SHA, &github.ListCheckRunsOptions{
 				AppID:       v.ApplicationID,
 				ListOptions: opt,
@@ -56,16 +165,8 @@ func (v *Provider) getExistingCheckRunID(ctx context.Context, runevent *info.Eve
 			return nil, err
 		}
 
-		for _, checkrun := range res.CheckRuns {
-			// if it is a Pending approval CheckRun then overwrite it
-			if isPendingApprovalCheckrun(checkrun) {
-				if v.canIUseCheckrunID(checkrun.ID) {
-					return checkrun.ID, nil
-				}
-			}
-			if *checkrun.ExternalID == status.PipelineRunName {
-				return checkrun.ID, nil
-			}
+		if id := v.matchCheckRunID(res.CheckRuns, status); id != nil {
+			return id, nil
 		}
 		if resp.NextPage == 0 {
 			break
@@ -76,6 +177,29 @@ func (v *Provider) getExistingCheckRunID(ctx context.Context, runevent *info.Eve
 	return nil, nil
 }
 
+// matchCheckRunID returns the ID of the check run of the PipelineRun among
+// the check runs, or of a pending approval or queued one it can take over.
+func (v *Provider) matchCheckRunID(checkruns []*github.CheckRun, status provider.StatusOpts) *int64 {
+	for _, checkrun := range checkruns {
+		// if it is a Pending approval CheckRun then overwrite it
+		if isPendingApprovalCheckrun(checkrun) {
+			if v.canIUseCheckrunID(checkrun.ID) {
+				return checkrun.ID
+			}
+		}
+		// if it is the queued CheckRun of the event then take it over
+		if isQueuedCheckrun(checkrun) {
+			if v.canIUseCheckrunID(checkrun.ID) {
+				return checkrun.ID
+			}
+		}
+		if checkrun.GetExternalID() == status.PipelineRunName {
+			return checkrun.ID
+		}
+	}
+	return nil
+}
+
 func isPendingApprovalCheckrun(run *github.CheckRun) bool {
 	if run == nil || run.Output == nil {
 		return false
@@ -88,6 +212,13 @@ func isPendingApprovalCheckrun(run *github.CheckRun) bool {
 	return false
 }
 
+func isQueuedCheckrun(run *github.CheckRun) bool {
+	if run == nil || run.Output == nil || run.GetExternalID() != "" {
+		return false
+	}
+	return run.GetStatus() == "queued" && run.Output.GetTitle() == queuedCheckRunTitle
+}
+
 func (v *Provider) canIUseCheckrunID(checkrunid *int64) bool {
 	v.mutex.Lock()
 	defer v.mutex.Unlock()
@@ -100,20 +231,26 @@ func (v *Provider) canIUseCheckrunID(checkrunid *int64) bool {
 }
 
 func (v *Provider) createCheckRunStatus(ctx context.Context, runevent *info.Event, status provider.StatusOpts) (*int64, error) {
-	now := github.Timestamp{Time: time.Now()}
+	startedAt := github.Timestamp{Time: pipelineRunStartedAt(status.PipelineRun)}
 	checkrunoption := github.CreateCheckRunOptions{
-		Name:       getCheckName(status, v.Run.Info.Pac),
+		Name:       getCheckName(v.Logger, status, v.Run.Info.Pac, runevent),
 		HeadSHA:    runevent.SHA,
 		Status:     github.String("in_progress"),
 		DetailsURL: github.String(status.DetailsURL),
 		ExternalID: github.String(status.PipelineRunName),
-		StartedAt:  &now,
+		StartedAt:  &startedAt,
+	}
+	// a queued check run has not started yet
+	if status.Conclusion == "queued" {
+		checkrunoption.Status = github.String("queued")
+		checkrunoption.StartedAt = nil
 	}
 
-	checkRun, _, err := v.Client.Checks.CreateCheckRun(ctx, runevent.Organization, runevent.Repository, checkrunoption)
+	checkRun, _, err := v.checks().CreateCheckRun(ctx, runevent.Organization, runevent.Repository, checkrunoption)
 	if err != nil {
 		return nil, err
 	}
+	v.statusLogger(runevent, status).With("check-run-id", checkRun.GetID()).Infof("created check run %s", checkrunoption.Name)
 	return checkRun.ID, nil
 }
 
@@ -189,6 +326,18 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, runevent *info
 	var checkRunID *int64
 	var found bool
 	pacopts := v.Run.Info.Pac
+	logger := v.statusLogger(runevent, statusOpts)
+
+	if statusOpts.ShowCounts {
+		if badge := taskRunCountsBadge(statusOpts); badge != "" {
+			statusOpts.Summary = fmt.Sprintf("%s\n\n%s", badge, statusOpts.Summary)
+		}
+	}
+	statusOpts = withFailureReason(statusOpts)
+	opts := v.makeCheckRunOptions(ctx, runevent, statusOpts)
+	if pacopts.DryRun {
+		return v.logDryRun("check run", opts.Name, opts.GetConclusion(), statusOpts.Summary, opts)
+	}
 
 	// check if pipelineRun has the label with checkRun-id
 	if statusOpts.PipelineRun != nil {
@@ -202,36 +351,149 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, runevent *info
 			checkRunID = github.Int64(int64(checkID))
 		}
 	}
-	if !found {
-		if checkRunID, _ = v.getExistingCheckRunID(ctx, runevent, statusOpts); checkRunID == nil {
-			checkRunID, err = v.createCheckRunStatus(ctx, runevent, statusOpts)
+	// the PipelineRuns reported in the aggregated check run have its id in
+	// another annotation, so they keep on being reported there
+	aggregated := false
+	if !found && statusOpts.PipelineRun != nil {
+		if id, ok := statusOpts.PipelineRun.GetAnnotations()[keys.AggregatedCheckRunID]; ok {
+			checkID, err := strconv.ParseInt(id, 10, 64)
 			if err != nil {
-				return err
+				return fmt.Errorf("api error: cannot convert aggregated checkrunid")
 			}
+			checkRunID = github.Int64(checkID)
+			found, aggregated = true, true
 		}
-		if statusOpts.PipelineRun != nil {
-			if _, err := action.PatchPipelineRun(ctx, v.Logger, "checkRunID and logURL", v.Run.Clients.Tekton, statusOpts.PipelineRun, metadataPatch(checkRunID, statusOpts.DetailsURL)); err != nil {
+	}
+	if !found {
+		if checkRunID, _ = v.getExistingCheckRunID(ctx, runevent, statusOpts); checkRunID == nil && v.isNearCheckRunsLimit(ctx, runevent) {
+			logger.Infof("commit %s is close to the limit of %d check runs, reporting pipelinerun %s in the aggregated check run",
+				runevent.SHA, maxCheckRunsPerCommit, statusOpts.PipelineRunName)
+			aggregated = true
+			if checkRunID, err = v.getOrCreateAggregatedCheckRun(ctx, runevent, statusOpts); err != nil {
 				return err
 			}
+		} else {
+			if checkRunID == nil {
+				checkRunID, err = v.createCheckRunStatus(ctx, runevent, statusOpts)
+				if err != nil {
+					return err
+				}
+			}
+			if statusOpts.PipelineRun != nil {
+				if _, err := action.PatchPipelineRun(ctx, v.Logger, "checkRunID and logURL", v.Run.Clients.Tekton, statusOpts.PipelineRun, metadataPatch(checkRunID, statusOpts.DetailsURL)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if aggregated {
+		opts = v.makeAggregatedCheckRunOptions(ctx, runevent, statusOpts, *checkRunID, opts)
+	}
+
+	logger = logger.With("check-run-id", *checkRunID)
+	// GitHub only accepts a limited number of annotations per update, the
+	// annotations of the next updates are added to the ones already there.
+	annotations := opts.Output.Annotations
+	for {
+		size := len(annotations)
+		if size > maxAnnotationsPerRequest {
+			size = maxAnnotationsPerRequest
+		}
+		output := *opts.Output
+		output.Annotations = annotations[:size]
+		annotations = annotations[size:]
+		opts.Output = &output
+		start := time.Now()
+		_, _, err = v.checks().UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts)
+		v.recordAPILatency("UpdateCheckRun", time.Since(start))
+		if err != nil {
+			logger.Errorf("cannot update check run %s: %v", opts.Name, err)
+			return err
+		}
+		if len(annotations) == 0 {
+			break
 		}
 	}
+	logger.Infof("updated check run %s with status %s", opts.Name, opts.GetStatus())
+	return nil
+}
 
-	text := statusOpts.Text
-	checkRunOutput := &github.CheckRunOutput{
-		Title:   &statusOpts.Title,
-		Summary: &statusOpts.Summary,
+// taskRunCountsBadge renders the counts of the TaskRuns of the status by
+// condition and of the skipped tasks of the PipelineRun, i.e: "✅ 12  ❌ 1  ⏭ 2".
+func taskRunCountsBadge(statusOpts provider.StatusOpts) string {
+	conditions := make([]knative1.Conditions, 0, len(statusOpts.TaskRunStatuses))
+	for _, taskRunStatus := range statusOpts.TaskRunStatuses {
+		if taskRunStatus == nil || taskRunStatus.Status == nil {
+			continue
+		}
+		conditions = append(conditions, taskRunStatus.Status.Conditions)
+	}
+	counts := formatting.CountConditions(conditions)
+	if statusOpts.PipelineRun != nil {
+		counts.Skipped = len(statusOpts.PipelineRun.Status.SkippedTasks)
 	}
+	return counts.Badge(false)
+}
+
+// makeCheckRunAnnotations converts the annotations of the status to check run
+// annotations, the levels GitHub doesn't know about are failures since it
+// refuses the whole update otherwise.
+func makeCheckRunAnnotations(annotations []provider.Annotation) []*github.CheckRunAnnotation {
+	ret := []*github.CheckRunAnnotation{}
+	for _, annotation := range annotations {
+		level := annotation.Level
+		switch level {
+		case "notice", "warning", "failure":
+		default:
+			level = "failure"
+		}
+		endLine := annotation.EndLine
+		if endLine < annotation.StartLine {
+			endLine = annotation.StartLine
+		}
+		ret = append(ret, &github.CheckRunAnnotation{
+			Path:            github.String(strings.TrimPrefix(annotation.Path, "./")),
+			StartLine:       github.Int(annotation.StartLine),
+			EndLine:         github.Int(endLine),
+			AnnotationLevel: github.String(level),
+			Message:         github.String(annotation.Message),
+		})
+	}
+	return ret
+}
+
+// pipelineRunStartedAt returns when the PipelineRun has started, now when it
+// has not started yet or is not known.
+func pipelineRunStartedAt(pr *tektonv1.PipelineRun) time.Time {
+	if pr == nil || pr.Status.StartTime == nil {
+		return time.Now()
+	}
+	return pr.Status.StartTime.Time
+}
+
+// pipelineRunCompletedAt returns when the PipelineRun has completed, now when
+// it has not completed yet or is not known.
+func pipelineRunCompletedAt(pr *tektonv1.PipelineRun) time.Time {
+	if pr == nil || pr.Status.CompletionTime == nil {
+		return time.Now()
+	}
+	return pr.Status.CompletionTime.Time
+}
+
+// makeCheckRunOptions makes the payload to update the check run with.
+func (v *Provider) makeCheckRunOptions(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts) github.UpdateCheckRunOptions {
+	pacopts := v.Run.Info.Pac
+	checkRunOutput := makeCheckRunOutput(statusOpts.Title, statusOpts.Summary, statusOpts.Text, statusLogURL(statusOpts))
 
 	if statusOpts.PipelineRun != nil {
 		if pacopts.ErrorDetection {
 			checkRunOutput.Annotations = v.getFailuresMessageAsAnnotations(ctx, statusOpts.PipelineRun, pacopts)
 		}
 	}
-
-	checkRunOutput.Text = github.String(text)
+	checkRunOutput.Annotations = append(checkRunOutput.Annotations, makeCheckRunAnnotations(statusOpts.Annotations)...)
 
 	opts := github.UpdateCheckRunOptions{
-		Name:   getCheckName(statusOpts, pacopts),
+		Name:   getCheckName(v.Logger, statusOpts, pacopts, runevent),
 		Status: github.String(statusOpts.Status),
 		Output: checkRunOutput,
 	}
@@ -241,18 +503,99 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, runevent *info
 	if statusOpts.DetailsURL != "" {
 		opts.DetailsURL = &statusOpts.DetailsURL
 	}
+	// link straight to the logs of the only failed task
+	if statusOpts.FailedTaskLogURL != "" {
+		opts.DetailsURL = &statusOpts.FailedTaskLogURL
+	}
 
-	// Only set completed-at if conclusion is set (which means finished)
-	if statusOpts.Conclusion != "" && statusOpts.Conclusion != "pending" {
-		opts.CompletedAt = &github.Timestamp{Time: time.Now()}
+	// Only set completed-at if conclusion is set (which means finished),
+	// skipped and neutral are genuine check run conclusions and passed as is,
+	// unlike the commit statuses which don't have them.
+	if statusOpts.Conclusion != "" && statusOpts.Conclusion != "pending" && statusOpts.Conclusion != "queued" {
+		opts.CompletedAt = &github.Timestamp{Time: pipelineRunCompletedAt(statusOpts.PipelineRun)}
 		opts.Conclusion = &statusOpts.Conclusion
 	}
 	if isPipelineRunCancelledOrStopped(statusOpts.PipelineRun) {
 		opts.Conclusion = github.String("cancelled")
 	}
+	if opts.GetConclusion() == "failure" {
+		opts.Actions = []*github.CheckRunAction{
+			{
+				Label:       RerunActionLabel,
+				Description: rerunActionDescription,
+				Identifier:  RerunActionIdentifier,
+			},
+		}
+	}
+	if statusOpts.Status == "in_progress" && statusOpts.PipelineRunName != "" {
+		opts.Actions = []*github.CheckRunAction{
+			{
+				Label:       CancelActionLabel,
+				Description: cancelActionDescription,
+				Identifier:  CancelActionIdentifier,
+			},
+		}
+	}
+	return opts
+}
 
-	_, _, err = v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts)
-	return err
+// recordAPILatency records the latency of a call to the GitHub API reporting a
+// status when we have a metrics recorder.
+func (v *Provider) recordAPILatency(api string, latency time.Duration) {
+	if v.Metrics == nil {
+		return
+	}
+	if err := v.Metrics.APILatency(v.metricsProviderName(), api, latency); err != nil && v.Logger != nil {
+		v.Logger.Debugf("cannot record the latency of %s: %v", api, err)
+	}
+}
+
+// metricsProviderName returns the name of the provider as labeled on the
+// metrics, the same as the PipelineRun metrics.
+func (v *Provider) metricsProviderName() string {
+	name := v.providerName
+	if name == "" {
+		name = "github"
+	}
+	if v.ApplicationID != nil {
+		return name + "-app"
+	}
+	return name + "-webhook"
+}
+
+// logDryRun logs the payload we would have sent to GitHub when running in dry
+// run mode.
+func (v *Provider) logDryRun(kind, checkName, conclusion, summary string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	v.Logger.Infof("dry-run: not creating %s %s with conclusion %s and summary %q, payload: %s",
+		kind, checkName, conclusion, summary, string(data))
+	return nil
+}
+
+// makeCheckRunOutput makes the output of a check run with every field
+// truncated to the size GitHub accepts, GitHub refuses the whole update
+// otherwise.
+func makeCheckRunOutput(title, summary, text, logURL string) *github.CheckRunOutput {
+	title, _ = formatting.TruncateRunes(title, maxTitleLength)
+	return &github.CheckRunOutput{
+		Title:   github.String(title),
+		Summary: github.String(formatting.TruncateText(summary, maxTextSize, logURL)),
+		Text:    github.String(formatting.TruncateText(text, maxTextSize, logURL)),
+	}
+}
+
+// statusLogURL returns the URL of the logs of the PipelineRun of a status,
+// the details URL of the status when it is not known.
+func statusLogURL(status provider.StatusOpts) string {
+	if status.PipelineRun != nil {
+		if logURL := status.PipelineRun.GetAnnotations()[keys.LogURL]; logURL != "" {
+			return logURL
+		}
+	}
+	return status.DetailsURL
 }
 
 func isPipelineRunCancelledOrStopped(run *tektonv1.PipelineRun) bool {
@@ -285,37 +628,80 @@ func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event,
 	var err error
 	now := time.Now()
 	switch status.Conclusion {
-	case "neutral":
-		status.Conclusion = "success" // We don't have a choice than setting as success, no pending here.
+	case "neutral", "skipped":
+		// there is no neutral state on the statuses API
+		switch v.Run.Info.Pac.ClassicStatusSkippedState {
+		case settings.ClassicStatusSkippedStatePending:
+			status.Conclusion = "pending"
+		case settings.ClassicStatusSkippedStateLabeled:
+			status.Conclusion = "success"
+			status.Title = strings.TrimSpace(status.Title + " (skipped)")
+		default:
+			status.Conclusion = "success"
+		}
 	case "pending":
 		if status.Title != "" {
 			status.Conclusion = "pending"
 		}
+	case "queued", "action_required":
+		status.Conclusion = "pending"
 	}
 	if status.Status == "in_progress" {
 		status.Conclusion = "pending"
 	}
 
+	description, truncated := formatting.TruncateRunes(status.Title, maxStatusDescriptionLength)
+	if truncated {
+		v.Logger.Debugf("truncating the description %q of the commit status to %d characters", status.Title, maxStatusDescriptionLength)
+	}
+	statusContext := getCheckName(v.Logger, status, v.Run.Info.Pac, runevent)
+	if shortened, truncated := formatting.TruncateRunes(statusContext, maxStatusContextLength); truncated {
+		v.Logger.Debugf("truncating the context %q of the commit status to %d characters", statusContext, maxStatusContextLength)
+		statusContext = shortened
+	}
 	ghstatus := &github.RepoStatus{
 		State:       github.String(status.Conclusion),
 		TargetURL:   github.String(status.DetailsURL),
-		Description: github.String(status.Title),
-		Context:     github.String(getCheckName(status, v.Run.Info.Pac)),
+		Description: github.String(description),
+		Context:     github.String(statusContext),
 		CreatedAt:   &github.Timestamp{Time: now},
 	}
 
-	if _, _, err := v.Client.Repositories.CreateStatus(ctx,
-		runevent.Organization, runevent.Repository, runevent.SHA, ghstatus); err != nil {
-		return err
+	dryRun := v.Run.Info.Pac.DryRun
+	if v.Run.Info.Pac.StatusGist && !dryRun && status.Status == "completed" && status.Text != "" {
+		if gistURL := v.createStatusGist(ctx, runevent, status, statusContext); gistURL != "" {
+			ghstatus.TargetURL = github.String(gistURL)
+		}
 	}
-	if (status.Status == "completed" || (status.Status == "queued" && status.Title == "Pending approval")) && status.Text != "" && runevent.EventType == triggertype.PullRequest.String() {
-		_, _, err = v.Client.Issues.CreateComment(ctx, runevent.Organization, runevent.Repository,
-			runevent.PullRequestNumber,
-			&github.IssueComment{
-				Body: github.String(fmt.Sprintf("%s<br>%s", status.Summary, status.Text)),
-			},
-		)
-		if err != nil {
+	if dryRun {
+		if err := v.logDryRun("commit status", ghstatus.GetContext(), ghstatus.GetState(), status.Summary, ghstatus); err != nil {
+			return err
+		}
+	} else {
+		for _, sha := range v.statusSHAs(ctx, runevent) {
+			start := time.Now()
+			_, _, err := v.repositories().CreateStatus(ctx,
+				runevent.Organization, runevent.Repository, sha, ghstatus)
+			v.recordAPILatency("CreateStatus", time.Since(start))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// when reported as a check run too, its output already has the details
+	// of the run and the pull request isn't commented on top of it
+	checkRun, _ := v.statusReporters(runevent)
+	if !checkRun && (status.Status == "completed" || isPendingApproval(status)) && status.Text != "" && runevent.EventType == triggertype.PullRequest.String() &&
+		shouldComment(v.Run.Info.Pac.CommentStrategy, status) {
+		if status.Conclusion == "failure" && v.Run.Info.Pac.CommentLogSnippet {
+			if logs := v.failedTaskLogs(ctx, status); logs != "" {
+				status.Text += "\n\n" + logs
+			}
+		}
+		if dryRun {
+			return v.logDryRun("pull request comment", ghstatus.GetContext(), ghstatus.GetState(), status.Summary, makeStatusComment(status))
+		}
+		if err = v.upsertStatusComment(ctx, runevent, status); err != nil {
 			return err
 		}
 	}
@@ -323,30 +709,101 @@ func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event,
 	return nil
 }
 
+// statusSHAs returns the commits to report the commit status on, the commit
+// of the event and with the head-and-merge status-sha-strategy the merge
+// commit of the pull request, when GitHub has computed it.
+func (v *Provider) statusSHAs(ctx context.Context, runevent *info.Event) []string {
+	shas := []string{runevent.SHA}
+	if v.Run.Info.Pac.StatusSHAStrategy != settings.StatusSHAStrategyHeadAndMerge ||
+		runevent.TriggerTarget != triggertype.PullRequest || runevent.PullRequestNumber == 0 ||
+		v.Client == nil {
+		return shas
+	}
+	pr, _, err := v.Client.PullRequests.Get(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber)
+	if err != nil {
+		v.Logger.Warnf("cannot get the merge commit of pull request %d, only reporting the status on %s: %v",
+			runevent.PullRequestNumber, runevent.SHA, err)
+		return shas
+	}
+	// the merge commit is computed asynchronously and not at all when the
+	// pull request has conflicts
+	mergeSHA := pr.GetMergeCommitSHA()
+	if mergeSHA == "" {
+		v.Logger.Infof("pull request %d has no merge commit, only reporting the status on %s",
+			runevent.PullRequestNumber, runevent.SHA)
+		return shas
+	}
+	if mergeSHA != runevent.SHA {
+		shas = append(shas, mergeSHA)
+	}
+	return shas
+}
+
+// isPendingApproval returns true for the status of a pull request waiting for
+// an /ok-to-test of an allowed user.
+func isPendingApproval(status provider.StatusOpts) bool {
+	return status.Status == "queued" && status.Title == "Pending approval"
+}
+
+// shouldComment returns if the status should be commented on the pull request
+// with the comment strategy of the settings, the comment of a pull request
+// pending approval is the only way to know why it is not tested so it is
+// kept on failure only.
+func shouldComment(strategy string, status provider.StatusOpts) bool {
+	switch strategy {
+	case settings.CommentStrategyDisableAll:
+		return false
+	case settings.CommentStrategyFailureOnly:
+		return status.Conclusion == "failure" || isPendingApproval(status)
+	}
+	return true
+}
+
 func (v *Provider) CreateStatus(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts) error {
-	if v.Client == nil {
-		return fmt.Errorf("cannot set status on github no token or url set")
+	if !v.hasStatusClients() {
+		return provider.NewStatusError(provider.ErrNoToken, fmt.Errorf("cannot set status on github no token or url set"))
+	}
+	statusOpts.CheckNameSuffix = v.checkNameSuffix(ctx, runevent, statusOpts)
+
+	// be strict on protected tags, only a success is a success
+	if provider.IsProtectedTag(runevent, v.Run.Info.Pac.ProtectedTags) &&
+		(statusOpts.Conclusion == "neutral" || statusOpts.Conclusion == "skipped") {
+		statusOpts.Conclusion = "failure"
+	}
+
+	// a PipelineRun waiting for a manual approval needs an action, not just
+	// more time, the check run is completed until it's approved
+	if statusOpts.ApprovalGate != "" {
+		statusOpts.Status = "completed"
+		statusOpts.Conclusion = "action_required"
 	}
 
 	switch statusOpts.Conclusion {
 	case "success":
-		statusOpts.Title = "Success"
+		statusOpts.Title = formatting.ConclusionTitle(statusOpts.Conclusion)
 		statusOpts.Summary = "has <b>successfully</b> validated your commit."
 	case "failure":
-		statusOpts.Title = "Failed"
+		statusOpts.Title = formatting.ConclusionTitle(statusOpts.Conclusion)
 		statusOpts.Summary = "has <b>failed</b>."
 	case "pending":
 		// for concurrency set title as pending
 		if statusOpts.Title == "" {
-			statusOpts.Title = "Pending"
+			statusOpts.Title = formatting.ConclusionTitle(statusOpts.Conclusion)
 			statusOpts.Summary = "is skipping this commit."
 		} else {
 			// for unauthorized user set title as Pending approval
 			statusOpts.Summary = "is waiting for approval."
 		}
 	case "neutral":
-		statusOpts.Title = "Unknown"
+		statusOpts.Title = formatting.ConclusionTitle(statusOpts.Conclusion)
 		statusOpts.Summary = "doesn't know what happened with this commit."
+	case "queued":
+		statusOpts.Status = "queued"
+		statusOpts.Title = queuedCheckRunTitle
+		statusOpts.Summary = "is queued."
+	case "action_required":
+		statusOpts.Title = formatting.ConclusionTitle(statusOpts.Conclusion)
+		statusOpts.Summary = fmt.Sprintf("is waiting for the approval of <b>%s</b>.", statusOpts.ApprovalGate)
 	}
 
 	if statusOpts.Status == "in_progress" {
@@ -358,13 +815,260 @@ func (v *Provider) CreateStatus(ctx context.Context, runevent *info.Event, statu
 	if statusOpts.OriginalPipelineRunName != "" {
 		onPr = "/" + statusOpts.OriginalPipelineRunName
 	}
-	statusOpts.Summary = fmt.Sprintf("%s%s %s", v.Run.Info.Pac.ApplicationName, onPr, statusOpts.Summary)
+	statusOpts.Summary = fmt.Sprintf("%s%s %s", getApplicationName(v.Run.Info.Pac, runevent), onPr, statusOpts.Summary)
+	if statusOpts.GraphURL != "" {
+		statusOpts.Summary += fmt.Sprintf("\n\n[view pipeline graph](%s)", statusOpts.GraphURL)
+	}
+	if statusOpts.Status == "completed" {
+		statusOpts.Summary += provider.FormatDurationBudget(statusOpts.PipelineRun, statusOpts.DurationBudget)
+	}
+	if statusOpts.Conclusion == "failure" {
+		if statusOpts.LastSuccessURL != "" {
+			statusOpts.Summary += fmt.Sprintf("\n\n[compare to last success](%s)", statusOpts.LastSuccessURL)
+		}
+		statusOpts.Summary += formatRemediations(statusOpts.FailureReasons, v.Run.Info.Pac.FailureRemediations)
+	}
+	if v.Run.Info.Pac.StatusPullRequestLabel && runevent.PullRequestNumber > 0 {
+		labels, err := v.getPullRequestLabels(ctx, runevent)
+		if err != nil {
+			v.Logger.Warnf("cannot get the labels of pull request %d: %v", runevent.PullRequestNumber, err)
+		} else if len(labels) > 0 {
+			statusOpts.Summary += fmt.Sprintf("\n\n**Labels:** `%s`", strings.Join(labels, "` `"))
+		}
+	}
+	statusOpts.Summary += provider.FormatParameters(statusOpts.Parameters)
+
+	logger := v.statusLogger(runevent, statusOpts)
+	if !v.claimStatus(ctx, logger, statusOpts) {
+		logger.Infof("status with idempotency token %s has already been posted, skipping", statusOpts.IdempotencyToken)
+		return nil
+	}
+	logger.Debugf("setting status %s of pipelinerun %s", statusOpts.Status, statusOpts.PipelineRunName)
+
+	var errs []error
+	checkRun, commitStatus := v.statusReporters(runevent)
+	if checkRun {
+		errs = append(errs, v.getOrUpdateCheckRunStatus(ctx, runevent, statusOpts))
+		if v.Run.Info.Pac.PerTaskChecks && statusOpts.PipelineRunName != "" {
+			errs = append(errs, v.updateTaskCheckRuns(ctx, runevent, statusOpts))
+		}
+	}
+	if commitStatus {
+		errs = append(errs, v.createStatusCommit(ctx, runevent, statusOpts))
+	}
+	err := statusError(errors.Join(errs...))
+	if v.Run.Info.Pac.AuditStatuses {
+		v.auditStatus(runevent, statusOpts, checkRun, commitStatus, err)
+	}
+
+	// the deployment is reported on top of the status, failing to report it
+	// doesn't fail the status
+	if derr := v.createDeploymentStatus(ctx, runevent, statusOpts); derr != nil {
+		logger.Warnf("cannot report the status of pipelinerun %s as a deployment to environment %s: %v",
+			statusOpts.PipelineRunName, deploymentEnvironment(statusOpts), derr)
+	}
+
+	v.releaseStatus(ctx, logger, statusOpts, err)
+
+	if v.Run.Info.Pac.StatusKubernetesEvent {
+		v.emitStatusEvent(statusOpts, err)
+	}
+	return err
+}
+
+// Prefixes of the personal access tokens of GitHub, the installation tokens
+// of the GitHub Apps are prefixed with "ghs_".
+// https://github.blog/2021-04-05-behind-githubs-new-authentication-token-formats/
+const (
+	classicPATPrefix     = "ghp_"
+	fineGrainedPATPrefix = "github_pat_"
+)
+
+var legacyPATRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// isPersonalAccessToken detects a personal access token, classic or
+// fine-grained, from the prefix GitHub gives them. The tokens from before the
+// prefixes were introduced are 40 hexadecimal characters, which the
+// installation tokens never were, so they are detected as personal access
+// tokens too.
+func isPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, classicPATPrefix) ||
+		strings.HasPrefix(token, fineGrainedPATPrefix) ||
+		legacyPATRegexp.MatchString(token)
+}
+
+// UseCheckRuns returns if the statuses of the event can be reported with the
+// check runs, it requires an installation token of a GitHub App, otherwise
+// the classic statuses are used, i.e: with a personal access token from the
+// Repository secret.
+func UseCheckRuns(runevent *info.Event) bool {
+	if runevent.InstallationID <= 0 {
+		return false
+	}
+	return runevent.Provider == nil || !isPersonalAccessToken(runevent.Provider.Token)
+}
 
-	// If we have an installationID which mean we have a github apps and we can use the checkRun API
-	if runevent.InstallationID > 0 {
-		return v.getOrUpdateCheckRunStatus(ctx, runevent, statusOpts)
+// ReportsCheckRuns returns whether the statuses of the event are reported as
+// check runs.
+func (v *Provider) ReportsCheckRuns(runevent *info.Event) bool {
+	checkRun, _ := v.statusReporters(runevent)
+	return checkRun
+}
+
+// statusReporters returns whether the statuses of the event are reported as
+// check runs, as commit statuses or as both with the status-mode setting. The
+// check runs need an app installation token, without one the status is
+// always reported as a commit status.
+func (v *Provider) statusReporters(runevent *info.Event) (checkRun, commitStatus bool) {
+	if !UseCheckRuns(runevent) {
+		return false, true
+	}
+	if v.Run == nil || v.Run.Info.Pac == nil {
+		return true, false
+	}
+	switch v.Run.Info.Pac.StatusMode {
+	case settings.StatusModeCommitStatus:
+		return false, true
+	case settings.StatusModeBoth:
+		return true, true
+	default:
+		return true, false
 	}
+}
 
-	// Otherwise use the update status commit API
-	return v.createStatusCommit(ctx, runevent, statusOpts)
+// statusLogger returns the logger with the fields to correlate the status
+// updates of a PipelineRun.
+func (v *Provider) statusLogger(runevent *info.Event, statusOpts provider.StatusOpts) *zap.SugaredLogger {
+	logger := v.Logger
+	if logger == nil {
+		logger = zap.NewNop().Sugar()
+	}
+	return logger.With(
+		"organization", runevent.Organization,
+		"repository", runevent.Repository,
+		"event-sha", runevent.SHA,
+		"pipeline-run", statusOpts.PipelineRunName,
+		"conclusion", statusOpts.Conclusion,
+	)
+}
+
+// formatRemediations renders the remediations matching the failure reasons as
+// a "what to do next" markdown list.
+func formatRemediations(reasons []string, remediations map[string]string) string {
+	var sb strings.Builder
+	seen := map[string]bool{}
+	for _, reason := range reasons {
+		remediation, ok := remediations[reason]
+		if !ok || seen[reason] {
+			continue
+		}
+		seen[reason] = true
+		if sb.Len() == 0 {
+			sb.WriteString("\n\n**What to do next:**\n")
+		}
+		sb.WriteString(fmt.Sprintf("* `%s`: %s\n", reason, remediation))
+	}
+	return sb.String()
+}
+
+// getPullRequestLabels gets the labels of the pull request, they are cached on
+// the event so they are only fetched once for all the statuses of the event.
+func (v *Provider) getPullRequestLabels(ctx context.Context, runevent *info.Event) ([]string, error) {
+	if runevent.PullRequestLabel != nil {
+		return runevent.PullRequestLabel, nil
+	}
+	labels := []string{}
+	opt := &github.ListOptions{PerPage: v.paginedNumber}
+	for {
+		ghLabels, resp, err := v.Client.Issues.ListLabelsByIssue(ctx, runevent.Organization, runevent.Repository,
+			runevent.PullRequestNumber, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range ghLabels {
+			labels = append(labels, label.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	runevent.PullRequestLabel = labels
+	return labels, nil
+}
+
+// postedStatuses remembers the idempotency tokens of the statuses posted by
+// all the providers of the controller.
+var postedStatuses = provider.NewIdempotencyCache(clockwork.NewRealClock(), provider.IdempotencyTTL)
+
+func (v *Provider) statusCache() *provider.IdempotencyCache {
+	if v.postedStatuses != nil {
+		return v.postedStatuses
+	}
+	return postedStatuses
+}
+
+// claimStatus returns false when the status with the idempotency token has
+// already been posted. The token is recorded on the PipelineRun so it is
+// remembered across restarts of the controller, the statuses without a
+// PipelineRun only get remembered in memory.
+func (v *Provider) claimStatus(ctx context.Context, logger *zap.SugaredLogger, statusOpts provider.StatusOpts) bool {
+	token := statusOpts.IdempotencyToken
+	if token == "" {
+		return true
+	}
+	if statusOpts.PipelineRun == nil {
+		return !v.statusCache().Seen(token)
+	}
+	claimed, err := provider.ClaimIdempotencyToken(ctx, v.Run.Clients.Tekton, statusOpts.PipelineRun, token, time.Now())
+	if err != nil {
+		// better a duplicate status than a missing one
+		logger.Warnf("cannot check if the status has already been posted: %v", err)
+		return true
+	}
+	return claimed
+}
+
+// releaseStatus remembers the idempotency token of a posted status, or
+// releases the token claimed on the PipelineRun when it could not be posted
+// so it can be retried.
+func (v *Provider) releaseStatus(ctx context.Context, logger *zap.SugaredLogger, statusOpts provider.StatusOpts, err error) {
+	token := statusOpts.IdempotencyToken
+	if token == "" {
+		return
+	}
+	if statusOpts.PipelineRun == nil {
+		if err == nil {
+			v.statusCache().Remember(token)
+		}
+		return
+	}
+	if err == nil {
+		return
+	}
+	if rerr := provider.ReleaseIdempotencyToken(ctx, v.Run.Clients.Tekton, statusOpts.PipelineRun, token); rerr != nil {
+		logger.Warnf("cannot release the idempotency token of the status, it will not be retried: %v", rerr)
+	}
+}
+
+// emitStatusEvent emits a Kubernetes event on the Repository mirroring the
+// status we are reporting to GitHub, so there is a local record of it even when
+// GitHub is not reachable.
+func (v *Provider) emitStatusEvent(statusOpts provider.StatusOpts, err error) {
+	if v.eventEmitter == nil {
+		return
+	}
+	conclusion := statusOpts.Conclusion
+	if conclusion == "" {
+		conclusion = statusOpts.Status
+	}
+	msg := fmt.Sprintf("PipelineRun %s status is %s: %s", statusOpts.PipelineRunName, conclusion, statusOpts.Summary)
+	level := zap.InfoLevel
+	if err != nil {
+		msg = fmt.Sprintf("%s (failed to report status to GitHub: %v)", msg, err)
+		level = zap.WarnLevel
+	} else if conclusion == "failure" {
+		level = zap.WarnLevel
+	}
+	v.eventEmitter.EmitMessage(v.repo, level, "PipelineRunStatus", msg)
 }
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"knative.dev/pkg/apis"
+)
+
+// taskCheckRunStatus maps the condition of a TaskRun to the status and the
+// conclusion of its check run.
+func taskCheckRunStatus(taskRunStatus *tektonv1.PipelineRunTaskRunStatus) (string, string) {
+	if taskRunStatus.Status == nil {
+		return "queued", ""
+	}
+	cond := taskRunStatus.Status.GetCondition(apis.ConditionSucceeded)
+	switch {
+	case cond == nil:
+		return "queued", ""
+	case cond.IsUnknown():
+		return "in_progress", ""
+	case cond.IsTrue():
+		return "completed", "success"
+	case cond.Reason == tektonv1.TaskRunReasonCancelled.String():
+		return "completed", "cancelled"
+	case cond.Reason == tektonv1.TaskRunReasonTimedOut.String():
+		return "completed", "timed_out"
+	}
+	return "completed", "failure"
+}
+
+// makeTaskCheckRunOptions makes the check run of a TaskRun, named after the
+// check run of the PipelineRun and the task.
+func (v *Provider) makeTaskCheckRunOptions(runevent *info.Event, statusOpts provider.StatusOpts, taskRunName string, taskRunStatus *tektonv1.PipelineRunTaskRunStatus) github.CreateCheckRunOptions {
+	status, conclusion := taskCheckRunStatus(taskRunStatus)
+	opts := github.CreateCheckRunOptions{
+		Name:       fmt.Sprintf("%s / %s", getCheckName(v.Logger, statusOpts, v.Run.Info.Pac, runevent), taskRunStatus.PipelineTaskName),
+		HeadSHA:    runevent.SHA,
+		ExternalID: github.String(taskRunName),
+		DetailsURL: github.String(statusOpts.DetailsURL),
+		Status:     github.String(status),
+		Output: makeCheckRunOutput(status,
+			fmt.Sprintf("Task %s of PipelineRun %s", taskRunStatus.PipelineTaskName, statusOpts.PipelineRunName), "", ""),
+	}
+	if statusOpts.PipelineRun != nil && v.Run.Clients.ConsoleUI != nil {
+		opts.DetailsURL = github.String(v.Run.Clients.ConsoleUI.TaskLogURL(statusOpts.PipelineRun, taskRunStatus))
+	}
+	if conclusion != "" {
+		opts.Conclusion = github.String(conclusion)
+	}
+	if taskRunStatus.Status == nil {
+		return opts
+	}
+	if cond := taskRunStatus.Status.GetCondition(apis.ConditionSucceeded); cond != nil {
+		opts.Output.Title = github.String(cond.Reason)
+		if cond.Message != "" {
+			opts.Output.Summary = github.String(cond.Message)
+		}
+	}
+	if taskRunStatus.Status.StartTime != nil {
+		opts.StartedAt = &github.Timestamp{Time: taskRunStatus.Status.StartTime.Time}
+	}
+	if taskRunStatus.Status.CompletionTime != nil && status == "completed" {
+		opts.CompletedAt = &github.Timestamp{Time: taskRunStatus.Status.CompletionTime.Time}
+	}
+	return opts
+}
+
+// listTaskCheckRunIDs gets the ids of the check runs already created for the
+// TaskRuns, i.e: on a previous status of the PipelineRun or by the controller
+// before it restarted.
+func (v *Provider) listTaskCheckRunIDs(ctx context.Context, runevent *info.Event, taskRunStatuses map[string]*tektonv1.PipelineRunTaskRunStatus) (map[string]int64, error) {
+	ids := map[string]int64{}
+	opt := github.ListOptions{PerPage: v.paginedNumber}
+	for {
+		res, resp, err := v.checks().ListCheckRunsForRef(ctx, runevent.Organization, runevent.Repository,
+			runevent.SHA, &github.ListCheckRunsOptions{
+				AppID:       v.ApplicationID,
+				ListOptions: opt,
+			})
+		if err != nil {
+			return nil, err
+		}
+		for _, checkrun := range res.CheckRuns {
+			if _, ok := taskRunStatuses[checkrun.GetExternalID()]; ok {
+				ids[checkrun.GetExternalID()] = checkrun.GetID()
+			}
+		}
+		if resp.NextPage == 0 {
+			return ids, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// updateTaskCheckRuns creates or updates a check run for every TaskRun of the
+// status.
+func (v *Provider) updateTaskCheckRuns(ctx context.Context, runevent *info.Event, statusOpts provider.StatusOpts) error {
+	if len(statusOpts.TaskRunStatuses) == 0 {
+		return nil
+	}
+
+	taskRunNames := make([]string, 0, len(statusOpts.TaskRunStatuses))
+	for taskRunName, taskRunStatus := range statusOpts.TaskRunStatuses {
+		if taskRunStatus != nil {
+			taskRunNames = append(taskRunNames, taskRunName)
+		}
+	}
+	sort.Strings(taskRunNames)
+
+	if v.Run.Info.Pac.DryRun {
+		for _, taskRunName := range taskRunNames {
+			opts := v.makeTaskCheckRunOptions(runevent, statusOpts, taskRunName, statusOpts.TaskRunStatuses[taskRunName])
+			if err := v.logDryRun("check run", opts.Name, opts.GetConclusion(), opts.Output.GetSummary(), opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ids, err := v.listTaskCheckRunIDs(ctx, runevent, statusOpts.TaskRunStatuses)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, taskRunName := range taskRunNames {
+		opts := v.makeTaskCheckRunOptions(runevent, statusOpts, taskRunName, statusOpts.TaskRunStatuses[taskRunName])
+		if id, ok := ids[taskRunName]; ok {
+			start := time.Now()
+			_, _, err := v.checks().UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, id, github.UpdateCheckRunOptions{
+				Name:        opts.Name,
+				ExternalID:  opts.ExternalID,
+				DetailsURL:  opts.DetailsURL,
+				Status:      opts.Status,
+				Conclusion:  opts.Conclusion,
+				CompletedAt: opts.CompletedAt,
+				Output:      opts.Output,
+			})
+			v.recordAPILatency("UpdateCheckRun", time.Since(start))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("cannot update check run %s: %w", opts.Name, err))
+			}
+			continue
+		}
+		start := time.Now()
+		_, _, err := v.checks().CreateCheckRun(ctx, runevent.Organization, runevent.Repository, opts)
+		v.recordAPILatency("CreateCheckRun", time.Since(start))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot create check run %s: %w", opts.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v59/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func taskRunStatusWithCondition(pipelineTask string, status corev1.ConditionStatus, reason string) *tektonv1.PipelineRunTaskRunStatus {
+	return &tektonv1.PipelineRunTaskRunStatus{
+		PipelineTaskName: pipelineTask,
+		Status: &tektonv1.TaskRunStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{{
+				Type:   apis.ConditionSucceeded,
+				Status: status,
+				Reason: reason,
+			}}},
+		},
+	}
+}
+
+func TestTaskCheckRunStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		taskRunStatus  *tektonv1.PipelineRunTaskRunStatus
+		wantStatus     string
+		wantConclusion string
+	}{
+		{
+			name:          "no status",
+			taskRunStatus: &tektonv1.PipelineRunTaskRunStatus{PipelineTaskName: "lint"},
+			wantStatus:    "queued",
+		},
+		{
+			name:          "running",
+			taskRunStatus: taskRunStatusWithCondition("lint", corev1.ConditionUnknown, "Running"),
+			wantStatus:    "in_progress",
+		},
+		{
+			name:           "succeeded",
+			taskRunStatus:  taskRunStatusWithCondition("lint", corev1.ConditionTrue, "Succeeded"),
+			wantStatus:     "completed",
+			wantConclusion: "success",
+		},
+		{
+			name:           "failed",
+			taskRunStatus:  taskRunStatusWithCondition("lint", corev1.ConditionFalse, "Failed"),
+			wantStatus:     "completed",
+			wantConclusion: "failure",
+		},
+		{
+			name:           "cancelled",
+			taskRunStatus:  taskRunStatusWithCondition("lint", corev1.ConditionFalse, tektonv1.TaskRunReasonCancelled.String()),
+			wantStatus:     "completed",
+			wantConclusion: "cancelled",
+		},
+		{
+			name:           "timed out",
+			taskRunStatus:  taskRunStatusWithCondition("lint", corev1.ConditionFalse, tektonv1.TaskRunReasonTimedOut.String()),
+			wantStatus:     "completed",
+			wantConclusion: "timed_out",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, conclusion := taskCheckRunStatus(tt.taskRunStatus)
+			assert.Equal(t, status, tt.wantStatus)
+			assert.Equal(t, conclusion, tt.wantConclusion)
+		})
+	}
+}
+
+func TestCreateStatusPerTaskChecks(t *testing.T) {
+	event := &info.Event{
+		Organization:   "owner",
+		Repository:     "repo",
+		SHA:            "sha",
+		InstallationID: 1,
+	}
+	// the check run of the PipelineRun already exists, so the PipelineRun
+	// doesn't get patched with its id
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pr-abcde",
+		Annotations: map[string]string{keys.CheckRunID: "1"},
+	}}
+	running := provider.StatusOpts{
+		Status:                  "in_progress",
+		Conclusion:              "pending",
+		PipelineRun:             pr,
+		PipelineRunName:         "pr-abcde",
+		OriginalPipelineRunName: "pr",
+		TaskRunStatuses: map[string]*tektonv1.PipelineRunTaskRunStatus{
+			"pr-abcde-lint": taskRunStatusWithCondition("lint", corev1.ConditionTrue, "Succeeded"),
+			"pr-abcde-unit": taskRunStatusWithCondition("unit", corev1.ConditionUnknown, "Running"),
+		},
+	}
+	completed := running
+	completed.Status, completed.Conclusion = "completed", "failure"
+	completed.TaskRunStatuses = map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"pr-abcde-lint": taskRunStatusWithCondition("lint", corev1.ConditionTrue, "Succeeded"),
+		"pr-abcde-unit": taskRunStatusWithCondition("unit", corev1.ConditionFalse, "Failed"),
+	}
+
+	t.Run("created then updated", func(t *testing.T) {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		v, checks, _, _ := newFakeProvider()
+		v.Run.Info.Pac.PerTaskChecks = true
+		checks.AddCheckRun("owner", "repo", &github.CheckRun{ID: github.Int64(1), Name: github.String("Pipelines as Code CI / pr"), HeadSHA: github.String("sha")})
+
+		assert.NilError(t, v.CreateStatus(ctx, event, running))
+		assert.Equal(t, len(checks.CheckRuns), 3)
+		assert.Equal(t, checks.CheckRuns[1].GetName(), "Pipelines as Code CI / pr / lint")
+		assert.Equal(t, checks.CheckRuns[1].GetExternalID(), "pr-abcde-lint")
+		assert.Equal(t, checks.CheckRuns[1].GetConclusion(), "success")
+		assert.Equal(t, checks.CheckRuns[2].GetName(), "Pipelines as Code CI / pr / unit")
+		assert.Equal(t, checks.CheckRuns[2].GetExternalID(), "pr-abcde-unit")
+		assert.Equal(t, checks.CheckRuns[2].GetStatus(), "in_progress")
+
+		assert.NilError(t, v.CreateStatus(ctx, event, completed))
+		assert.Equal(t, len(checks.CheckRuns), 3, "the check runs of the tasks should have been updated")
+		assert.Equal(t, checks.CheckRuns[2].GetStatus(), "completed")
+		assert.Equal(t, checks.CheckRuns[2].GetConclusion(), "failure")
+		// the check run of the PipelineRun is still reported
+		assert.Equal(t, len(checks.Updates[1]), 2)
+		assert.Equal(t, checks.Updates[1][1].GetConclusion(), "failure")
+	})
+
+	t.Run("existing check runs after a restart", func(t *testing.T) {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		v, checks, _, _ := newFakeProvider()
+		v.Run.Info.Pac.PerTaskChecks = true
+		checks.AddCheckRun("owner", "repo", &github.CheckRun{ID: github.Int64(1), Name: github.String("Pipelines as Code CI / pr"), HeadSHA: github.String("sha")})
+		checks.AddCheckRun("owner", "repo", &github.CheckRun{ID: github.Int64(2), ExternalID: github.String("pr-abcde-lint"), HeadSHA: github.String("sha")})
+		checks.AddCheckRun("owner", "repo", &github.CheckRun{ID: github.Int64(3), ExternalID: github.String("pr-abcde-unit"), HeadSHA: github.String("sha")})
+
+		assert.NilError(t, v.CreateStatus(ctx, event, completed))
+		assert.Equal(t, len(checks.CheckRuns), 3)
+		assert.Equal(t, len(checks.Updates[2]), 1)
+		assert.Equal(t, len(checks.Updates[3]), 1)
+		assert.Equal(t, checks.CheckRuns[2].GetConclusion(), "failure")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		v, checks, _, _ := newFakeProvider()
+		checks.AddCheckRun("owner", "repo", &github.CheckRun{ID: github.Int64(1), Name: github.String("Pipelines as Code CI / pr"), HeadSHA: github.String("sha")})
+
+		assert.NilError(t, v.CreateStatus(ctx, event, completed))
+		assert.Equal(t, len(checks.CheckRuns), 1)
+	})
+}
@@ -0,0 +1,387 @@
+// Package testing has in-memory fakes of the GitHub APIs used by the GitHub
+// provider to report the statuses, to test them without a GitHub server.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v59/github"
+)
+
+func okResponse() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+}
+
+// CheckRun is a check run created with the fake checks API.
+type CheckRun struct {
+	Owner, Repo string
+	*github.CheckRun
+}
+
+// Checks is an in-memory fake of the checks API of GitHub, safe for
+// concurrent use.
+type Checks struct {
+	mutex  sync.Mutex
+	nextID int64
+	// CheckRuns are the check runs, in creation order.
+	CheckRuns []*CheckRun
+	// Updates are the options of the updates of the check runs by id, in
+	// order.
+	Updates map[int64][]github.UpdateCheckRunOptions
+	// Err is returned by all the calls when set.
+	Err error
+}
+
+func (c *Checks) find(owner, repo string, checkRunID int64) *CheckRun {
+	for _, checkRun := range c.CheckRuns {
+		if checkRun.Owner == owner && checkRun.Repo == repo && checkRun.GetID() == checkRunID {
+			return checkRun
+		}
+	}
+	return nil
+}
+
+// AddCheckRun adds an existing check run, its id is set when not set.
+func (c *Checks) AddCheckRun(owner, repo string, checkRun *github.CheckRun) *github.CheckRun {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if checkRun.ID == nil {
+		c.nextID++
+		checkRun.ID = github.Int64(c.nextID)
+	} else if checkRun.GetID() > c.nextID {
+		c.nextID = checkRun.GetID()
+	}
+	c.CheckRuns = append(c.CheckRuns, &CheckRun{Owner: owner, Repo: repo, CheckRun: checkRun})
+	return checkRun
+}
+
+// ListCheckRunsForRef lists the check runs of a SHA filtered by app, name
+// and status, all of them on a single page.
+func (c *Checks) ListCheckRunsForRef(_ context.Context, owner, repo, ref string, opts *github.ListCheckRunsOptions) (*github.ListCheckRunsResults, *github.Response, error) {
+	if c.Err != nil {
+		return nil, nil, c.Err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if opts == nil {
+		opts = &github.ListCheckRunsOptions{}
+	}
+	results := &github.ListCheckRunsResults{CheckRuns: []*github.CheckRun{}}
+	for _, checkRun := range c.CheckRuns {
+		switch {
+		case checkRun.Owner != owner, checkRun.Repo != repo, checkRun.GetHeadSHA() != ref,
+			opts.CheckName != nil && checkRun.GetName() != opts.GetCheckName(),
+			opts.Status != nil && checkRun.GetStatus() != opts.GetStatus(),
+			opts.AppID != nil && checkRun.GetApp().GetID() != opts.GetAppID():
+			continue
+		}
+		results.CheckRuns = append(results.CheckRuns, checkRun.CheckRun)
+	}
+	results.Total = github.Int(len(results.CheckRuns))
+	return results, okResponse(), nil
+}
+
+// CreateCheckRun creates a check run.
+func (c *Checks) CreateCheckRun(_ context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	if c.Err != nil {
+		return nil, nil, c.Err
+	}
+	checkRun := c.AddCheckRun(owner, repo, &github.CheckRun{
+		Name:        github.String(opts.Name),
+		HeadSHA:     github.String(opts.HeadSHA),
+		ExternalID:  opts.ExternalID,
+		DetailsURL:  opts.DetailsURL,
+		Status:      opts.Status,
+		Conclusion:  opts.Conclusion,
+		StartedAt:   opts.StartedAt,
+		CompletedAt: opts.CompletedAt,
+		Output:      opts.Output,
+	})
+	return checkRun, okResponse(), nil
+}
+
+// UpdateCheckRun records the update and applies it to the check run, it
+// fails with a 404 when the check run doesn't exist.
+func (c *Checks) UpdateCheckRun(_ context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	if c.Err != nil {
+		return nil, nil, c.Err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	checkRun := c.find(owner, repo, checkRunID)
+	if checkRun == nil {
+		return nil, notFound(), fmt.Errorf("check run %d of %s/%s not found", checkRunID, owner, repo)
+	}
+	if c.Updates == nil {
+		c.Updates = map[int64][]github.UpdateCheckRunOptions{}
+	}
+	c.Updates[checkRunID] = append(c.Updates[checkRunID], opts)
+
+	checkRun.Name = github.String(opts.Name)
+	if opts.ExternalID != nil {
+		checkRun.ExternalID = opts.ExternalID
+	}
+	if opts.DetailsURL != nil {
+		checkRun.DetailsURL = opts.DetailsURL
+	}
+	if opts.Status != nil {
+		checkRun.Status = opts.Status
+	}
+	if opts.Conclusion != nil {
+		checkRun.Conclusion = opts.Conclusion
+		checkRun.Status = github.String("completed")
+	}
+	if opts.CompletedAt != nil {
+		checkRun.CompletedAt = opts.CompletedAt
+	}
+	if opts.Output != nil {
+		checkRun.Output = opts.Output
+	}
+	return checkRun.CheckRun, okResponse(), nil
+}
+
+// Status is a commit status created with the fake repositories API.
+type Status struct {
+	Owner, Repo, Ref string
+	*github.RepoStatus
+}
+
+// Deployment is a deployment created with the fake repositories API.
+type Deployment struct {
+	Owner, Repo string
+	*github.Deployment
+	// Statuses are the statuses of the deployment, in creation order.
+	Statuses []*github.DeploymentStatus
+}
+
+// Repositories is an in-memory fake of the commit statuses and deployments of
+// the repositories API of GitHub, safe for concurrent use.
+type Repositories struct {
+	mutex sync.Mutex
+	// Statuses are the commit statuses, in creation order.
+	Statuses []*Status
+	// Deployments are the deployments, in creation order.
+	Deployments []*Deployment
+	// PrivateRepositories are the "owner/repo" of the private repositories,
+	// the other ones are public.
+	PrivateRepositories map[string]bool
+	// Err is returned by all the calls when set.
+	Err error
+}
+
+// Get returns a repository, private when it's in PrivateRepositories.
+func (r *Repositories) Get(_ context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return &github.Repository{
+		Name:     github.String(repo),
+		FullName: github.String(owner + "/" + repo),
+		Private:  github.Bool(r.PrivateRepositories[owner+"/"+repo]),
+	}, okResponse(), nil
+}
+
+// CreateStatus creates a commit status.
+func (r *Repositories) CreateStatus(_ context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	created := *status
+	created.ID = github.Int64(int64(len(r.Statuses) + 1))
+	created.CreatedAt = &github.Timestamp{Time: time.Now()}
+	r.Statuses = append(r.Statuses, &Status{Owner: owner, Repo: repo, Ref: ref, RepoStatus: &created})
+	return &created, okResponse(), nil
+}
+
+// ListDeployments lists the deployments filtered by SHA, ref, task and
+// environment, all of them on a single page.
+func (r *Repositories) ListDeployments(_ context.Context, owner, repo string, opts *github.DeploymentsListOptions) ([]*github.Deployment, *github.Response, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if opts == nil {
+		opts = &github.DeploymentsListOptions{}
+	}
+	deployments := []*github.Deployment{}
+	for _, deployment := range r.Deployments {
+		switch {
+		case deployment.Owner != owner, deployment.Repo != repo,
+			opts.SHA != "" && deployment.GetSHA() != opts.SHA,
+			opts.Ref != "" && deployment.GetRef() != opts.Ref,
+			opts.Task != "" && deployment.GetTask() != opts.Task,
+			opts.Environment != "" && deployment.GetEnvironment() != opts.Environment:
+			continue
+		}
+		deployments = append(deployments, deployment.Deployment)
+	}
+	return deployments, okResponse(), nil
+}
+
+// CreateDeployment creates a deployment of a SHA, the ref of the request is
+// used as the SHA.
+func (r *Repositories) CreateDeployment(_ context.Context, owner, repo string, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	deployment := &github.Deployment{
+		ID:          github.Int64(int64(len(r.Deployments) + 1)),
+		SHA:         request.Ref,
+		Ref:         request.Ref,
+		Task:        request.Task,
+		Environment: request.Environment,
+		Description: request.Description,
+	}
+	r.Deployments = append(r.Deployments, &Deployment{Owner: owner, Repo: repo, Deployment: deployment})
+	return deployment, okResponse(), nil
+}
+
+// CreateDeploymentStatus creates a status of a deployment, it fails with a
+// 404 when the deployment doesn't exist.
+func (r *Repositories) CreateDeploymentStatus(_ context.Context, owner, repo string, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, deployment := range r.Deployments {
+		if deployment.Owner == owner && deployment.Repo == repo && deployment.GetID() == deploymentID {
+			status := &github.DeploymentStatus{
+				ID:          github.Int64(int64(len(deployment.Statuses) + 1)),
+				State:       request.State,
+				Description: request.Description,
+				Environment: request.Environment,
+				LogURL:      request.LogURL,
+			}
+			deployment.Statuses = append(deployment.Statuses, status)
+			return status, okResponse(), nil
+		}
+	}
+	return nil, notFound(), fmt.Errorf("deployment %d of %s/%s not found", deploymentID, owner, repo)
+}
+
+// Comment is a comment of a pull request created with the fake issues API.
+type Comment struct {
+	Owner, Repo string
+	Number      int
+	*github.IssueComment
+}
+
+// Issues is an in-memory fake of the comments of the issues API of GitHub,
+// safe for concurrent use.
+type Issues struct {
+	mutex sync.Mutex
+	// Comments are the comments, in creation order.
+	Comments []*Comment
+	// Err is returned by all the calls when set.
+	Err error
+}
+
+// ListComments lists the comments of a pull request, all of them on a single
+// page.
+func (i *Issues) ListComments(_ context.Context, owner, repo string, number int, _ *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	if i.Err != nil {
+		return nil, nil, i.Err
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	comments := []*github.IssueComment{}
+	for _, comment := range i.Comments {
+		if comment.Owner == owner && comment.Repo == repo && comment.Number == number {
+			comments = append(comments, comment.IssueComment)
+		}
+	}
+	return comments, okResponse(), nil
+}
+
+// CreateComment creates a comment on a pull request.
+func (i *Issues) CreateComment(_ context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	if i.Err != nil {
+		return nil, nil, i.Err
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	created := *comment
+	created.ID = github.Int64(int64(len(i.Comments) + 1))
+	i.Comments = append(i.Comments, &Comment{Owner: owner, Repo: repo, Number: number, IssueComment: &created})
+	return &created, okResponse(), nil
+}
+
+// EditComment replaces the body of a comment, it fails with a 404 when the
+// comment doesn't exist.
+func (i *Issues) EditComment(_ context.Context, owner, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	if i.Err != nil {
+		return nil, nil, i.Err
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, existing := range i.Comments {
+		if existing.Owner == owner && existing.Repo == repo && existing.GetID() == commentID {
+			existing.Body = comment.Body
+			return existing.IssueComment, okResponse(), nil
+		}
+	}
+	return nil, notFound(), fmt.Errorf("comment %d of %s/%s not found", commentID, owner, repo)
+}
+
+// Gists is an in-memory fake of the gists API of GitHub, safe for concurrent
+// use.
+type Gists struct {
+	mutex sync.Mutex
+	// Gists are the gists, in creation order.
+	Gists []*github.Gist
+	// Edits counts the edits of the gists.
+	Edits int
+	// Err is returned by all the calls when set.
+	Err error
+}
+
+// Create creates a gist, its URL is https://gist.github.com/<id>.
+func (g *Gists) Create(_ context.Context, gist *github.Gist) (*github.Gist, *github.Response, error) {
+	if g.Err != nil {
+		return nil, nil, g.Err
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	created := *gist
+	created.ID = github.String(fmt.Sprint(len(g.Gists) + 1))
+	created.HTMLURL = github.String("https://gist.github.com/" + created.GetID())
+	g.Gists = append(g.Gists, &created)
+	return &created, okResponse(), nil
+}
+
+// Edit replaces the description and the files of a gist, it is not found
+// when it hasn't been created.
+func (g *Gists) Edit(_ context.Context, id string, gist *github.Gist) (*github.Gist, *github.Response, error) {
+	if g.Err != nil {
+		return nil, nil, g.Err
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, existing := range g.Gists {
+		if existing.GetID() != id {
+			continue
+		}
+		existing.Description = gist.Description
+		existing.Files = gist.Files
+		g.Edits++
+		edited := *existing
+		return &edited, okResponse(), nil
+	}
+	return nil, notFound(), fmt.Errorf("404 Not Found")
+}
+
+func notFound() *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// transportKey is the settings a transport has been built from.
+type transportKey struct {
+	httpsProxy   string
+	noProxy      string
+	caBundlePath string
+}
+
+// transports keeps the transports built by NewTransport, so their connections
+// are reused and the CA bundle is not read again on every request.
+var transports = struct {
+	mutex sync.Mutex
+	byKey map[transportKey]http.RoundTripper
+}{byKey: map[transportKey]http.RoundTripper{}}
+
+// NewTransport returns the transport to use for the requests made to GitHub
+// for the GitHub App authentication, configured with the proxy and CA bundle
+// from the settings. http.DefaultTransport is returned when none are set.
+// The transport is built once and reused as long as the settings are the same.
+func NewTransport(pacOpts *info.PacOpts) (http.RoundTripper, error) {
+	if pacOpts == nil || pacOpts.Settings == nil ||
+		(pacOpts.GitHubHTTPSProxy == "" && pacOpts.GitHubNoProxy == "" && pacOpts.GitHubCABundlePath == "") {
+		return http.DefaultTransport, nil
+	}
+
+	key := transportKey{
+		httpsProxy:   pacOpts.GitHubHTTPSProxy,
+		noProxy:      pacOpts.GitHubNoProxy,
+		caBundlePath: pacOpts.GitHubCABundlePath,
+	}
+	transports.mutex.Lock()
+	defer transports.mutex.Unlock()
+	if tr, ok := transports.byKey[key]; ok {
+		return tr, nil
+	}
+	tr, err := newTransport(pacOpts)
+	if err != nil {
+		return nil, err
+	}
+	transports.byKey[key] = tr
+	return tr, nil
+}
+
+func newTransport(pacOpts *info.PacOpts) (http.RoundTripper, error) {
+	tr, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("default transport is not an http.Transport")
+	}
+	tr = tr.Clone()
+
+	proxy := http.ProxyFromEnvironment
+	if pacOpts.GitHubHTTPSProxy != "" {
+		proxyURL, err := url.Parse(pacOpts.GitHubHTTPSProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid github https proxy %s: %w", pacOpts.GitHubHTTPSProxy, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	noProxy := strings.Split(pacOpts.GitHubNoProxy, ",")
+	tr.Proxy = func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+
+	if pacOpts.GitHubCABundlePath != "" {
+		pem, err := os.ReadFile(pacOpts.GitHubCABundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read github ca bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in github ca bundle %s", pacOpts.GitHubCABundlePath)
+		}
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return tr, nil
+}
+
+// matchNoProxy checks if host is matched by one of the no proxy entries, an
+// entry matches the host itself and its subdomains, "*" matches everything.
+func matchNoProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewTransportDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		pacOpts *info.PacOpts
+	}{
+		{
+			name: "no pac opts",
+		},
+		{
+			name:    "no settings",
+			pacOpts: &info.PacOpts{},
+		},
+		{
+			name:    "nothing set",
+			pacOpts: &info.PacOpts{Settings: &settings.Settings{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := NewTransport(tt.pacOpts)
+			assert.NilError(t, err)
+			assert.Assert(t, tr == http.DefaultTransport)
+		})
+	}
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{
+		GitHubHTTPSProxy: "http://proxy.corp:3128",
+		GitHubNoProxy:    "localhost, .internal.corp",
+	}}
+	tr, err := NewTransport(pacOpts)
+	assert.NilError(t, err)
+	httpTr, ok := tr.(*http.Transport)
+	assert.Assert(t, ok)
+
+	tests := []struct {
+		url       string
+		wantProxy string
+	}{
+		{url: "https://ghe.example.com/api/v3/app/installations", wantProxy: "http://proxy.corp:3128"},
+		{url: "https://localhost:8443/api/v3", wantProxy: ""},
+		{url: "https://ghe.internal.corp/api/v3", wantProxy: ""},
+		{url: "https://internal.corp/api/v3", wantProxy: ""},
+		{url: "https://notinternal.corp/api/v3", wantProxy: "http://proxy.corp:3128"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			assert.NilError(t, err)
+			proxyURL, err := httpTr.Proxy(&http.Request{URL: u})
+			assert.NilError(t, err)
+			if tt.wantProxy == "" {
+				assert.Assert(t, proxyURL == nil, "expected no proxy, got %v", proxyURL)
+				return
+			}
+			assert.Equal(t, proxyURL.String(), tt.wantProxy)
+		})
+	}
+}
+
+func TestNewTransportCABundle(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	assert.NilError(t, os.WriteFile(caPath, caPEM, 0o600))
+	invalidPath := filepath.Join(dir, "invalid.pem")
+	assert.NilError(t, os.WriteFile(invalidPath, []byte("not a certificate"), 0o600))
+
+	// without the bundle the self-signed certificate is refused
+	client := &http.Client{Transport: http.DefaultTransport}
+	_, err := client.Get(ts.URL) //nolint:noctx
+	assert.ErrorContains(t, err, "certificate")
+
+	tr, err := NewTransport(&info.PacOpts{Settings: &settings.Settings{GitHubCABundlePath: caPath}})
+	assert.NilError(t, err)
+	client = &http.Client{Transport: tr}
+	resp, err := client.Get(ts.URL) //nolint:noctx
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+
+	_, err = NewTransport(&info.PacOpts{Settings: &settings.Settings{GitHubCABundlePath: invalidPath}})
+	assert.ErrorContains(t, err, "no certificates found in github ca bundle")
+
+	_, err = NewTransport(&info.PacOpts{Settings: &settings.Settings{GitHubCABundlePath: filepath.Join(dir, "missing.pem")}})
+	assert.ErrorContains(t, err, "cannot read github ca bundle")
+}
+
+func TestNewTransportCached(t *testing.T) {
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{GitHubHTTPSProxy: "http://cached.proxy.corp:3128"}}
+	tr, err := NewTransport(pacOpts)
+	assert.NilError(t, err)
+	again, err := NewTransport(&info.PacOpts{Settings: &settings.Settings{GitHubHTTPSProxy: "http://cached.proxy.corp:3128"}})
+	assert.NilError(t, err)
+	assert.Assert(t, tr == again, "transport has not been reused")
+
+	other, err := NewTransport(&info.PacOpts{Settings: &settings.Settings{GitHubHTTPSProxy: "http://other.proxy.corp:3128"}})
+	assert.NilError(t, err)
+	assert.Assert(t, tr != other, "transport of other settings has been reused")
+}
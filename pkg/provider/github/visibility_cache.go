@@ -0,0 +1,77 @@
+package github
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// repoVisibilityTTL is how long the visibility of a repository is cached,
+// it's looked up for every status published as a gist.
+const repoVisibilityTTL = 10 * time.Minute
+
+// repoVisibilities is the cache of the visibility of the repositories shared
+// by all the providers of the controller.
+var repoVisibilities = NewRepoVisibilityCache(clockwork.NewRealClock())
+
+type repoVisibilityKey struct {
+	apiURL   string
+	fullName string
+}
+
+type cachedRepoVisibility struct {
+	private   bool
+	expiresAt time.Time
+}
+
+// RepoVisibilityCache is an in-memory cache of whether the repositories are
+// private keyed by API URL and full name, safe for concurrent use.
+type RepoVisibilityCache struct {
+	mutex        sync.Mutex
+	clock        clockwork.Clock
+	visibilities map[repoVisibilityKey]cachedRepoVisibility
+}
+
+func NewRepoVisibilityCache(clock clockwork.Clock) *RepoVisibilityCache {
+	return &RepoVisibilityCache{
+		clock:        clock,
+		visibilities: map[repoVisibilityKey]cachedRepoVisibility{},
+	}
+}
+
+// Get returns whether a repository is private if it has been looked up less
+// than repoVisibilityTTL ago.
+func (c *RepoVisibilityCache) Get(apiURL, fullName string) (private, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := repoVisibilityKey{apiURL: apiURL, fullName: fullName}
+	cached, ok := c.visibilities[key]
+	if !ok {
+		return false, false
+	}
+	if !c.clock.Now().Before(cached.expiresAt) {
+		delete(c.visibilities, key)
+		return false, false
+	}
+	return cached.private, true
+}
+
+// Set stores whether a repository is private for repoVisibilityTTL.
+func (c *RepoVisibilityCache) Set(apiURL, fullName string, private bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.visibilities[repoVisibilityKey{apiURL: apiURL, fullName: fullName}] = cachedRepoVisibility{
+		private:   private,
+		expiresAt: c.clock.Now().Add(repoVisibilityTTL),
+	}
+}
+
+func (v *Provider) repoVisibilities() *RepoVisibilityCache {
+	if v.repoVisibilityCache != nil {
+		return v.repoVisibilityCache
+	}
+	return repoVisibilities
+}
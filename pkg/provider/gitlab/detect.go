@@ -42,6 +42,10 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		if gitEvent.ObjectAttributes.Action == "update" && gitEvent.ObjectAttributes.OldRev != "" {
 			return setLoggerAndProceed(true, "", nil)
 		}
+		// a draft MR being marked as ready has been skipped until now
+		if gitEvent.ObjectAttributes.Action == "update" && isMarkedAsReady(gitEvent) {
+			return setLoggerAndProceed(true, "", nil)
+		}
 		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"open", "reopen"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
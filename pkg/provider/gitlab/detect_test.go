@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"testing"
@@ -11,6 +12,11 @@ import (
 	"gotest.tools/v3/assert"
 )
 
+// withChanges adds the changes of a merge request update to its event.
+func withChanges(event, changes string) string {
+	return strings.Replace(event, "{", fmt.Sprintf(`{"changes": %s,`, changes), 1)
+}
+
 func TestProvider_Detect(t *testing.T) {
 	sample := thelp.TEvent{
 		Username:          "foo",
@@ -70,6 +76,27 @@ func TestProvider_Detect(t *testing.T) {
 			isGL:       true,
 			processReq: true,
 		},
+		{
+			name:       "good/mergeRequest update Event marked as ready",
+			event:      withChanges(sample.MREventAsJSON("update", ""), `{"draft": {"previous": true, "current": false}}`),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "good/mergeRequest update Event with draft prefix removed",
+			event:      withChanges(sample.MREventAsJSON("update", ""), `{"title": {"previous": "WIP: commit it", "current": "commit it"}}`),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "bad/mergeRequest update Event marked as draft",
+			event:      withChanges(sample.MREventAsJSON("update", ""), `{"draft": {"previous": false, "current": true}}`),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: false,
+		},
 		{
 			name:       "bad/mergeRequest update Event with no commit",
 			event:      sample.MREventAsJSON("update", ``),
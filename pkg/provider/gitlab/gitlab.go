@@ -26,7 +26,7 @@ const (
 	apiPublicURL       = "https://gitlab.com"
 	taskStatusTemplate = `
 <table>
-  <tr><th>Status</th><th>Duration</th><th>Name</th></tr>
+  <tr><th>Status</th><th>Duration</th><th>Name</th>{{- if .ShowResourceRequests }}<th>Resource Requests</th>{{- end }}</tr>
 
 {{- range $taskrun := .TaskRunList }}
 <tr>
@@ -34,11 +34,17 @@ const (
 <td>{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}</td><td>
 
 {{ $taskrun.ConsoleLogURL }}
+{{- with $taskrun.PodEvent }}<br>{{ . }}{{- end }}
 
-</td></tr>
+</td>{{- if $.ShowResourceRequests }}<td>{{ $taskrun.ResourceRequests }}</td>{{- end }}</tr>
 {{- end }}
 </table>`
 	noClientErrStr = `no gitlab client has been initialized, exiting... (hint: did you forget setting a secret on your repo?)`
+	// maxTextSize is the maximum size of a merge request note on GitLab.
+	maxTextSize = 1000000
+	// statusNoteMarker is a hidden comment identifying the merge request
+	// note with the status of a PipelineRun, so we can update it.
+	statusNoteMarker = "<!-- pipelines-as-code-status: %s -->"
 )
 
 var _ provider.Interface = (*Provider)(nil)
@@ -66,6 +72,16 @@ func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []str
 	return false, ""
 }
 
+// CancelInProgressCheckRuns TODO: Implement ME.
+func (v *Provider) CancelInProgressCheckRuns(_ context.Context, _ *info.Event) error {
+	return nil
+}
+
+// ReportsCheckRuns returns false, there are only commit statuses.
+func (v *Provider) ReportsCheckRuns(_ *info.Event) bool {
+	return false
+}
+
 func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
 	v.Logger = logger
 }
@@ -101,6 +117,7 @@ func (v *Provider) GetConfig() *info.ProviderConfig {
 		TaskStatusTMPL: taskStatusTemplate,
 		APIURL:         apiPublicURL,
 		Name:           "gitlab",
+		MaxTextSize:    maxTextSize,
 	}
 }
 
@@ -190,8 +207,9 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	if statusOpts.OriginalPipelineRunName != "" {
 		onPr = "/" + statusOpts.OriginalPipelineRunName
 	}
-	body := fmt.Sprintf("**%s%s** has %s\n\n%s\n\n<small>Full log available [here](%s)</small>",
-		v.run.Info.Pac.ApplicationName, onPr, statusOpts.Title, statusOpts.Text, detailsURL)
+	marker := fmt.Sprintf(statusNoteMarker, v.run.Info.Pac.ApplicationName+onPr)
+	body := fmt.Sprintf("**%s%s** has %s\n\n%s\n\n<small>Full log available [here](%s)</small>\n\n%s",
+		v.run.Info.Pac.ApplicationName, onPr, statusOpts.Title, statusOpts.Text, detailsURL, marker)
 
 	// in case we have access set the commit status, typically on MR from
 	// another users we won't have it but it would work on push or MR from a
@@ -203,6 +221,7 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 		Name:        gitlab.Ptr(v.run.Info.Pac.ApplicationName),
 		TargetURL:   gitlab.Ptr(detailsURL),
 		Description: gitlab.Ptr(statusOpts.Title),
+		Coverage:    statusOpts.Coverage,
 	}
 	//nolint: dogsled
 	_, _, _ = v.Client.Commits.SetCommitStatus(event.SourceProjectID, event.SHA, opt)
@@ -211,6 +230,13 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	if event.EventType == triggertype.PullRequest.String() ||
 		event.EventType == "Merge_Request" || event.EventType == "Merge Request" ||
 		opscomments.IsAnyOpsEventType(event.EventType) {
+		// update the note we have previously posted for this PipelineRun
+		// instead of adding a new one on every status change
+		if noteID := v.findStatusNote(event.TargetProjectID, event.PullRequestNumber, marker); noteID != 0 {
+			uopt := &gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.Ptr(body)}
+			_, _, err := v.Client.Notes.UpdateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, noteID, uopt)
+			return err
+		}
 		mopt := &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)}
 		_, _, err := v.Client.Notes.CreateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, mopt)
 		return err
@@ -218,6 +244,27 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusOpts
 	return nil
 }
 
+// findStatusNote returns the ID of the merge request note containing the
+// marker, or 0 if there is none or we cannot list the notes.
+func (v *Provider) findStatusNote(projectID, mrID int, marker string) int {
+	opt := &gitlab.ListMergeRequestNotesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		notes, resp, err := v.Client.Notes.ListMergeRequestNotes(projectID, mrID, opt)
+		if err != nil {
+			return 0
+		}
+		for _, note := range notes {
+			if strings.Contains(note.Body, marker) {
+				return note.ID
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return 0
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
 func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path, provenance string) (string, error) {
 	if v.Client == nil {
 		return "", fmt.Errorf("no gitlab client has been initialized, " +
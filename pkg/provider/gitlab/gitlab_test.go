@@ -190,6 +190,107 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestCreateStatusStickyNote(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, tearDown := thelp.Setup(t)
+	defer tearDown()
+
+	run := params.New()
+	run.Info.Pac.ApplicationName = "Pipelines as Code CI"
+	v := &Provider{Client: client, run: run}
+	event := &info.Event{
+		TriggerTarget:     "pull_request",
+		EventType:         "Merge Request",
+		TargetProjectID:   10,
+		PullRequestNumber: 666,
+	}
+
+	created, updated := 0, 0
+	notesPath := fmt.Sprintf("/projects/%d/merge_requests/%d/notes", event.TargetProjectID, event.PullRequestNumber)
+	mux.HandleFunc(notesPath, func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// the note from another PipelineRun is on the first page, ours on the second one
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(rw, `[{"id": 42, "body": "**Pipelines as Code CI/pr** has failed\n\n<!-- pipelines-as-code-status: Pipelines as Code CI/pr -->"}]`)
+				return
+			}
+			rw.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(rw, `[{"id": 41, "body": "**Pipelines as Code CI/other** has failed\n\n<!-- pipelines-as-code-status: Pipelines as Code CI/other -->"}]`)
+			return
+		}
+		created++
+		fmt.Fprint(rw, `{}`)
+	})
+	mux.HandleFunc(notesPath+"/42", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		note := gitlab.Note{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&note))
+		assert.Assert(t, strings.Contains(note.Body, "has successfully validated your commit"), note.Body)
+		assert.Assert(t, strings.Contains(note.Body, "<!-- pipelines-as-code-status: Pipelines as Code CI/pr -->"), note.Body)
+		updated++
+		fmt.Fprint(rw, `{}`)
+	})
+
+	err := v.CreateStatus(ctx, event, provider.StatusOpts{
+		Conclusion:              "success",
+		OriginalPipelineRunName: "pr",
+		Text:                    "all good",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, updated, 1)
+	assert.Equal(t, created, 0)
+
+	// a PipelineRun without a note yet gets a new one
+	err = v.CreateStatus(ctx, event, provider.StatusOpts{
+		Conclusion:              "failure",
+		OriginalPipelineRunName: "new",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, updated, 1)
+	assert.Equal(t, created, 1)
+}
+
+func TestCreateStatusCoverage(t *testing.T) {
+	coverage := 87.5
+	tests := []struct {
+		name     string
+		coverage *float64
+	}{
+		{name: "with coverage", coverage: &coverage},
+		{name: "without coverage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown := thelp.Setup(t)
+			defer tearDown()
+
+			v := &Provider{Client: client, run: params.New()}
+			event := &info.Event{SourceProjectID: 10, SHA: "sha", EventType: "push"}
+
+			posted := 0
+			mux.HandleFunc(fmt.Sprintf("/projects/%d/statuses/%s", event.SourceProjectID, event.SHA), func(rw http.ResponseWriter, r *http.Request) {
+				body := map[string]any{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+				got, ok := body["coverage"]
+				if tt.coverage == nil {
+					assert.Assert(t, !ok, "the coverage should be omitted")
+				} else {
+					assert.Equal(t, got, *tt.coverage)
+				}
+				posted++
+				fmt.Fprint(rw, `{}`)
+			})
+
+			assert.NilError(t, v.CreateStatus(ctx, event, provider.StatusOpts{
+				Conclusion: "success",
+				Coverage:   tt.coverage,
+			}))
+			assert.Equal(t, posted, 1)
+		})
+	}
+}
+
 func TestGetCommitInfo(t *testing.T) {
 	ctx, _ := rtesting.SetupFakeContext(t)
 	client, _, tearDown := thelp.Setup(t)
@@ -206,6 +307,7 @@ func TestGetConfig(t *testing.T) {
 	v := &Provider{}
 	assert.Assert(t, v.GetConfig().APIURL != "")
 	assert.Assert(t, v.GetConfig().TaskStatusTMPL != "")
+	assert.Equal(t, v.GetConfig().MaxTextSize, maxTextSize)
 }
 
 func TestSetClient(t *testing.T) {
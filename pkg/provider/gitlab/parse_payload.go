@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
@@ -14,6 +15,26 @@ import (
 	"github.com/xanzy/go-gitlab"
 )
 
+// draftTitle matches the title prefixes marking a merge request as a draft,
+// the WIP ones are still accepted by GitLab for backward compatibility.
+var draftTitle = regexp.MustCompile(`(?i)^\s*(\[draft\]|\(draft\)|draft:|\[wip\]|wip:)`)
+
+// isDraftMergeRequest checks if a merge request is a draft, from its draft
+// flags or from the prefix of its title.
+func isDraftMergeRequest(event *gitlab.MergeEvent) bool {
+	return event.ObjectAttributes.Draft || event.ObjectAttributes.WorkInProgress ||
+		draftTitle.MatchString(event.ObjectAttributes.Title)
+}
+
+// isMarkedAsReady checks if a merge request update is moving it out of draft.
+func isMarkedAsReady(event *gitlab.MergeEvent) bool {
+	if event.Changes.Draft.Previous && !event.Changes.Draft.Current {
+		return true
+	}
+	return draftTitle.MatchString(event.Changes.Title.Previous) &&
+		event.Changes.Title.Current != "" && !draftTitle.MatchString(event.Changes.Title.Current)
+}
+
 func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.Request,
 	payload string,
 ) (*info.Event, error) {
@@ -50,6 +71,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseURL = gitEvent.ObjectAttributes.Target.WebURL
 		processedEvent.PullRequestNumber = gitEvent.ObjectAttributes.IID
 		processedEvent.PullRequestTitle = gitEvent.ObjectAttributes.Title
+		processedEvent.PullRequestDraft = isDraftMergeRequest(gitEvent)
 		v.targetProjectID = gitEvent.Project.ID
 		v.sourceProjectID = gitEvent.ObjectAttributes.SourceProjectID
 		v.userID = gitEvent.User.ID
@@ -164,6 +164,34 @@ func TestParsePayload(t *testing.T) {
 				State:         info.State{TargetCancelPipelineRun: "dummy"},
 			},
 		},
+		{
+			name: "merge event draft",
+			args: args{
+				event:   gitlab.EventTypeMergeRequest,
+				payload: sample.MREventAsJSON("open", `"draft": true`),
+			},
+			want: &info.Event{
+				EventType:        "Merge Request",
+				TriggerTarget:    "pull_request",
+				Organization:     "hello-this-is-me-ze",
+				Repository:       "project",
+				PullRequestDraft: true,
+			},
+		},
+		{
+			name: "merge event work in progress",
+			args: args{
+				event:   gitlab.EventTypeMergeRequest,
+				payload: sample.MREventAsJSON("open", `"work_in_progress": true`),
+			},
+			want: &info.Event{
+				EventType:        "Merge Request",
+				TriggerTarget:    "pull_request",
+				Organization:     "hello-this-is-me-ze",
+				Repository:       "project",
+				PullRequestDraft: true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -196,6 +224,7 @@ func TestParsePayload(t *testing.T) {
 				assert.Equal(t, tt.want.EventType, got.EventType)
 				assert.Equal(t, tt.want.Organization, got.Organization)
 				assert.Equal(t, tt.want.Repository, got.Repository)
+				assert.Equal(t, tt.want.PullRequestDraft, got.PullRequestDraft)
 				if tt.want.TargetTestPipelineRun != "" {
 					assert.Equal(t, tt.want.TargetTestPipelineRun, got.TargetTestPipelineRun)
 				}
@@ -206,3 +235,32 @@ func TestParsePayload(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDraftMergeRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		draft bool
+		wip   bool
+		want  bool
+	}{
+		{name: "ready", title: "Add the frobnicator", want: false},
+		{name: "draft flag", title: "Add the frobnicator", draft: true, want: true},
+		{name: "work in progress flag", title: "Add the frobnicator", wip: true, want: true},
+		{name: "draft prefix", title: "Draft: Add the frobnicator", want: true},
+		{name: "draft brackets prefix", title: "[Draft] Add the frobnicator", want: true},
+		{name: "draft parenthesis prefix", title: "(draft) Add the frobnicator", want: true},
+		{name: "wip prefix", title: "WIP: Add the frobnicator", want: true},
+		{name: "wip brackets prefix", title: "[WIP] Add the frobnicator", want: true},
+		{name: "draft not as prefix", title: "Add the Draft: frobnicator", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &gitlab.MergeEvent{}
+			event.ObjectAttributes.Title = tt.title
+			event.ObjectAttributes.Draft = tt.draft
+			event.ObjectAttributes.WorkInProgress = tt.wip
+			assert.Equal(t, isDraftMergeRequest(event), tt.want)
+		})
+	}
+}
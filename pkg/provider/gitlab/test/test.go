@@ -43,6 +43,10 @@ func Setup(t *testing.T) (*gitlab.Client, *http.ServeMux, func()) {
 func MuxNotePost(t *testing.T, mux *http.ServeMux, projectNumber, mrID int, catchStr string) {
 	path := fmt.Sprintf("/projects/%d/merge_requests/%d/notes", projectNumber, mrID)
 	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(rw, "[]")
+			return
+		}
 		bit, _ := io.ReadAll(r.Body)
 		s := string(bit)
 		if catchStr != "" {
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// IdempotencyTTL is how long we remember a status idempotency token.
+const IdempotencyTTL = 10 * time.Minute
+
+// IdempotencyCache remembers the idempotency tokens of the statuses already
+// posted for a while, so a retried post with the same token can be
+// suppressed, safe for concurrent use. It is only used for the statuses
+// without a PipelineRun, the tokens of the others are recorded on the
+// PipelineRun with ClaimIdempotencyToken so they survive a restart.
+type IdempotencyCache struct {
+	mutex  sync.Mutex
+	clock  clockwork.Clock
+	ttl    time.Duration
+	tokens map[string]time.Time
+}
+
+func NewIdempotencyCache(clock clockwork.Clock, ttl time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		clock:  clock,
+		ttl:    ttl,
+		tokens: map[string]time.Time{},
+	}
+}
+
+// Seen returns true if the token has been remembered less than the TTL ago,
+// an empty token is never seen.
+func (c *IdempotencyCache) Seen(token string) bool {
+	if token == "" {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	seenAt, ok := c.tokens[token]
+	if !ok {
+		return false
+	}
+	if c.clock.Since(seenAt) >= c.ttl {
+		delete(c.tokens, token)
+		return false
+	}
+	return true
+}
+
+// Remember stores the token, expired tokens are cleaned up at the same time so
+// the cache doesn't grow forever.
+func (c *IdempotencyCache) Remember(token string) {
+	if token == "" {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+	for t, seenAt := range c.tokens {
+		if now.Sub(seenAt) >= c.ttl {
+			delete(c.tokens, t)
+		}
+	}
+	c.tokens[token] = now
+}
+
+// ClaimIdempotencyToken records the token, with the time it has been claimed
+// at, as the one of the last status posted for the PipelineRun in its
+// annotations. It returns false when it is already the recorded one and has
+// been claimed less than IdempotencyTTL before now. The patch is conditioned
+// on the resource version of the PipelineRun, so out of two concurrent claims
+// of the same token only one succeeds.
+func ClaimIdempotencyToken(ctx context.Context, tekton versioned.Interface, pr *tektonv1.PipelineRun, token string, now time.Time) (bool, error) {
+	claimed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Get(ctx, pr.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		claimedToken, claimedAt := parseIdempotencyToken(latest.GetAnnotations()[keys.StatusIdempotencyToken])
+		if claimedToken == token && now.Sub(claimedAt) < IdempotencyTTL {
+			claimed = false
+			return nil
+		}
+		value := fmt.Sprintf("%s@%s", token, now.UTC().Format(time.RFC3339))
+		if err := patchIdempotencyToken(ctx, tekton, latest, &value); err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("cannot claim the status idempotency token of pipelinerun %s/%s: %w", pr.GetNamespace(), pr.GetName(), err)
+	}
+	return claimed, nil
+}
+
+// ReleaseIdempotencyToken removes the token claimed with ClaimIdempotencyToken
+// when the status could not be posted, so a retry can post it.
+func ReleaseIdempotencyToken(ctx context.Context, tekton versioned.Interface, pr *tektonv1.PipelineRun, token string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Get(ctx, pr.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		// another status has been claimed since
+		if claimedToken, _ := parseIdempotencyToken(latest.GetAnnotations()[keys.StatusIdempotencyToken]); claimedToken != token {
+			return nil
+		}
+		return patchIdempotencyToken(ctx, tekton, latest, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot release the status idempotency token of pipelinerun %s/%s: %w", pr.GetNamespace(), pr.GetName(), err)
+	}
+	return nil
+}
+
+// parseIdempotencyToken splits the annotation recorded by
+// ClaimIdempotencyToken into the token and the time it has been claimed at, an
+// annotation without a valid time is considered claimed long ago.
+func parseIdempotencyToken(value string) (string, time.Time) {
+	i := strings.LastIndex(value, "@")
+	if i < 0 {
+		return value, time.Time{}
+	}
+	claimedAt, err := time.Parse(time.RFC3339, value[i+1:])
+	if err != nil {
+		return value, time.Time{}
+	}
+	return value[:i], claimedAt
+}
+
+// patchIdempotencyToken sets the token annotation of the PipelineRun, or
+// removes it when token is nil, failing with a conflict when the PipelineRun
+// has been modified since latest has been fetched.
+func patchIdempotencyToken(ctx context.Context, tekton versioned.Interface, latest *tektonv1.PipelineRun, token *string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": latest.GetResourceVersion(),
+			"annotations": map[string]interface{}{
+				keys.StatusIdempotencyToken: token,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tekton.TektonV1().PipelineRuns(latest.GetNamespace()).Patch(ctx, latest.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
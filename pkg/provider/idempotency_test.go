@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewIdempotencyCache(clock, 10*time.Minute)
+
+	assert.Assert(t, !cache.Seen("token"))
+	cache.Remember("token")
+	assert.Assert(t, cache.Seen("token"))
+	assert.Assert(t, !cache.Seen("another"))
+
+	clock.Advance(9 * time.Minute)
+	assert.Assert(t, cache.Seen("token"))
+
+	clock.Advance(time.Minute)
+	assert.Assert(t, !cache.Seen("token"))
+
+	// empty tokens are never suppressed
+	cache.Remember("")
+	assert.Assert(t, !cache.Seen(""))
+}
+
+func TestIdempotencyCacheCleanup(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	cache := NewIdempotencyCache(clock, time.Minute)
+
+	cache.Remember("old")
+	clock.Advance(2 * time.Minute)
+	cache.Remember("new")
+	assert.Equal(t, len(cache.tokens), 1)
+	assert.Assert(t, cache.Seen("new"))
+}
+
+func TestClaimIdempotencyToken(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pr1",
+			Namespace: "ns",
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*tektonv1.PipelineRun{pr}})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	claimed, err := ClaimIdempotencyToken(ctx, stdata.Pipeline, pr, "token", now)
+	assert.NilError(t, err)
+	assert.Assert(t, claimed)
+	claimed, err = ClaimIdempotencyToken(ctx, stdata.Pipeline, pr, "token", now)
+	assert.NilError(t, err)
+	assert.Assert(t, !claimed)
+
+	// releasing another token keeps the claimed one
+	assert.NilError(t, ReleaseIdempotencyToken(ctx, stdata.Pipeline, pr, "another"))
+	claimed, err = ClaimIdempotencyToken(ctx, stdata.Pipeline, pr, "token", now)
+	assert.NilError(t, err)
+	assert.Assert(t, !claimed)
+
+	assert.NilError(t, ReleaseIdempotencyToken(ctx, stdata.Pipeline, pr, "token"))
+	latest, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, "pr1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, ok := latest.GetAnnotations()[keys.StatusIdempotencyToken]
+	assert.Assert(t, !ok)
+	claimed, err = ClaimIdempotencyToken(ctx, stdata.Pipeline, pr, "token", now)
+	assert.NilError(t, err)
+	assert.Assert(t, claimed)
+
+	// the token can be claimed again once it has expired
+	claimed, err = ClaimIdempotencyToken(ctx, stdata.Pipeline, pr, "token", now.Add(IdempotencyTTL-time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, !claimed)
+	claimed, err = ClaimIdempotencyToken(ctx, stdata.Pipeline, pr, "token", now.Add(IdempotencyTTL))
+	assert.NilError(t, err)
+	assert.Assert(t, claimed)
+
+	_, err = ClaimIdempotencyToken(ctx, stdata.Pipeline, &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "ns"},
+	}, "token", now)
+	assert.ErrorContains(t, err, "cannot claim the status idempotency token of pipelinerun ns/missing")
+}
+
+func TestParseIdempotencyToken(t *testing.T) {
+	token, claimedAt := parseIdempotencyToken("pr1/completed@2024-01-01T00:00:00Z")
+	assert.Equal(t, token, "pr1/completed")
+	assert.Equal(t, claimedAt, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// a token without a valid time has been claimed long ago
+	token, claimedAt = parseIdempotencyToken("pr1@yesterday")
+	assert.Equal(t, token, "pr1@yesterday")
+	assert.Assert(t, claimedAt.IsZero())
+	token, claimedAt = parseIdempotencyToken("pr1")
+	assert.Equal(t, token, "pr1")
+	assert.Assert(t, claimedAt.IsZero())
+}
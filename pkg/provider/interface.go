@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
@@ -23,6 +24,68 @@ type StatusOpts struct {
 	DetailsURL              string
 	Summary                 string
 	Title                   string
+	// Parameters the run has been triggered with, rendered in the summary
+	// with the values looking like a secret redacted.
+	Parameters map[string]string
+	// FailuresOnly only shows the TaskRuns that have not succeeded in the
+	// status when the PipelineRun has failed.
+	FailuresOnly bool
+	// IdempotencyToken identifies a status post, a status with the same
+	// token as one posted less than IdempotencyTTL ago is not posted
+	// again. Statuses without a token are always posted.
+	IdempotencyToken string
+	// GraphURL links to a visualization of the graph of the PipelineRun,
+	// rendered as a "view pipeline graph" link in the summary when set.
+	GraphURL string
+	// FailureReasons are the reason of a failed PipelineRun followed by the
+	// names of its failed tasks, used to show the matching remediations.
+	FailureReasons []string
+	// FailureReason and FailureMessage are the reason and the message of the
+	// failed condition of a failed PipelineRun, i.e: PipelineRunTimeout.
+	FailureReason  string
+	FailureMessage string
+	// LastSuccessURL links to the last successful run of the same
+	// PipelineRun on the same branch, shown on failures to compare them.
+	LastSuccessURL string
+	// Annotations are shown inline on the files of the commit, collected
+	// from the AnnotationsResultName result of the tasks.
+	Annotations []Annotation
+	// DurationBudget is the duration the PipelineRun is expected to run
+	// within, the status of a completed run shows whether it did.
+	DurationBudget time.Duration
+	// FailedTaskLogURL is the log URL of the TaskRun of a failed PipelineRun
+	// when it's the only one which has failed, the check run links to it
+	// instead of DetailsURL.
+	FailedTaskLogURL string
+	// ShowCounts shows how many TaskRuns have succeeded, failed or have been
+	// skipped on a single line at the top of the summary, counted from
+	// TaskRunStatuses.
+	ShowCounts      bool
+	TaskRunStatuses map[string]*v1.PipelineRunTaskRunStatus
+	// CheckNameSuffix disambiguates the check name of the PipelineRun from
+	// the one of another PipelineRun of the commit it collides with.
+	CheckNameSuffix string
+	// ApprovalGate is the manual approval gate (i.e: the approval task) the
+	// PipelineRun is waiting for, when set the status is reported as
+	// requiring an action instead of being in progress.
+	ApprovalGate string
+	// Coverage is the test coverage percentage reported by the
+	// CoverageResultName result of a task, nil when none has been reported.
+	Coverage *float64
+}
+
+// AnnotationsResultName is the name of the task result with the JSON list of
+// annotations to show on the files of the commit.
+const AnnotationsResultName = "pac-annotations"
+
+// Annotation is a message about some lines of a file of the commit, the level
+// is one of notice, warning or failure, any other level is a failure.
+type Annotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
 }
 
 type Interface interface {
@@ -42,6 +105,8 @@ type Interface interface {
 	GetTaskURI(ctx context.Context, event *info.Event, uri string) (bool, string, error)
 	CreateToken(context.Context, []string, *info.Event) (string, error)
 	CheckPolicyAllowing(context.Context, *info.Event, []string) (bool, string)
+	CancelInProgressCheckRuns(context.Context, *info.Event) error
+	ReportsCheckRuns(*info.Event) bool
 }
 
 const DefaultProviderAPIUser = "git"
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const redactedParameterValue = "*****"
+
+var (
+	// secretParameterNameRegex matches the parameter names that are likely to
+	// contain a secret.
+	secretParameterNameRegex = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|credential|api[_-]?key|private[_-]?key)`)
+	// secretParameterValueRegex matches the values that look like a secret no
+	// matter the name of the parameter.
+	secretParameterValueRegex = regexp.MustCompile(`(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|glpat-[A-Za-z0-9_-]{20,}|-----BEGIN [A-Z ]*PRIVATE KEY-----)`)
+)
+
+// FormatParameters renders the parameters a run has been triggered with as a
+// markdown key/value list, sorted by name, with the values that look like a
+// secret redacted.
+func FormatParameters(parameters map[string]string) string {
+	if len(parameters) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("\n\n**Parameters:**\n")
+	for _, name := range names {
+		value := parameters[name]
+		if secretParameterNameRegex.MatchString(name) || secretParameterValueRegex.MatchString(value) {
+			value = redactedParameterValue
+		}
+		sb.WriteString(fmt.Sprintf("* `%s`: `%s`\n", name, value))
+	}
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+const (
+	// DefaultStatusConcurrency is how many statuses are posted at the same
+	// time when it is not set.
+	DefaultStatusConcurrency = 5
+	// lowRateLimitRemaining is the number of requests left before the rate
+	// limit of the provider under which the statuses are posted one at a
+	// time.
+	lowRateLimitRemaining = 100
+)
+
+// StatusCreator is the part of a provider able to post a status.
+type StatusCreator interface {
+	CreateStatus(context.Context, *info.Event, StatusOpts) error
+}
+
+// RateLimitReporter is implemented by the providers throttled by a rate
+// limiter, i.e: GitHub.
+type RateLimitReporter interface {
+	// RateLimitRemaining returns how many requests can still be sent before
+	// hitting the rate limit, -1 when unknown.
+	RateLimitRemaining() int
+}
+
+// StatusPool posts statuses with a bounded number of them in flight so a
+// burst of statuses doesn't trip the abuse limits of the provider, they are
+// posted one at a time when the provider is close to its rate limit. It is
+// safe for concurrent use.
+type StatusPool struct {
+	creator StatusCreator
+	slots   chan struct{}
+	serial  sync.Mutex
+}
+
+// NewStatusPool returns a pool posting at most concurrency statuses at the
+// same time, DefaultStatusConcurrency when not positive.
+func NewStatusPool(creator StatusCreator, concurrency int) *StatusPool {
+	if concurrency <= 0 {
+		concurrency = DefaultStatusConcurrency
+	}
+	return &StatusPool{
+		creator: creator,
+		slots:   make(chan struct{}, concurrency),
+	}
+}
+
+func (p *StatusPool) nearRateLimit() bool {
+	reporter, ok := p.creator.(RateLimitReporter)
+	if !ok {
+		return false
+	}
+	remaining := reporter.RateLimitRemaining()
+	return remaining >= 0 && remaining < lowRateLimitRemaining
+}
+
+// CreateStatus posts a status once there is a free slot in the pool, or
+// fails when the context is done before.
+func (p *StatusPool) CreateStatus(ctx context.Context, event *info.Event, status StatusOpts) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	if p.nearRateLimit() {
+		p.serial.Lock()
+		defer p.serial.Unlock()
+	}
+	return p.creator.CreateStatus(ctx, event, status)
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"gotest.tools/v3/assert"
+)
+
+// concurrentStatusCreator records how many statuses are posted at the same
+// time, the statuses of the PipelineRuns in failing fail.
+type concurrentStatusCreator struct {
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+	posted      []string
+	failing     map[string]bool
+	remaining   int
+}
+
+func (c *concurrentStatusCreator) CreateStatus(_ context.Context, _ *info.Event, status StatusOpts) error {
+	c.mutex.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mutex.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inFlight--
+	c.posted = append(c.posted, status.PipelineRunName)
+	if c.failing[status.PipelineRunName] {
+		return fmt.Errorf("403 Forbidden")
+	}
+	return nil
+}
+
+type rateLimitedStatusCreator struct {
+	*concurrentStatusCreator
+}
+
+func (r rateLimitedStatusCreator) RateLimitRemaining() int {
+	return r.remaining
+}
+
+func makeStatusBatch(size int) []StatusOpts {
+	statuses := []StatusOpts{}
+	for i := 0; i < size; i++ {
+		statuses = append(statuses, StatusOpts{
+			PipelineRunName: fmt.Sprintf("pr-%d", i),
+			Status:          "in_progress",
+			Conclusion:      "pending",
+		})
+	}
+	return statuses
+}
+
+// postConcurrently posts all the statuses through the pool at the same time.
+func postConcurrently(ctx context.Context, pool *StatusPool, event *info.Event, statuses []StatusOpts) {
+	var wg sync.WaitGroup
+	for _, status := range statuses {
+		wg.Add(1)
+		go func(status StatusOpts) {
+			defer wg.Done()
+			_ = pool.CreateStatus(ctx, event, status)
+		}(status)
+	}
+	wg.Wait()
+}
+
+func TestStatusPool(t *testing.T) {
+	ctx := context.Background()
+	event := &info.Event{Organization: "owner", Repository: "repo"}
+	statuses := makeStatusBatch(20)
+
+	t.Run("bounded", func(t *testing.T) {
+		creator := &concurrentStatusCreator{}
+		postConcurrently(ctx, NewStatusPool(creator, 0), event, statuses)
+		assert.Equal(t, len(creator.posted), 20)
+		assert.Assert(t, creator.maxInFlight <= DefaultStatusConcurrency, "max in flight: %d", creator.maxInFlight)
+		assert.Assert(t, creator.maxInFlight > 1, "the statuses should have been posted concurrently")
+	})
+
+	t.Run("configured concurrency", func(t *testing.T) {
+		creator := &concurrentStatusCreator{}
+		postConcurrently(ctx, NewStatusPool(creator, 2), event, statuses)
+		assert.Equal(t, len(creator.posted), 20)
+		assert.Assert(t, creator.maxInFlight <= 2, "max in flight: %d", creator.maxInFlight)
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		creator := &concurrentStatusCreator{failing: map[string]bool{"pr-3": true}}
+		pool := NewStatusPool(creator, 5)
+		assert.Error(t, pool.CreateStatus(ctx, event, statuses[3]), "403 Forbidden")
+		assert.NilError(t, pool.CreateStatus(ctx, event, statuses[4]))
+	})
+
+	t.Run("one at a time close to the rate limit", func(t *testing.T) {
+		creator := rateLimitedStatusCreator{&concurrentStatusCreator{remaining: 20}}
+		postConcurrently(ctx, NewStatusPool(creator, 5), event, statuses)
+		assert.Equal(t, len(creator.posted), 20)
+		assert.Equal(t, creator.maxInFlight, 1)
+	})
+
+	t.Run("rate limit not reached", func(t *testing.T) {
+		creator := rateLimitedStatusCreator{&concurrentStatusCreator{remaining: 4000}}
+		postConcurrently(ctx, NewStatusPool(creator, 5), event, statuses)
+		assert.Assert(t, creator.maxInFlight > 1)
+	})
+}
+
+func TestStatusPoolContextDone(t *testing.T) {
+	pool := NewStatusPool(&concurrentStatusCreator{}, 1)
+	pool.slots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := pool.CreateStatus(ctx, &info.Event{}, StatusOpts{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+const tagRefPrefix = "refs/tags/"
+
+// IsProtectedTag returns true if the event is a push on a tag matching one of
+// the comma separated globs of protected tags.
+func IsProtectedTag(event *info.Event, protectedTags string) bool {
+	if protectedTags == "" || !strings.HasPrefix(event.BaseBranch, tagRefPrefix) {
+		return false
+	}
+	return MatchGlobs(strings.TrimPrefix(event.BaseBranch, tagRefPrefix), strings.Split(protectedTags, ","))
+}
+
+// MatchGlobs returns true if value matches one of the globs, the empty and
+// invalid globs never match.
+func MatchGlobs(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		if g.Match(value) {
+			return true
+		}
+	}
+	return false
+}
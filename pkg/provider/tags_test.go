@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"gotest.tools/v3/assert"
+)
+
+func TestIsProtectedTag(t *testing.T) {
+	tests := []struct {
+		name          string
+		baseBranch    string
+		protectedTags string
+		want          bool
+	}{
+		{
+			name:          "protected tag",
+			baseBranch:    "refs/tags/v1.0.0",
+			protectedTags: "v*",
+			want:          true,
+		},
+		{
+			name:          "protected tag in a list",
+			baseBranch:    "refs/tags/release-1.0",
+			protectedTags: "v*, release-*",
+			want:          true,
+		},
+		{
+			name:          "tag not protected",
+			baseBranch:    "refs/tags/nightly",
+			protectedTags: "v*,release-*",
+		},
+		{
+			name:          "branch matching the glob",
+			baseBranch:    "refs/heads/v1",
+			protectedTags: "v*",
+		},
+		{
+			name:       "no protected tags configured",
+			baseBranch: "refs/tags/v1.0.0",
+		},
+		{
+			name:          "invalid glob",
+			baseBranch:    "refs/tags/v1.0.0",
+			protectedTags: "[v",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &info.Event{BaseBranch: tt.baseBranch}
+			assert.Equal(t, IsProtectedTag(event, tt.protectedTags), tt.want)
+		})
+	}
+}
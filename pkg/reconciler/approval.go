@@ -0,0 +1,61 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// approvalTaskKind is the kind of the custom task of the manual approval
+// gates, see https://github.com/openshift-pipelines/manual-approval-gate.
+const approvalTaskKind = "ApprovalTask"
+
+// pendingApprovalGate returns the name of the pipeline task of the manual
+// approval gate the PipelineRun is waiting for, empty when there is none.
+func (r *Reconciler) pendingApprovalGate(ctx context.Context, pr *tektonv1.PipelineRun) string {
+	for _, child := range pr.Status.ChildReferences {
+		if child.Kind != "CustomRun" {
+			continue
+		}
+		customRun, err := r.run.Clients.Tekton.TektonV1beta1().CustomRuns(pr.GetNamespace()).Get(ctx, child.Name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if customRun.Spec.CustomRef == nil || string(customRun.Spec.CustomRef.Kind) != approvalTaskKind {
+			continue
+		}
+		if !customRun.IsDone() {
+			return child.PipelineTaskName
+		}
+	}
+	return ""
+}
+
+// reportApprovalGate reports a running PipelineRun as waiting for an approval
+// when it reaches a manual approval gate, and as running again once the gate
+// has been approved. The gate reported is recorded on the PipelineRun so it's
+// only reported once.
+func (r *Reconciler) reportApprovalGate(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun) error {
+	gate := r.pendingApprovalGate(ctx, pr)
+	if gate == pr.GetAnnotations()[keys.ApprovalGate] {
+		return nil
+	}
+	repo, err := r.repoLister.Repositories(pr.Namespace).Get(pr.GetAnnotations()[keys.Repository])
+	if err != nil {
+		return fmt.Errorf("reportApprovalGate: %w", err)
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{keys.ApprovalGate: gate},
+		},
+	}
+	if pr, err = action.PatchPipelineRun(ctx, logger, "approval gate", r.run.Clients.Tekton, pr, patch); err != nil {
+		return err
+	}
+	return r.reportInProgress(ctx, logger, repo, pr, gate)
+}
@@ -0,0 +1,105 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestPendingApprovalGate(t *testing.T) {
+	customRun := func(name, kind string, condition corev1.ConditionStatus) *tektonv1beta1.CustomRun {
+		run := &tektonv1beta1.CustomRun{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Spec: tektonv1beta1.CustomRunSpec{
+				CustomRef: &tektonv1beta1.TaskRef{APIVersion: "openshift-pipelines.org/v1alpha1", Kind: tektonv1beta1.TaskKind(kind)},
+			},
+		}
+		if condition != "" {
+			run.Status.Status = knativeduckv1.Status{Conditions: knativeduckv1.Conditions{
+				{Type: knativeapi.ConditionSucceeded, Status: condition},
+			}}
+		}
+		return run
+	}
+
+	tests := []struct {
+		name       string
+		customRuns []*tektonv1beta1.CustomRun
+		want       string
+	}{
+		{
+			name:       "waiting for an approval",
+			customRuns: []*tektonv1beta1.CustomRun{customRun("pr-approve", approvalTaskKind, corev1.ConditionUnknown)},
+			want:       "approve",
+		},
+		{
+			name:       "approved",
+			customRuns: []*tektonv1beta1.CustomRun{customRun("pr-approve", approvalTaskKind, corev1.ConditionTrue)},
+		},
+		{
+			name:       "another custom task",
+			customRuns: []*tektonv1beta1.CustomRun{customRun("pr-approve", "Wait", corev1.ConditionUnknown)},
+		},
+		{
+			name: "custom run not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			for _, run := range tt.customRuns {
+				_, err := stdata.Pipeline.TektonV1beta1().CustomRuns("ns").Create(ctx, run, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			r := &Reconciler{run: &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline}}}
+			pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns"}}
+			pr.Status.ChildReferences = []tektonv1.ChildStatusReference{
+				{TypeMeta: runtime.TypeMeta{Kind: "TaskRun"}, Name: "pr-build", PipelineTaskName: "build"},
+				{TypeMeta: runtime.TypeMeta{Kind: "CustomRun"}, Name: "pr-approve", PipelineTaskName: "approve"},
+			}
+			assert.Equal(t, r.pendingApprovalGate(ctx, pr), tt.want)
+		})
+	}
+}
+
+func TestReportApprovalGateAlreadyReported(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	_, err := stdata.Pipeline.TektonV1beta1().CustomRuns("ns").Create(ctx, &tektonv1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pr-approve", Namespace: "ns"},
+		Spec:       tektonv1beta1.CustomRunSpec{CustomRef: &tektonv1beta1.TaskRef{Kind: approvalTaskKind}},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	fakelogger, _ := logger.GetLogger()
+	r := &Reconciler{run: &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline}}}
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pr",
+		Namespace:   "ns",
+		Annotations: map[string]string{keys.ApprovalGate: "approve"},
+	}}
+	pr.Status.ChildReferences = []tektonv1.ChildStatusReference{
+		{TypeMeta: runtime.TypeMeta{Kind: "CustomRun"}, Name: "pr-approve", PipelineTaskName: "approve"},
+	}
+
+	// the gate has already been reported, nothing is reported again
+	assert.NilError(t, r.reportApprovalGate(ctx, fakelogger, pr))
+
+	// a PipelineRun without a gate is not reported either
+	pr.Status.ChildReferences = nil
+	pr.Annotations = nil
+	assert.NilError(t, r.reportApprovalGate(ctx, fakelogger, pr))
+}
@@ -8,9 +8,30 @@ import (
 )
 
 func (r *Reconciler) emitMetrics(pr *tektonv1.PipelineRun) error {
-	gitProvider := pr.GetAnnotations()[keys.GitProvider]
-	eventType := pr.GetAnnotations()[keys.EventType]
+	gitProvider, err := metricsProviderName(pr)
+	if err != nil {
+		return err
+	}
+	return r.metrics.Count(gitProvider, pr.GetAnnotations()[keys.EventType])
+}
 
+// emitStatusMetrics counts a status update of a PipelineRun, the metrics are
+// skipped when the provider of the PipelineRun is unknown.
+func (r *Reconciler) emitStatusMetrics(pr *tektonv1.PipelineRun, conclusion string, failed bool) error {
+	if r.metrics == nil || pr == nil {
+		return nil
+	}
+	gitProvider, err := metricsProviderName(pr)
+	if err != nil {
+		return err
+	}
+	return r.metrics.StatusUpdate(gitProvider, conclusion, failed)
+}
+
+// metricsProviderName returns the provider of a PipelineRun as labeled on the
+// metrics, i.e: github-app or gitlab-webhook.
+func metricsProviderName(pr *tektonv1.PipelineRun) (string, error) {
+	gitProvider := pr.GetAnnotations()[keys.GitProvider]
 	switch gitProvider {
 	case "github", "github-enterprise":
 		if _, ok := pr.GetAnnotations()[keys.InstallationID]; ok {
@@ -21,8 +42,7 @@ func (r *Reconciler) emitMetrics(pr *tektonv1.PipelineRun) error {
 	case "gitlab", "gitea", "bitbucket-cloud", "bitbucket-server":
 		gitProvider += "-webhook"
 	default:
-		return fmt.Errorf("no supported Git provider")
+		return "", fmt.Errorf("no supported Git provider")
 	}
-
-	return r.metrics.Count(gitProvider, eventType)
+	return gitProvider, nil
 }
@@ -8,12 +8,25 @@ import (
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativemetrics "knative.dev/pkg/metrics"
+	"knative.dev/pkg/metrics/metricstest"
 )
 
+// unregisterMetrics resets the views of the recorder so the values recorded
+// by a test don't leak in the next one.
+func unregisterMetrics() {
+	knativemetrics.InitForTesting()
+	metricstest.Unregister("pipelines_as_code_pipelinerun_count",
+		"pipelines_as_code_status_update_count",
+		"pipelines_as_code_status_update_failure_count",
+		"pipelines_as_code_git_provider_api_latency")
+}
+
 func TestEmitMetrics(t *testing.T) {
 	tests := []struct {
 		name        string
 		annotations map[string]string
+		wantTags    map[string]string
 		wantErr     bool
 	}{
 		{
@@ -23,7 +36,8 @@ func TestEmitMetrics(t *testing.T) {
 				keys.EventType:      "pull_request",
 				keys.InstallationID: "123",
 			},
-			wantErr: false,
+			wantTags: map[string]string{"provider": "github-app", "event-type": "pull_request"},
+			wantErr:  false,
 		},
 		{
 			name: "provider is GitHub Enterprise App",
@@ -32,7 +46,8 @@ func TestEmitMetrics(t *testing.T) {
 				keys.EventType:      "pull_request",
 				keys.InstallationID: "123",
 			},
-			wantErr: false,
+			wantTags: map[string]string{"provider": "github-enterprise-app", "event-type": "pull_request"},
+			wantErr:  false,
 		},
 		{
 			name: "provider is GitHub Webhook",
@@ -40,7 +55,8 @@ func TestEmitMetrics(t *testing.T) {
 				keys.GitProvider: "github",
 				keys.EventType:   "pull_request",
 			},
-			wantErr: false,
+			wantTags: map[string]string{"provider": "github-webhook", "event-type": "pull_request"},
+			wantErr:  false,
 		},
 		{
 			name: "provider is GitLab",
@@ -48,7 +64,8 @@ func TestEmitMetrics(t *testing.T) {
 				keys.GitProvider: "gitlab",
 				keys.EventType:   "push",
 			},
-			wantErr: false,
+			wantTags: map[string]string{"provider": "gitlab-webhook", "event-type": "push"},
+			wantErr:  false,
 		},
 		{
 			name: "unsupported provider",
@@ -62,6 +79,7 @@ func TestEmitMetrics(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			unregisterMetrics()
 			m, err := metrics.NewRecorder()
 			assert.NilError(t, err)
 			r := &Reconciler{
@@ -75,6 +93,82 @@ func TestEmitMetrics(t *testing.T) {
 			if err = r.emitMetrics(pr); (err != nil) != tt.wantErr {
 				t.Errorf("emitMetrics() error = %v, wantErr %v", err != nil, tt.wantErr)
 			}
+			if tt.wantErr {
+				metricstest.CheckStatsNotReported(t, "pipelines_as_code_pipelinerun_count")
+				return
+			}
+			metricstest.CheckCountData(t, "pipelines_as_code_pipelinerun_count", tt.wantTags, 1)
+		})
+	}
+}
+
+func TestEmitStatusMetrics(t *testing.T) {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				keys.GitProvider:    "github",
+				keys.InstallationID: "123",
+			},
+		},
+	}
+	wantTags := map[string]string{"provider": "github-app", "conclusion": "success"}
+	tests := []struct {
+		name        string
+		noRecorder  bool
+		pr          *tektonv1.PipelineRun
+		failed      bool
+		wantCount   int64
+		wantFailure int64
+		wantErr     string
+	}{
+		{
+			name:      "status update",
+			pr:        pr,
+			wantCount: 1,
+		},
+		{
+			name:        "failed status update",
+			pr:          pr,
+			failed:      true,
+			wantCount:   1,
+			wantFailure: 1,
+		},
+		{
+			name:       "no recorder",
+			noRecorder: true,
+			pr:         pr,
+		},
+		{
+			name:    "unsupported provider",
+			pr:      &tektonv1.PipelineRun{},
+			wantErr: "no supported Git provider",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unregisterMetrics()
+			r := &Reconciler{}
+			if !tt.noRecorder {
+				m, err := metrics.NewRecorder()
+				assert.NilError(t, err)
+				r.metrics = m
+			}
+			err := r.emitStatusMetrics(tt.pr, "success", tt.failed)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			if tt.wantCount == 0 {
+				metricstest.CheckStatsNotReported(t, "pipelines_as_code_status_update_count")
+			} else {
+				metricstest.CheckCountData(t, "pipelines_as_code_status_update_count", wantTags, tt.wantCount)
+			}
+			if tt.wantFailure == 0 {
+				metricstest.CheckStatsNotReported(t, "pipelines_as_code_status_update_failure_count")
+			} else {
+				metricstest.CheckCountData(t, "pipelines_as_code_status_update_failure_count", wantTags, tt.wantFailure)
+			}
 		})
 	}
 }
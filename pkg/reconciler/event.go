@@ -33,6 +33,7 @@ func (r *Reconciler) detectProvider(ctx context.Context, logger *zap.SugaredLogg
 		gh := github.New()
 		gh.Logger = logger
 		gh.Run = r.run
+		gh.Metrics = r.metrics
 		if event.InstallationID != 0 {
 			if err := gh.InitAppClient(ctx, r.run.Clients.Kube, event); err != nil {
 				return nil, nil, err
@@ -83,8 +84,8 @@ func buildEventFromPipelineRun(pr *tektonv1.PipelineRun) *info.Event {
 		id, _ := strconv.Atoi(installationID)
 		event.InstallationID = int64(id)
 	}
-	if gheURL, ok := prAnno[keys.GHEURL]; ok {
-		event.GHEURL = gheURL
+	if gheHost, ok := prAnno[keys.GHEURL]; ok || event.InstallationID > 0 {
+		event.GHEHost = gheHost
 	}
 
 	// Gitlab
@@ -25,7 +25,7 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 		Organization:      "url-org",
 		Repository:        "repo",
 		InstallationID:    12345678,
-		GHEURL:            "http://ghe",
+		GHEHost:           "ghe",
 		SourceProjectID:   1234,
 		TargetProjectID:   2345,
 	}
@@ -53,7 +53,7 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 
 						// github
 						keys.InstallationID: "12345678",
-						keys.GHEURL:         "http://ghe",
+						keys.GHEURL:         "ghe",
 
 						// gitlab
 						keys.SourceProjectID: "1234",
@@ -74,7 +74,7 @@ func TestBuildEventFromPipelineRun(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			event := buildEventFromPipelineRun(tt.pipelineRun)
 			assert.Equal(t, event.InstallationID, tt.event.InstallationID)
-			assert.Equal(t, event.GHEURL, tt.event.GHEURL)
+			assert.Equal(t, event.GHEHost, tt.event.GHEHost)
 			assert.Equal(t, event.SHA, tt.event.SHA)
 			assert.Equal(t, event.SHATitle, tt.event.SHATitle)
 			assert.Equal(t, event.SourceProjectID, tt.event.SourceProjectID)
@@ -58,8 +58,9 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *tektonv1.PipelineRun
 		return nil
 	}
 
-	// if its a GitHub App pipelineRun PR then process only if check run id is added otherwise wait
-	if _, ok := pr.Annotations[keys.InstallationID]; ok {
+	// if its a GitHub App pipelineRun PR reported as a check run then process
+	// only if check run id is added otherwise wait
+	if _, ok := pr.Annotations[keys.InstallationID]; ok && r.run.Info.Pac.StatusMode != settings.StatusModeCommitStatus {
 		if _, ok := pr.Annotations[keys.CheckRunID]; !ok {
 			return nil
 		}
@@ -72,6 +73,9 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *tektonv1.PipelineRun
 	}
 
 	if !pr.IsDone() {
+		if state == kubeinteraction.StateStarted {
+			return r.reportApprovalGate(ctx, logger, pr)
+		}
 		return nil
 	}
 
@@ -129,7 +133,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *tektonv1.PipelineRun
 	return nil
 }
 
-func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredLogger, event *info.Event, pr *tektonv1.PipelineRun, provider provider.Interface) (*v1alpha1.Repository, error) {
+func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredLogger, event *info.Event, pr *tektonv1.PipelineRun, vcx provider.Interface) (*v1alpha1.Repository, error) {
 	repoName := pr.GetAnnotations()[keys.Repository]
 	repo, err := r.repoLister.Repositories(pr.Namespace).Get(repoName)
 	if err != nil {
@@ -147,18 +151,22 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	if event.InstallationID > 0 {
 		event.Provider.WebhookSecret, _ = pipelineascode.GetCurrentNSWebhookSecret(ctx, r.kinteract, r.run)
 	} else {
-		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, provider.GetConfig(), event, repo, logger); err != nil {
+		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, vcx.GetConfig(), event, repo, logger); err != nil {
 			return repo, fmt.Errorf("cannot get secret from repository: %w", err)
 		}
 	}
 
-	err = provider.SetClient(ctx, r.run, event, repo, r.eventEmitter)
+	err = vcx.SetClient(ctx, r.run, event, repo, r.eventEmitter)
 	if err != nil {
 		return repo, fmt.Errorf("cannot set client: %w", err)
 	}
 
 	finalState := kubeinteraction.StateCompleted
-	newPr, err := r.postFinalStatus(ctx, logger, provider, event, pr)
+	newPr, err := r.postFinalStatus(ctx, logger, repo, vcx, event, pr)
+	if err != nil && provider.IsRetryableStatusError(err) {
+		// the PipelineRun gets reconciled again later to report it
+		return repo, err
+	}
 	if err != nil {
 		logger.Errorf("failed to post final status, moving on: %v", err)
 		finalState = kubeinteraction.StateFailed
@@ -202,6 +210,12 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 	if err != nil {
 		return fmt.Errorf("cannot update state: %w", err)
 	}
+	return r.reportInProgress(ctx, logger, repo, pr, "")
+}
+
+// reportInProgress reports the PipelineRun as running on the git provider, or
+// as waiting for the approval of approvalGate when set.
+func (r *Reconciler) reportInProgress(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, approvalGate string) error {
 	p, event, err := r.detectProvider(ctx, logger, pr)
 	if err != nil {
 		logger.Error(err)
@@ -243,9 +257,10 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 		PipelineRunName:         pr.GetName(),
 		PipelineRun:             pr,
 		OriginalPipelineRunName: pr.GetAnnotations()[keys.OriginalPRName],
+		ApprovalGate:            approvalGate,
 	}
 
-	if err := createStatusWithRetry(ctx, logger, p, event, status); err != nil {
+	if err := r.createStatusWithRetry(ctx, logger, p, event, status); err != nil {
 		// if failed to report status for running state, let the pipelineRun continue,
 		// pipelineRun is already started so we will try again once it completes
 		logger.Errorf("failed to report status to running on provider continuing! error: %v", err)
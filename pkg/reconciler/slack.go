@@ -0,0 +1,69 @@
+package reconciler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/slack"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+)
+
+// slackNotifyTimeout is how long getting the Slack webhook and posting to it
+// may take.
+const slackNotifyTimeout = 10 * time.Second
+
+// notifySlack posts the final status of a PipelineRun to the Slack webhook of
+// the repository when it has one. It's independent of the status on the git
+// provider and of the reconciliation, it's meant to run in its own goroutine
+// with a context of its own bounded by slackNotifyTimeout: errors are only
+// logged.
+func (r *Reconciler) notifySlack(logger *zap.SugaredLogger, repo *v1alpha1.Repository, event *info.Event, pr *tektonv1.PipelineRun, status provider.StatusOpts) {
+	if repo == nil || repo.Spec.Settings == nil || repo.Spec.Settings.SlackWebhookSecret == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), slackNotifyTimeout)
+	defer cancel()
+
+	secret := repo.Spec.Settings.SlackWebhookSecret
+	key := secret.Key
+	if key == "" {
+		key = slack.DefaultWebhookSecretKey
+	}
+	webhookURL, err := r.kinteract.GetSecret(ctx, ktypes.GetSecretOpt{
+		Namespace: repo.GetNamespace(),
+		Name:      secret.Name,
+		Key:       key,
+	})
+	if err != nil {
+		logger.Warnf("cannot get the slack webhook secret %s of repository %s: %v", secret.Name, repo.GetName(), err)
+		return
+	}
+	if webhookURL == "" {
+		logger.Warnf("no slack webhook url in the key %s of the secret %s of repository %s", key, secret.Name, repo.GetName())
+		return
+	}
+
+	allowedHosts := []string{}
+	if r.run.Info.Pac != nil && r.run.Info.Pac.Settings != nil {
+		allowedHosts = strings.Split(r.run.Info.Pac.SlackWebhookAllowedHosts, ",")
+	}
+	if err := slack.ValidateWebhookURL(webhookURL, allowedHosts); err != nil {
+		logger.Warnf("not notifying slack with the webhook of the secret %s of repository %s: %v", secret.Name, repo.GetName(), err)
+		return
+	}
+
+	pullRequestURL := slack.PullRequestURL(pr.GetAnnotations()[keys.GitProvider], event.URL, event.PullRequestNumber)
+	msg := slack.FormatMessage(event, status, pullRequestURL)
+	if err := slack.Post(ctx, &r.run.Clients.HTTP, webhookURL, msg); err != nil {
+		logger.Warnf("cannot notify slack of the status of pipelinerun %s: %v", pr.GetName(), err)
+		return
+	}
+	logger.Infof("notified slack of the status of pipelinerun %s", pr.GetName())
+}
@@ -0,0 +1,142 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/slack"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNotifySlack(t *testing.T) {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pr-abcde",
+			Namespace:   "ns",
+			Annotations: map[string]string{keys.GitProvider: "github"},
+		},
+	}
+	event := &info.Event{
+		Organization:      "owner",
+		Repository:        "repo",
+		URL:               "https://github.com/owner/repo",
+		PullRequestNumber: 42,
+	}
+	status := provider.StatusOpts{Status: "completed", Conclusion: "success", PipelineRunName: "pr-abcde"}
+
+	tests := []struct {
+		name         string
+		settings     *pacv1a1.Settings
+		secrets      map[string]string
+		allowedHosts string
+		wantMessages int
+		wantLog      string
+	}{
+		{
+			name:     "no slack webhook",
+			settings: &pacv1a1.Settings{},
+		},
+		{
+			name: "notified",
+			settings: &pacv1a1.Settings{
+				SlackWebhookSecret: &pacv1a1.Secret{Name: "slack"},
+			},
+			secrets:      map[string]string{"slack": "/services/ok"},
+			allowedHosts: "127.0.0.1",
+			wantMessages: 1,
+			wantLog:      "notified slack of the status of pipelinerun pr-abcde",
+		},
+		{
+			name: "missing secret",
+			settings: &pacv1a1.Settings{
+				SlackWebhookSecret: &pacv1a1.Secret{Name: "slack"},
+			},
+			wantLog: "cannot get the slack webhook secret slack of repository repo",
+		},
+		{
+			name: "not an https url",
+			settings: &pacv1a1.Settings{
+				SlackWebhookSecret: &pacv1a1.Secret{Name: "slack"},
+			},
+			secrets: map[string]string{"slack": "http://hooks.slack.com/services/ok"},
+			wantLog: "not notifying slack with the webhook of the secret slack of repository repo: the slack webhook url is not an https url",
+		},
+		{
+			name: "host not allowed",
+			settings: &pacv1a1.Settings{
+				SlackWebhookSecret: &pacv1a1.Secret{Name: "slack"},
+			},
+			secrets:      map[string]string{"slack": "/services/ok"},
+			allowedHosts: "slack.proxy.corp",
+			wantLog:      "not notifying slack with the webhook of the secret slack of repository repo: the host of the slack webhook url is not hooks.slack.com",
+		},
+		{
+			name: "webhook error",
+			settings: &pacv1a1.Settings{
+				SlackWebhookSecret: &pacv1a1.Secret{Name: "slack", Key: "url"},
+			},
+			secrets:      map[string]string{"slack": "/services/invalid"},
+			allowedHosts: "127.0.0.1",
+			wantLog:      "cannot notify slack of the status of pipelinerun pr-abcde: slack webhook returned 404",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			fakelogger := zap.New(observer).Sugar()
+
+			messages := []slack.Message{}
+			mux := http.NewServeMux()
+			mux.HandleFunc("/services/ok", func(_ http.ResponseWriter, r *http.Request) {
+				msg := slack.Message{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&msg))
+				messages = append(messages, msg)
+			})
+			server := httptest.NewTLSServer(mux)
+			defer server.Close()
+
+			secrets := map[string]string{}
+			for name, path := range tt.secrets {
+				if strings.HasPrefix(path, "/") {
+					path = server.URL + path
+				}
+				secrets[name] = path
+			}
+			repo := &pacv1a1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       pacv1a1.RepositorySpec{Settings: tt.settings},
+			}
+			run := params.New()
+			run.Clients.HTTP = *server.Client()
+			run.Info.Pac.SlackWebhookAllowedHosts = tt.allowedHosts
+			r := &Reconciler{
+				run:       run,
+				kinteract: &kubernetestint.KinterfaceTest{GetSecretResult: secrets},
+			}
+
+			r.notifySlack(fakelogger, repo, event, pr, status)
+			assert.Equal(t, len(messages), tt.wantMessages)
+			if tt.wantMessages > 0 {
+				assert.Equal(t, messages[0].Text, slack.FormatMessage(event, status, "https://github.com/owner/repo/pull/42").Text)
+			}
+			if tt.wantLog != "" {
+				assert.Equal(t, logs.FilterMessageSnippet(tt.wantLog).Len(), 1, logs.All())
+			} else {
+				assert.Equal(t, logs.Len(), 0)
+			}
+		})
+	}
+}
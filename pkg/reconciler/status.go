@@ -2,6 +2,8 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/google/go-github/v59/github"
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -16,10 +19,12 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
 )
 
 const (
@@ -85,18 +90,60 @@ func (r *Reconciler) getFailureSnippet(ctx context.Context, pr *tektonv1.Pipelin
 		return ""
 	}
 	sortedTaskInfos := sort.TaskInfos(taskinfos)
-	text := strings.TrimSpace(sortedTaskInfos[0].LogSnippet)
-	if text == "" {
-		text = sortedTaskInfos[0].Message
+	if r.run.Info.Pac != nil && r.run.Info.Pac.Settings != nil && r.run.Info.Pac.GroupFailureMessages {
+		return formatGroupedFailures(sortedTaskInfos)
 	}
-	name := sortedTaskInfos[0].Name
-	if sortedTaskInfos[0].DisplayName != "" {
-		name = strings.ToLower(sortedTaskInfos[0].DisplayName)
+	return fmt.Sprintf("task <b>%s</b> has the status <b>\"%s\"</b>:\n<pre>%s</pre>",
+		failureTaskName(sortedTaskInfos[0]), sortedTaskInfos[0].Reason, failureText(sortedTaskInfos[0]))
+}
+
+// formatGroupedFailures renders the failures of all the failed tasks, the tasks
+// failing with the same message are collapsed in one entry with the list of
+// their names expandable.
+func formatGroupedFailures(taskinfos []pacv1a1.TaskInfos) string {
+	texts := []string{}
+	groups := map[string][]pacv1a1.TaskInfos{}
+	for _, ti := range taskinfos {
+		text := failureText(ti)
+		if _, ok := groups[text]; !ok {
+			texts = append(texts, text)
+		}
+		groups[text] = append(groups[text], ti)
+	}
+
+	failures := []string{}
+	for _, text := range texts {
+		group := groups[text]
+		if len(group) == 1 {
+			failures = append(failures, fmt.Sprintf("task <b>%s</b> has the status <b>\"%s\"</b>:\n<pre>%s</pre>",
+				failureTaskName(group[0]), group[0].Reason, text))
+			continue
+		}
+		names := []string{}
+		for _, ti := range group {
+			names = append(names, fmt.Sprintf("<li>%s</li>", failureTaskName(ti)))
+		}
+		failures = append(failures, fmt.Sprintf("<b>%d tasks</b>: <pre>%s</pre>\n<details>\n<summary>failed tasks</summary>\n<ul>%s</ul>\n</details>",
+			len(group), text, strings.Join(names, "")))
+	}
+	return strings.Join(failures, "\n")
+}
+
+func failureText(ti pacv1a1.TaskInfos) string {
+	if text := strings.TrimSpace(ti.LogSnippet); text != "" {
+		return text
+	}
+	return ti.Message
+}
+
+func failureTaskName(ti pacv1a1.TaskInfos) string {
+	if ti.DisplayName != "" {
+		return strings.ToLower(ti.DisplayName)
 	}
-	return fmt.Sprintf("task <b>%s</b> has the status <b>\"%s\"</b>:\n<pre>%s</pre>", name, sortedTaskInfos[0].Reason, text)
+	return ti.Name
 }
 
-func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, createdPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
+func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, repo *pacv1a1.Repository, vcx provider.Interface, event *info.Event, createdPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
 	pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(createdPR.GetNamespace()).Get(
 		ctx, createdPR.GetName(), metav1.GetOptions{},
 	)
@@ -104,13 +151,108 @@ func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLog
 		return pr, err
 	}
 
+	status, err := r.makeStatusOpts(ctx, vcx, pr)
+	if err != nil {
+		return pr, err
+	}
+
+	err = r.createStatusWithRetry(ctx, logger, vcx, event, status)
+	logger.Infof("pipelinerun %s has a status of '%s'", pr.Name, status.Conclusion)
+	// the PipelineRun is reconciled again to retry the status, notify on
+	// the final attempt only
+	if err == nil || !provider.IsRetryableStatusError(err) {
+		go r.notifySlack(logger, repo, event, pr.DeepCopy(), status)
+	}
+	return pr, err
+}
+
+// RefreshStatus fetches the current state of a PipelineRun and posts its
+// status again to the git provider, i.e: when the status shown there got
+// lost or is out of date. A PipelineRun that is still running is reported as
+// in progress. The status has no idempotency token, so it is posted again even
+// when the same status has already been posted.
+func (r *Reconciler) RefreshStatus(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, runevent *info.Event, namespace, pipelineRunName string) error {
+	pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(namespace).Get(ctx, pipelineRunName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot get pipelinerun %s/%s: %w", namespace, pipelineRunName, err)
+	}
+
+	status, err := r.makeStatusOpts(ctx, vcx, pr)
+	if err != nil {
+		return err
+	}
+	if !pr.IsDone() {
+		status.Status = "in_progress"
+		status.Conclusion = "pending"
+	}
+
+	logger.Infof("refreshing status of pipelinerun %s/%s with status '%s'", namespace, pipelineRunName, status.Status)
+	return r.createStatusWithRetry(ctx, logger, vcx, runevent, status)
+}
+
+// makeStatusOpts builds the status to report for a PipelineRun, with the
+// task statuses and failure snippets rendered in the text.
+func (r *Reconciler) makeStatusOpts(ctx context.Context, vcx provider.Interface, pr *tektonv1.PipelineRun) (provider.StatusOpts, error) {
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		PipelineRun:             pr,
+		Conclusion:              formatting.PipelineRunStatus(pr),
+		PipelineRunName:         pr.Name,
+		DetailsURL:              r.run.Clients.ConsoleUI.DetailURL(pr),
+		OriginalPipelineRunName: pr.GetAnnotations()[apipac.OriginalPRName],
+		FailuresOnly:            pr.GetAnnotations()[apipac.StatusFailuresOnly] == "true",
+		ShowCounts:              pr.GetAnnotations()[apipac.StatusCounts] == "true",
+	}
+	if pr.GetAnnotations()[apipac.StatusParameters] == "true" {
+		status.Parameters = getParameters(pr)
+	}
+	if r.run.Info.Pac.StatusGraphURL != "" {
+		status.GraphURL = templates.ReplacePlaceHoldersVariables(r.run.Info.Pac.StatusGraphURL, map[string]string{
+			"namespace":   pr.GetNamespace(),
+			"pipelinerun": pr.GetName(),
+		}, nil, nil, nil)
+	}
+
+	if budget, ok := pr.GetAnnotations()[apipac.StatusDurationBudget]; ok {
+		duration, err := time.ParseDuration(budget)
+		if err != nil {
+			logging.FromContext(ctx).Warnf("invalid duration budget %q on pipelinerun %s: %v", budget, pr.GetName(), err)
+		} else {
+			status.DurationBudget = duration
+		}
+	}
+
 	trStatus := kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run)
+	if status.Conclusion == "failure" {
+		status.FailureReasons = getFailureReasons(pr, trStatus)
+		if cond := pr.Status.GetCondition(apis.ConditionSucceeded); cond != nil {
+			status.FailureReason = cond.Reason
+			status.FailureMessage = cond.Message
+		}
+		status.FailedTaskLogURL = getFailedTaskLogURL(pr, trStatus, r.run.Clients.ConsoleUI)
+		if r.run.Info.Pac.StatusLastSuccessLink {
+			status.LastSuccessURL = r.getLastSuccessURL(ctx, pr)
+		}
+	}
+	status.Annotations = getAnnotations(ctx, trStatus)
+	status.Coverage = getCoverage(ctx, trStatus)
+	status.TaskRunStatuses = trStatus
 	var taskStatusText string
 	if len(trStatus) > 0 {
 		var err error
-		taskStatusText, err = sort.TaskStatusTmpl(pr, trStatus, r.run, vcx.GetConfig())
+		opts := sort.TaskStatusOpts{
+			FailuresOnly: status.FailuresOnly && status.Conclusion == "failure",
+		}
+		if r.run.Info.Pac.StatusPodEvents {
+			opts.PodEvents = kstatus.GetTaskRunsPodEvents(ctx, pr, trStatus, r.run)
+		}
+		if r.run.Info.Pac.StatusResourceRequests {
+			opts.ShowResourceRequests = true
+			opts.ResourceRequests = kstatus.GetTaskRunsResourceRequests(ctx, pr, trStatus, r.run)
+		}
+		taskStatusText, err = sort.TaskStatusTmpl(pr, trStatus, r.run, vcx.GetConfig(), opts)
 		if err != nil {
-			return pr, err
+			return status, err
 		}
 	} else {
 		taskStatusText = pr.Status.GetCondition(apis.ConditionSucceeded).Message
@@ -136,33 +278,184 @@ func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLog
 			mt.FailureSnippet = failures
 		}
 	}
-	var tmplStatusText string
-	if tmplStatusText, err = mt.MakeTemplate(formatting.PipelineRunStatusText); err != nil {
-		return nil, fmt.Errorf("cannot create message template: %w", err)
+	tmplStatusText, err := mt.MakeTemplate(formatting.PipelineRunStatusText)
+	if err != nil {
+		return status, fmt.Errorf("cannot create message template: %w", err)
 	}
 
-	status := provider.StatusOpts{
-		Status:                  "completed",
-		PipelineRun:             pr,
-		Conclusion:              formatting.PipelineRunStatus(pr),
-		Text:                    tmplStatusText,
-		PipelineRunName:         pr.Name,
-		DetailsURL:              r.run.Clients.ConsoleUI.DetailURL(pr),
-		OriginalPipelineRunName: pr.GetAnnotations()[apipac.OriginalPRName],
+	status.Text = formatting.TruncateText(tmplStatusText, vcx.GetConfig().MaxTextSize, consoleURL)
+	return status, nil
+}
+
+// getLastSuccessURL returns the console URL of the last successful run of the
+// same PipelineRun on the same branch, empty when there is none.
+func (r *Reconciler) getLastSuccessURL(ctx context.Context, pr *tektonv1.PipelineRun) string {
+	originalPRName, ok := pr.GetLabels()[apipac.OriginalPRName]
+	if !ok {
+		return ""
 	}
+	labelSelector := fmt.Sprintf("%s=%s,%s=%s",
+		apipac.Repository, pr.GetLabels()[apipac.Repository], apipac.OriginalPRName, originalPRName)
+	pruns, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).List(ctx,
+		metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		logging.FromContext(ctx).Warnf("cannot list the pipelineruns %s to find the last success: %v", labelSelector, err)
+		return ""
+	}
+	branch := pr.GetAnnotations()[apipac.Branch]
+	sorted := sort.PipelineRunSortByCompletionTime(pruns.Items)
+	for i := range sorted {
+		prun := &sorted[i]
+		if prun.GetName() == pr.GetName() || prun.GetAnnotations()[apipac.Branch] != branch {
+			continue
+		}
+		if prun.Status.GetCondition(apis.ConditionSucceeded).IsTrue() {
+			return r.run.Clients.ConsoleUI.DetailURL(prun)
+		}
+	}
+	return ""
+}
 
-	err = createStatusWithRetry(ctx, logger, vcx, event, status)
-	logger.Infof("pipelinerun %s has a status of '%s'", pr.Name, status.Conclusion)
-	return pr, err
+// getFailureReasons returns the reason of the failed PipelineRun followed by
+// the sorted names of its failed tasks.
+func getFailureReasons(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus) []string {
+	reasons := []string{}
+	if cond := pr.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.Reason != "" {
+		reasons = append(reasons, cond.Reason)
+	}
+	failedTasks := []string{}
+	for _, taskrunStatus := range trStatus {
+		if taskrunStatus == nil || taskrunStatus.Status == nil {
+			continue
+		}
+		if cond := taskrunStatus.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.IsFalse() {
+			failedTasks = append(failedTasks, taskrunStatus.PipelineTaskName)
+		}
+	}
+	return append(reasons, formatting.UniqueStringArray(failedTasks)...)
 }
 
-func createStatusWithRetry(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, status provider.StatusOpts) error {
+// getFailedTaskLogURL returns the log URL of the failed TaskRun when only one
+// of them has failed, empty otherwise.
+func getFailedTaskLogURL(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus, console consoleui.Interface) string {
+	var failed *tektonv1.PipelineRunTaskRunStatus
+	for _, taskrunStatus := range trStatus {
+		if taskrunStatus == nil || taskrunStatus.Status == nil {
+			continue
+		}
+		if cond := taskrunStatus.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.IsFalse() {
+			if failed != nil {
+				return ""
+			}
+			failed = taskrunStatus
+		}
+	}
+	if failed == nil {
+		return ""
+	}
+	return console.TaskLogURL(pr, failed)
+}
+
+// getParameters returns the parameters of a PipelineRun as strings, the
+// array values joined by a comma and the object values as sorted key=value
+// pairs.
+func getParameters(pr *tektonv1.PipelineRun) map[string]string {
+	parameters := map[string]string{}
+	for _, param := range pr.Spec.Params {
+		switch param.Value.Type {
+		case tektonv1.ParamTypeArray:
+			parameters[param.Name] = strings.Join(param.Value.ArrayVal, ", ")
+		case tektonv1.ParamTypeObject:
+			pairs := []string{}
+			for key, value := range param.Value.ObjectVal {
+				pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+			}
+			parameters[param.Name] = strings.Join(formatting.UniqueStringArray(pairs), ", ")
+		default:
+			parameters[param.Name] = param.Value.StringVal
+		}
+	}
+	return parameters
+}
+
+// getAnnotations returns the annotations from the provider.AnnotationsResultName
+// result of the tasks, sorted by TaskRun name. Results which are not a valid
+// JSON list of annotations are ignored.
+func getAnnotations(ctx context.Context, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus) []provider.Annotation {
+	names := []string{}
+	for name := range trStatus {
+		names = append(names, name)
+	}
+	annotations := []provider.Annotation{}
+	for _, name := range formatting.UniqueStringArray(names) {
+		taskrunStatus := trStatus[name]
+		if taskrunStatus == nil || taskrunStatus.Status == nil {
+			continue
+		}
+		for _, result := range taskrunStatus.Status.Results {
+			if result.Name != provider.AnnotationsResultName {
+				continue
+			}
+			taskAnnotations := []provider.Annotation{}
+			if err := json.Unmarshal([]byte(result.Value.StringVal), &taskAnnotations); err != nil {
+				logging.FromContext(ctx).Warnf("cannot parse the %s result of taskrun %s: %v", provider.AnnotationsResultName, name, err)
+				continue
+			}
+			for _, annotation := range taskAnnotations {
+				if annotation.Path == "" || annotation.StartLine <= 0 {
+					continue
+				}
+				annotations = append(annotations, annotation)
+			}
+		}
+	}
+	return annotations
+}
+
+// getCoverage returns the coverage reported by the CoverageResultName result
+// of the TaskRuns, the one of the last TaskRun by name when several have
+// reported one.
+func getCoverage(ctx context.Context, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus) *float64 {
+	names := []string{}
+	for name := range trStatus {
+		names = append(names, name)
+	}
+	var coverage *float64
+	for _, name := range formatting.UniqueStringArray(names) {
+		taskrunStatus := trStatus[name]
+		if taskrunStatus == nil || taskrunStatus.Status == nil {
+			continue
+		}
+		for _, result := range taskrunStatus.Status.Results {
+			if result.Name != provider.CoverageResultName {
+				continue
+			}
+			value, err := provider.ParseCoverage(result.Value.StringVal)
+			if err != nil {
+				logging.FromContext(ctx).Warnf("cannot use the %s result of taskrun %s: %v", provider.CoverageResultName, name, err)
+				continue
+			}
+			coverage = &value
+		}
+	}
+	return coverage
+}
+
+func (r *Reconciler) createStatusWithRetry(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, status provider.StatusOpts) error {
 	var finalError error
 	for _, backoff := range backoffSchedule {
 		err := vcx.CreateStatus(ctx, event, status)
+		if merr := r.emitStatusMetrics(status.PipelineRun, status.Conclusion, err != nil); merr != nil {
+			logger.Debugf("cannot record the status update metrics: %v", merr)
+		}
 		if err == nil {
 			return nil
 		}
+		// retrying right away doesn't help when the token is missing or not
+		// allowed to, nor before the rate limit gets reset
+		if errors.Is(err, provider.ErrNoToken) || errors.Is(err, provider.ErrPermission) || errors.Is(err, provider.ErrRateLimited) {
+			return fmt.Errorf("failed to report status: %w", err)
+		}
 		logger.Infof("failed to create status, error: %v, retrying in %v", err, backoff)
 		time.Sleep(backoff)
 		finalError = err
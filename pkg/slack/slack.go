@@ -0,0 +1,146 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// DefaultWebhookSecretKey is the key of the Slack webhook URL in the secret of
+// the Repository when none is set.
+const DefaultWebhookSecretKey = "slack.webhook"
+
+// WebhookHost is the host of the Slack incoming webhooks.
+const WebhookHost = "hooks.slack.com"
+
+// Message is the payload of a Slack incoming webhook.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// conclusionEmojis are the emojis shown before the title of the conclusions
+// of a PipelineRun.
+var conclusionEmojis = map[string]string{
+	"success":         ":white_check_mark:",
+	"failure":         ":x:",
+	"skipped":         ":fast_forward:",
+	"neutral":         ":grey_question:",
+	"cancelled":       ":no_entry_sign:",
+	"timed_out":       ":hourglass:",
+	"action_required": ":raised_hand:",
+}
+
+// conclusionTitle is how the conclusions of a PipelineRun are shown, the
+// titles of the check runs with an emoji.
+func conclusionTitle(conclusion string) string {
+	title := formatting.ConclusionTitle(conclusion)
+	if emoji, ok := conclusionEmojis[conclusion]; ok {
+		return fmt.Sprintf("%s %s", emoji, title)
+	}
+	return title
+}
+
+// PullRequestURL returns the web URL of a pull request of a repository for a
+// git provider (as annotated on the PipelineRuns), empty when there is no pull
+// request.
+func PullRequestURL(gitProvider, repoURL string, number int) string {
+	if number == 0 || repoURL == "" {
+		return ""
+	}
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	switch gitProvider {
+	case "gitlab":
+		return fmt.Sprintf("%s/-/merge_requests/%d", repoURL, number)
+	case "bitbucket-cloud", "bitbucket-server":
+		return fmt.Sprintf("%s/pull-requests/%d", repoURL, number)
+	}
+	return fmt.Sprintf("%s/pull/%d", repoURL, number)
+}
+
+// FormatMessage formats the message of a completed PipelineRun with its
+// repository, pull request, conclusion and failed tasks.
+func FormatMessage(event *info.Event, status provider.StatusOpts, pullRequestURL string) Message {
+	name := status.PipelineRunName
+	if status.DetailsURL != "" {
+		name = fmt.Sprintf("<%s|%s>", status.DetailsURL, name)
+	}
+	repo := fmt.Sprintf("%s/%s", event.Organization, event.Repository)
+	if event.URL != "" {
+		repo = fmt.Sprintf("<%s|%s>", event.URL, repo)
+	}
+
+	lines := []string{fmt.Sprintf("*%s*: PipelineRun %s on %s", conclusionTitle(status.Conclusion), name, repo)}
+	switch {
+	case pullRequestURL != "":
+		lines = append(lines, fmt.Sprintf("Pull request: <%s|#%d>", pullRequestURL, event.PullRequestNumber))
+	case event.SHA != "":
+		lines = append(lines, fmt.Sprintf("Commit: %s", event.SHA))
+	}
+	if tasks := formatting.FailedTaskNames(status.TaskRunStatuses); len(tasks) > 0 {
+		lines = append(lines, fmt.Sprintf("Failed tasks: %s", strings.Join(tasks, ", ")))
+	}
+	return Message{Text: strings.Join(lines, "\n")}
+}
+
+// ValidateWebhookURL checks the webhook URL is an https URL of Slack or of one
+// of the allowedHosts of the admin, so a Repository can't make the controller
+// post to any other service. The webhook URL is a secret and is never part of
+// the returned error.
+func ValidateWebhookURL(webhookURL string, allowedHosts []string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid slack webhook url")
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("the slack webhook url is not an https url")
+	}
+	host := u.Hostname()
+	if host == WebhookHost {
+		return nil
+	}
+	for _, allowed := range allowedHosts {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && strings.EqualFold(host, allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the host of the slack webhook url is not %s or allowed by the slack-webhook-allowed-hosts setting", WebhookHost)
+}
+
+// Post sends a message to a Slack incoming webhook, the webhook URL is a
+// secret and is never part of the returned errors.
+func Post(ctx context.Context, client *http.Client, webhookURL string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid slack webhook url")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		// the error of the client has the URL of the request in it
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("cannot post to the slack webhook: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		reply, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("slack webhook returned %d: %s", res.StatusCode, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
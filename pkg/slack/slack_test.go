@@ -0,0 +1,193 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func taskRunStatus(name string, status corev1.ConditionStatus) *tektonv1.PipelineRunTaskRunStatus {
+	return &tektonv1.PipelineRunTaskRunStatus{
+		PipelineTaskName: name,
+		Status: &tektonv1.TaskRunStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}}},
+		},
+	}
+}
+
+func TestPullRequestURL(t *testing.T) {
+	tests := []struct {
+		gitProvider string
+		repoURL     string
+		number      int
+		want        string
+	}{
+		{gitProvider: "github", repoURL: "https://github.com/owner/repo", number: 1, want: "https://github.com/owner/repo/pull/1"},
+		{gitProvider: "gitea", repoURL: "https://gitea.com/owner/repo/", number: 2, want: "https://gitea.com/owner/repo/pull/2"},
+		{gitProvider: "gitlab", repoURL: "https://gitlab.com/group/sub/repo", number: 3, want: "https://gitlab.com/group/sub/repo/-/merge_requests/3"},
+		{gitProvider: "bitbucket-cloud", repoURL: "https://bitbucket.org/owner/repo", number: 4, want: "https://bitbucket.org/owner/repo/pull-requests/4"},
+		{gitProvider: "github", repoURL: "https://github.com/owner/repo", number: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, PullRequestURL(tt.gitProvider, tt.repoURL, tt.number), tt.want)
+		})
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	event := &info.Event{
+		Organization:      "owner",
+		Repository:        "repo",
+		URL:               "https://github.com/owner/repo",
+		SHA:               "sha",
+		PullRequestNumber: 42,
+	}
+	tests := []struct {
+		name           string
+		status         provider.StatusOpts
+		pullRequestURL string
+		want           string
+	}{
+		{
+			name: "failure of a pull request",
+			status: provider.StatusOpts{
+				Conclusion:      "failure",
+				PipelineRunName: "pr-abcde",
+				DetailsURL:      "https://console/pr-abcde",
+				TaskRunStatuses: map[string]*tektonv1.PipelineRunTaskRunStatus{
+					"pr-abcde-lint":  taskRunStatus("lint", corev1.ConditionFalse),
+					"pr-abcde-build": taskRunStatus("build", corev1.ConditionFalse),
+					"pr-abcde-clone": taskRunStatus("clone", corev1.ConditionTrue),
+				},
+			},
+			pullRequestURL: "https://github.com/owner/repo/pull/42",
+			want: "*:x: Failed*: PipelineRun <https://console/pr-abcde|pr-abcde> on <https://github.com/owner/repo|owner/repo>\n" +
+				"Pull request: <https://github.com/owner/repo/pull/42|#42>\n" +
+				"Failed tasks: build, lint",
+		},
+		{
+			name: "success of a push",
+			status: provider.StatusOpts{
+				Conclusion:      "success",
+				PipelineRunName: "push-abcde",
+				TaskRunStatuses: map[string]*tektonv1.PipelineRunTaskRunStatus{
+					"push-abcde-clone": taskRunStatus("clone", corev1.ConditionTrue),
+				},
+			},
+			want: "*:white_check_mark: Success*: PipelineRun push-abcde on <https://github.com/owner/repo|owner/repo>\n" +
+				"Commit: sha",
+		},
+		{
+			name: "cancelled",
+			status: provider.StatusOpts{
+				Conclusion:      "cancelled",
+				PipelineRunName: "push-abcde",
+			},
+			want: "*:no_entry_sign: Cancelled*: PipelineRun push-abcde on <https://github.com/owner/repo|owner/repo>\n" +
+				"Commit: sha",
+		},
+		{
+			name: "unknown conclusion",
+			status: provider.StatusOpts{
+				Conclusion:      "stale",
+				PipelineRunName: "push-abcde",
+			},
+			want: "*stale*: PipelineRun push-abcde on <https://github.com/owner/repo|owner/repo>\n" +
+				"Commit: sha",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, FormatMessage(event, tt.status, tt.pullRequestURL).Text, tt.want)
+		})
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got Message
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/ok", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&got))
+	})
+	mux.HandleFunc("/services/invalid", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("no_service"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	assert.NilError(t, Post(ctx, server.Client(), server.URL+"/services/ok", Message{Text: "hello"}))
+	assert.Equal(t, got.Text, "hello")
+
+	err := Post(ctx, server.Client(), server.URL+"/services/invalid", Message{Text: "hello"})
+	assert.Error(t, err, "slack webhook returned 404: no_service")
+
+	err = Post(ctx, server.Client(), "http://127.0.0.1:1/services/secret", Message{Text: "hello"})
+	assert.ErrorContains(t, err, "cannot post to the slack webhook")
+	assert.Assert(t, !strings.Contains(err.Error(), "secret"), "the webhook url should not be in the error")
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		webhookURL   string
+		allowedHosts []string
+		wantErr      string
+	}{
+		{
+			name:       "slack",
+			webhookURL: "https://hooks.slack.com/services/T000/B000/secret",
+		},
+		{
+			name:         "allowed host",
+			webhookURL:   "https://Slack.Proxy.Corp:8443/services/secret",
+			allowedHosts: []string{"", " slack.proxy.corp"},
+		},
+		{
+			name:       "not https",
+			webhookURL: "http://hooks.slack.com/services/secret",
+			wantErr:    "the slack webhook url is not an https url",
+		},
+		{
+			name:       "another host",
+			webhookURL: "https://169.254.169.254/services/secret",
+			wantErr:    "the host of the slack webhook url is not hooks.slack.com or allowed by the slack-webhook-allowed-hosts setting",
+		},
+		{
+			name:         "subdomain of an allowed host",
+			webhookURL:   "https://hooks.slack.com.evil.example/services/secret",
+			allowedHosts: []string{"slack.proxy.corp"},
+			wantErr:      "the host of the slack webhook url is not hooks.slack.com or allowed by the slack-webhook-allowed-hosts setting",
+		},
+		{
+			name:       "invalid url",
+			webhookURL: "https://hooks.slack.com/%zz/secret",
+			wantErr:    "invalid slack webhook url",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookURL(tt.webhookURL, tt.allowedHosts)
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
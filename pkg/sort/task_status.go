@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type tkr struct {
-	taskLogURL string
+	taskLogURL       string
+	resourceRequests corev1.ResourceList
+	podEvent         string
+	podEventsURL     string
 	*tektonv1.PipelineRunTaskRunStatus
 }
 
@@ -25,6 +30,34 @@ func (t tkr) ConsoleLogURL() string {
 	return fmt.Sprintf("[%s](%s)", name, t.taskLogURL)
 }
 
+// ResourceRequests returns the resource requests of the TaskRun pod, i.e:
+// "cpu: 500m, memory: 256Mi" or "-" if the TaskRun has no pod.
+func (t tkr) ResourceRequests() string {
+	if len(t.resourceRequests) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(t.resourceRequests))
+	for name := range t.resourceRequests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := t.resourceRequests[corev1.ResourceName(name)]
+		parts = append(parts, fmt.Sprintf("%s: %s", name, quantity.String()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PodEvent returns the pod event explaining why the TaskRun has failed before
+// running, with a link to the pod events, or an empty string.
+func (t tkr) PodEvent() string {
+	if t.podEvent == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s ([pod events](%s))", t.podEvent, t.podEventsURL)
+}
+
 type taskrunList []tkr
 
 func (trs taskrunList) Len() int      { return len(trs) }
@@ -41,8 +74,92 @@ func (trs taskrunList) Less(i, j int) bool {
 	return trs[j].Status.StartTime.Before(trs[i].Status.StartTime)
 }
 
+// summary returns a one line summary of the TaskRuns conditions, i.e: "5
+// succeeded, 1 failed".
+func (trs taskrunList) summary() string {
+	var succeeded, failed, running, pending int
+	for _, tr := range trs {
+		if tr.Status == nil || len(tr.Status.Conditions) == 0 {
+			pending++
+			continue
+		}
+		switch tr.Status.Conditions[0].Status {
+		case corev1.ConditionTrue:
+			succeeded++
+		case corev1.ConditionFalse:
+			failed++
+		default:
+			running++
+		}
+	}
+
+	parts := []string{}
+	for _, count := range []struct {
+		number int
+		label  string
+	}{
+		{succeeded, "succeeded"},
+		{failed, "failed"},
+		{running, "running"},
+		{pending, "pending"},
+	} {
+		if count.number > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.number, count.label))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// failuresOnly returns only the TaskRuns that have not succeeded and how many
+// have succeeded, if there is no failed TaskRuns all of them are returned.
+func (trs taskrunList) failuresOnly() (taskrunList, int) {
+	failed := taskrunList{}
+	for _, tr := range trs {
+		if tr.Status != nil && len(tr.Status.Conditions) > 0 && tr.Status.Conditions[0].Status == corev1.ConditionTrue {
+			continue
+		}
+		failed = append(failed, tr)
+	}
+	if len(failed) == 0 {
+		return trs, 0
+	}
+	return failed, len(trs) - len(failed)
+}
+
+// formatConditionSkippedTasks renders the tasks skipped because of their when
+// expressions, they have no TaskRun to be shown in the table.
+func formatConditionSkippedTasks(pr *tektonv1.PipelineRun, skipEmoji bool) string {
+	marker := "⏭️ Skipped (condition)"
+	if skipEmoji {
+		marker = "Skipped (condition)"
+	}
+	lines := []string{}
+	for _, skipped := range pr.Status.SkippedTasks {
+		if skipped.Reason != tektonv1.WhenExpressionsSkip {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s <b>%s</b>: %s", marker, skipped.Name, skipped.Reason))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// TaskStatusOpts are the options on how to render the TaskRuns status.
+type TaskStatusOpts struct {
+	// FailuresOnly only renders the TaskRuns that have not succeeded.
+	FailuresOnly bool
+	// ShowResourceRequests adds a column with the resource requests of every
+	// TaskRun, taken from ResourceRequests.
+	ShowResourceRequests bool
+	// ResourceRequests are the resource requests of the TaskRun pods by
+	// TaskRun name.
+	ResourceRequests map[string]corev1.ResourceList
+	// PodEvents are the pod events explaining why the TaskRuns have failed
+	// before running by TaskRun name.
+	PodEvents map[string]string
+}
+
 // TaskStatusTmpl generate a template of all status of a TaskRuns sorted to a statusTemplate as defined by the git provider.
-func TaskStatusTmpl(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus, runs *params.Run, config *info.ProviderConfig) (string, error) {
+func TaskStatusTmpl(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.PipelineRunTaskRunStatus, runs *params.Run, config *info.ProviderConfig, opts TaskStatusOpts) (string, error) {
 	trl := taskrunList{}
 	outputBuffer := bytes.Buffer{}
 
@@ -50,16 +167,26 @@ func TaskStatusTmpl(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.Pipe
 		return "PipelineRun has no taskruns", nil
 	}
 
-	for _, taskrunStatus := range trStatus {
+	for name, taskrunStatus := range trStatus {
 		trl = append(trl, tkr{
 			taskLogURL:               runs.Clients.ConsoleUI.TaskLogURL(pr, taskrunStatus),
+			resourceRequests:         opts.ResourceRequests[name],
+			podEvent:                 opts.PodEvents[name],
+			podEventsURL:             runs.Clients.ConsoleUI.PodEventsURL(pr, taskrunStatus),
 			PipelineRunTaskRunStatus: taskrunStatus,
 		})
 	}
 	sort.Sort(sort.Reverse(trl))
 
+	// keep the whole list for the summary line of the collapsed table
+	allTrl := trl
+	succeeded := 0
+	if opts.FailuresOnly {
+		trl, succeeded = trl.failuresOnly()
+	}
+
 	funcMap := template.FuncMap{
-		"formatDuration":  formatting.Duration,
+		"formatDuration":  formatting.TaskDuration,
 		"formatCondition": formatting.ConditionEmoji,
 	}
 
@@ -67,12 +194,24 @@ func TaskStatusTmpl(pr *tektonv1.PipelineRun, trStatus map[string]*tektonv1.Pipe
 		funcMap["formatCondition"] = formatting.ConditionSad
 	}
 
-	data := struct{ TaskRunList taskrunList }{TaskRunList: trl}
+	data := struct {
+		TaskRunList          taskrunList
+		ShowResourceRequests bool
+	}{TaskRunList: trl, ShowResourceRequests: opts.ShowResourceRequests}
 	t := template.Must(template.New("Task Status").Funcs(funcMap).Parse(config.TaskStatusTMPL))
 	if err := t.Execute(&outputBuffer, data); err != nil {
 		_, _ = fmt.Fprintf(&outputBuffer, "failed to execute template: ")
 		return "", err
 	}
+	if succeeded > 0 {
+		_, _ = fmt.Fprintf(&outputBuffer, "\n\n(%d other tasks succeeded)", succeeded)
+	}
+	if skipped := formatConditionSkippedTasks(pr, config.SkipEmoji); skipped != "" {
+		_, _ = fmt.Fprintf(&outputBuffer, "\n\n%s", skipped)
+	}
 
+	if runs.Info.Pac != nil && runs.Info.Pac.Settings != nil && runs.Info.Pac.CollapseTaskStatus {
+		return fmt.Sprintf("<details>\n<summary>%s</summary>\n\n%s\n</details>", allTrl.summary(), outputBuffer.String()), nil
+	}
 	return outputBuffer.String(), nil
 }
@@ -2,6 +2,7 @@ package sort
 
 import (
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
@@ -10,6 +11,8 @@ import (
 	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestStatusTmpl(t *testing.T) {
@@ -76,7 +79,7 @@ func TestStatusTmpl(t *testing.T) {
 			runs := params.New()
 			runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
 			pr := &tektonv1.PipelineRun{}
-			output, err := TaskStatusTmpl(pr, tt.prTaskRunStatus, runs, config)
+			output, err := TaskStatusTmpl(pr, tt.prTaskRunStatus, runs, config, TaskStatusOpts{})
 			if tt.wantErr {
 				assert.Assert(t, err != nil)
 				return
@@ -88,3 +91,206 @@ func TestStatusTmpl(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusTmplCollapsed(t *testing.T) {
+	failed := tektontest.MakePrTrStatus("failed", "", 10)
+	failed.Status.Conditions[0].Status = corev1.ConditionFalse
+	prTaskRunStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"first":  tektontest.MakePrTrStatus("first", "", 5),
+		"second": tektontest.MakePrTrStatus("second", "", 15),
+		"failed": failed,
+	}
+	tests := []struct {
+		name     string
+		collapse bool
+		want     string
+	}{
+		{
+			name:     "collapsed",
+			collapse: true,
+			want:     "<details>\n<summary>2 succeeded, 1 failed</summary>\n\n<table>",
+		},
+		{
+			name: "not collapsed by default",
+			want: "<table>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &info.ProviderConfig{
+				TaskStatusTMPL: `<table>{{- range $taskrun := .TaskRunList }}{{- $taskrun.ConsoleLogURL }}{{- end }}</table>`,
+			}
+			runs := params.New()
+			runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
+			runs.Info.Pac.CollapseTaskStatus = tt.collapse
+			output, err := TaskStatusTmpl(&tektonv1.PipelineRun{}, prTaskRunStatus, runs, config, TaskStatusOpts{})
+			assert.NilError(t, err)
+			assert.Assert(t, strings.HasPrefix(output, tt.want), output)
+			if tt.collapse {
+				assert.Assert(t, strings.HasSuffix(output, "</table>\n</details>"), output)
+			}
+		})
+	}
+}
+
+func TestStatusTmplFailuresOnly(t *testing.T) {
+	failed := tektontest.MakePrTrStatus("failed", "", 10)
+	failed.Status.Conditions[0].Status = corev1.ConditionFalse
+	tests := []struct {
+		name            string
+		failuresOnly    bool
+		prTaskRunStatus map[string]*tektonv1.PipelineRunTaskRunStatus
+		want            string
+	}{
+		{
+			name:         "only failed tasks",
+			failuresOnly: true,
+			prTaskRunStatus: map[string]*tektonv1.PipelineRunTaskRunStatus{
+				"first":  tektontest.MakePrTrStatus("first", "", 5),
+				"second": tektontest.MakePrTrStatus("second", "", 15),
+				"failed": failed,
+			},
+			want: "❌ Failed failed\n\n(2 other tasks succeeded)",
+		},
+		{
+			name: "all tasks when not enabled",
+			prTaskRunStatus: map[string]*tektonv1.PipelineRunTaskRunStatus{
+				"first":  tektontest.MakePrTrStatus("first", "", 5),
+				"failed": failed,
+			},
+			want: "✅ Succeeded first❌ Failed failed",
+		},
+		{
+			name:         "all tasks when none has failed",
+			failuresOnly: true,
+			prTaskRunStatus: map[string]*tektonv1.PipelineRunTaskRunStatus{
+				"first":  tektontest.MakePrTrStatus("first", "", 5),
+				"second": tektontest.MakePrTrStatus("second", "", 15),
+			},
+			want: "✅ Succeeded first✅ Succeeded second",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &info.ProviderConfig{
+				TaskStatusTMPL: `{{- range $taskrun := .TaskRunList }}{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }} {{ $taskrun.PipelineTaskName }}{{- end }}`,
+			}
+			runs := params.New()
+			runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
+			output, err := TaskStatusTmpl(&tektonv1.PipelineRun{}, tt.prTaskRunStatus, runs, config, TaskStatusOpts{FailuresOnly: tt.failuresOnly})
+			assert.NilError(t, err)
+			assert.Equal(t, output, tt.want)
+		})
+	}
+}
+
+func TestStatusTmplConditionSkipped(t *testing.T) {
+	pr := &tektonv1.PipelineRun{
+		Status: tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				SkippedTasks: []tektonv1.SkippedTask{
+					{Name: "deploy", Reason: tektonv1.WhenExpressionsSkip},
+					{Name: "notify", Reason: tektonv1.ParentTasksSkip},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		skipEmoji bool
+		want      string
+	}{
+		{
+			name: "skipped by a when expression",
+			want: "✅ Succeeded build\n\n⏭️ Skipped (condition) <b>deploy</b>: When Expressions evaluated to false",
+		},
+		{
+			name:      "without emoji",
+			skipEmoji: true,
+			want:      "Succeeded build\n\nSkipped (condition) <b>deploy</b>: When Expressions evaluated to false",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &info.ProviderConfig{
+				TaskStatusTMPL: `{{- range $taskrun := .TaskRunList }}{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }} {{ $taskrun.PipelineTaskName }}{{- end }}`,
+				SkipEmoji:      tt.skipEmoji,
+			}
+			runs := params.New()
+			runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
+			prTaskRunStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+				"build": tektontest.MakePrTrStatus("build", "", 5),
+			}
+			output, err := TaskStatusTmpl(pr, prTaskRunStatus, runs, config, TaskStatusOpts{})
+			assert.NilError(t, err)
+			assert.Equal(t, output, tt.want)
+		})
+	}
+}
+
+func TestStatusTmplResourceRequests(t *testing.T) {
+	prTaskRunStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"first":  tektontest.MakePrTrStatus("first", "", 5),
+		"second": tektontest.MakePrTrStatus("second", "", 15),
+	}
+	resourceRequests := map[string]corev1.ResourceList{
+		"first": {
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+		},
+	}
+	tmpl := `<tr><th>Name</th>{{- if .ShowResourceRequests }}<th>Resource Requests</th>{{- end }}</tr>` +
+		`{{- range $taskrun := .TaskRunList }}<tr><td>{{ $taskrun.PipelineTaskName }}</td>` +
+		`{{- if $.ShowResourceRequests }}<td>{{ $taskrun.ResourceRequests }}</td>{{- end }}</tr>{{- end }}`
+	tests := []struct {
+		name string
+		opts TaskStatusOpts
+		want string
+	}{
+		{
+			name: "resource requests column",
+			opts: TaskStatusOpts{ShowResourceRequests: true, ResourceRequests: resourceRequests},
+			want: "<tr><th>Name</th><th>Resource Requests</th></tr>" +
+				"<tr><td>first</td><td>cpu: 500m, memory: 256Mi</td></tr>" +
+				"<tr><td>second</td><td>-</td></tr>",
+		},
+		{
+			name: "no resource requests column by default",
+			opts: TaskStatusOpts{ResourceRequests: resourceRequests},
+			want: "<tr><th>Name</th></tr><tr><td>first</td></tr><tr><td>second</td></tr>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &info.ProviderConfig{TaskStatusTMPL: tmpl}
+			runs := params.New()
+			runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
+			output, err := TaskStatusTmpl(&tektonv1.PipelineRun{}, prTaskRunStatus, runs, config, tt.opts)
+			assert.NilError(t, err)
+			assert.Equal(t, output, tt.want)
+		})
+	}
+}
+
+func TestStatusTmplPodEvents(t *testing.T) {
+	unschedulable := tektontest.MakePrTrStatus("unschedulable", "", 5)
+	unschedulable.Status.Conditions[0].Status = corev1.ConditionFalse
+	unschedulable.Status.PodName = "unschedulable-pod"
+	prTaskRunStatus := map[string]*tektonv1.PipelineRunTaskRunStatus{
+		"first":         tektontest.MakePrTrStatus("first", "", 10),
+		"unschedulable": unschedulable,
+	}
+	config := &info.ProviderConfig{
+		TaskStatusTMPL: `{{- range $taskrun := .TaskRunList }}<td>{{ $taskrun.PipelineTaskName }}{{- with $taskrun.PodEvent }}<br>{{ . }}{{- end }}</td>{{- end }}`,
+	}
+	runs := params.New()
+	runs.Clients.ConsoleUI = consoleui.FallBackConsole{}
+	output, err := TaskStatusTmpl(&tektonv1.PipelineRun{}, prTaskRunStatus, runs, config, TaskStatusOpts{
+		PodEvents: map[string]string{
+			"unschedulable": "FailedScheduling: 0/3 nodes are available: 3 Insufficient cpu.",
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, output, "<td>unschedulable<br>FailedScheduling: 0/3 nodes are available: 3 Insufficient cpu. ([pod events](https://dashboard.is.not.configured))</td>"+
+		"<td>first</td>")
+}
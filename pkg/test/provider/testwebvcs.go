@@ -21,6 +21,7 @@ type TestProviderImp struct {
 	Event                  *info.Event
 	TektonDirTemplate      string
 	CreateStatusErorring   bool
+	CreateStatusError      error
 	FilesInsideRepo        map[string]string
 	WantProviderRemoteTask bool
 	PolicyDisallowing      bool
@@ -30,6 +31,19 @@ type TestProviderImp struct {
 	WantDeletedFiles       []string
 	WantModifiedFiles      []string
 	WantRenamedFiles       []string
+	CreatedStatuses        []provider.StatusOpts
+	TaskStatusTMPL         string
+	CancelledPreviousSHAs  []string
+	CheckRuns              bool
+}
+
+func (v *TestProviderImp) CancelInProgressCheckRuns(_ context.Context, event *info.Event) error {
+	v.CancelledPreviousSHAs = append(v.CancelledPreviousSHAs, event.PreviousSHA)
+	return nil
+}
+
+func (v *TestProviderImp) ReportsCheckRuns(_ *info.Event) bool {
+	return v.CheckRuns
 }
 
 func (v *TestProviderImp) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
@@ -59,7 +73,7 @@ func (v *TestProviderImp) ParsePayload(_ context.Context, _ *params.Run, _ *http
 }
 
 func (v *TestProviderImp) GetConfig() *info.ProviderConfig {
-	return &info.ProviderConfig{}
+	return &info.ProviderConfig{TaskStatusTMPL: v.TaskStatusTMPL}
 }
 
 func (v *TestProviderImp) GetCommitInfo(_ context.Context, _ *info.Event) error {
@@ -81,10 +95,14 @@ func (v *TestProviderImp) GetTaskURI(_ context.Context, _ *info.Event, _ string)
 	return v.WantProviderRemoteTask, "", nil
 }
 
-func (v *TestProviderImp) CreateStatus(_ context.Context, _ *info.Event, _ provider.StatusOpts) error {
+func (v *TestProviderImp) CreateStatus(_ context.Context, _ *info.Event, opts provider.StatusOpts) error {
 	if v.CreateStatusErorring {
 		return fmt.Errorf("some provider error occurred while reporting status")
 	}
+	if v.CreateStatusError != nil {
+		return v.CreateStatusError
+	}
+	v.CreatedStatuses = append(v.CreatedStatuses, opts)
 	return nil
 }
 